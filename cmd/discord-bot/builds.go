@@ -0,0 +1,161 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"flavaflav/internal/db"
+	"flavaflav/internal/models"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+var buildMemberOption = &discordgo.ApplicationCommandOption{
+	Type:        discordgo.ApplicationCommandOptionUser,
+	Name:        "member",
+	Description: "Member to look up or, for Maesters, update",
+	Required:    false,
+}
+
+var buildCommand = &discordgo.ApplicationCommand{
+	Name:        "build",
+	Description: "Tag how you play so draws can match you with links that suit you",
+	Options: []*discordgo.ApplicationCommandOption{
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "set",
+			Description: "Replace your build tags",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "tags",
+					Description: "Tags separated by spaces or commas: " + strings.Join(models.AllBuildTags, ", "),
+					Required:    true,
+				},
+				buildMemberOption,
+			},
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "clear",
+			Description: "Remove your build tags",
+			Options:     []*discordgo.ApplicationCommandOption{buildMemberOption},
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "show",
+			Description: "Show your build tags, or another member's",
+			Options:     []*discordgo.ApplicationCommandOption{buildMemberOption},
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "links",
+			Description: "List the link types that suit a build",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "tag",
+					Description: "Build tag",
+					Required:    true,
+					Choices:     buildTagChoices(),
+				},
+			},
+		},
+	},
+}
+
+func buildTagChoices() []*discordgo.ApplicationCommandOptionChoice {
+	choices := make([]*discordgo.ApplicationCommandOptionChoice, len(models.AllBuildTags))
+	for i, tag := range models.AllBuildTags {
+		choices[i] = &discordgo.ApplicationCommandOptionChoice{Name: strings.Title(tag), Value: tag}
+	}
+	return choices
+}
+
+func handleBuild(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) {
+	sub := i.ApplicationCommandData().Options[0]
+	options := optionMap(sub.Options)
+
+	if sub.Name == "links" {
+		handleBuildLinks(s, i, options["tag"].StringValue())
+		return
+	}
+
+	userID := i.Member.User.ID
+	if opt, ok := options["member"]; ok {
+		userID = opt.UserValue(s).ID
+	}
+	if sub.Name != "show" && userID != i.Member.User.ID && !isMaester(s, i.Member) {
+		respondError(s, i, "Only Maesters can change another member's build tags.")
+		return
+	}
+
+	member, err := dbClient.GetMember(ctx, userID)
+	if err != nil {
+		if userID == i.Member.User.ID {
+			respondError(s, i, "You are not registered as a guild member. Contact a Maester to be added.")
+		} else {
+			respondError(s, i, "That member is not registered")
+		}
+		return
+	}
+
+	if sub.Name != "show" {
+		var tags []string
+		if sub.Name == "set" {
+			tags = strings.FieldsFunc(options["tags"].StringValue(), func(r rune) bool {
+				return r == ',' || r == ' '
+			})
+		}
+		if err := member.SetBuildTags(tags); err != nil {
+			respondError(s, i, err.Error())
+			return
+		}
+		err = dbClient.UpdateMemberBuildTags(ctx, member)
+		if errors.Is(err, db.ErrMemberNotFound) {
+			respondError(s, i, "That member is no longer registered")
+			return
+		}
+		if err != nil {
+			respondError(s, i, "Failed to save build tags")
+			return
+		}
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title: "Build of " + member.Username,
+		Color: getRankColor(member.Rank),
+	}
+	if len(member.BuildTags) == 0 {
+		embed.Description = "No build tags set. Draws that match builds only offer this member links that suit any build. Add tags with /build set."
+	} else {
+		embed.Description = member.DescribeBuildTags()
+	}
+	respondEmbed(s, i, embed)
+}
+
+func handleBuildLinks(s *discordgo.Session, i *discordgo.InteractionCreate, tag string) {
+	linkTypes := models.BuildTagLinkTypes(tag)
+
+	embed := &discordgo.MessageEmbed{
+		Title:  fmt.Sprintf("Links for %s builds", strings.Title(tag)),
+		Color:  0x00ff00,
+		Footer: &discordgo.MessageEmbedFooter{Text: "Slayer, resistance and other general links suit every build."},
+	}
+	if len(linkTypes) == 0 {
+		embed.Description = "No link types are specific to this build."
+	} else {
+		embed.Description = truncate(strings.Join(linkTypes, "\n"), 4096)
+	}
+	respondEmbed(s, i, embed)
+}
+
+// buildField shows a member's build tags, or nil if they have set none
+func buildField(member *models.Member) *discordgo.MessageEmbedField {
+	if len(member.BuildTags) == 0 {
+		return nil
+	}
+	return &discordgo.MessageEmbedField{Name: "Build", Value: member.DescribeBuildTags(), Inline: true}
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"errors"
+
+	"flavaflav/internal/db"
+	"flavaflav/internal/models"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+var characterNameOption = &discordgo.ApplicationCommandOption{
+	Type:        discordgo.ApplicationCommandOptionString,
+	Name:        "name",
+	Description: "In-game character name",
+	Required:    true,
+}
+
+var charactersCommand = &discordgo.ApplicationCommand{
+	Name:        "characters",
+	Description: "Register the in-game characters you want links traded to",
+	Options: []*discordgo.ApplicationCommandOption{
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "add",
+			Description: "Register a character",
+			Options: []*discordgo.ApplicationCommandOption{
+				characterNameOption,
+				{
+					Type:        discordgo.ApplicationCommandOptionBoolean,
+					Name:        "primary",
+					Description: "Send links to this character by default",
+					Required:    false,
+				},
+			},
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "remove",
+			Description: "Unregister a character",
+			Options:     []*discordgo.ApplicationCommandOption{characterNameOption},
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "primary",
+			Description: "Choose the character links are sent to by default",
+			Options:     []*discordgo.ApplicationCommandOption{characterNameOption},
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "list",
+			Description: "Show your characters, or another member's",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionUser,
+					Name:        "member",
+					Description: "Member to look up",
+					Required:    false,
+				},
+			},
+		},
+	},
+}
+
+func handleCharacters(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) {
+	sub := i.ApplicationCommandData().Options[0]
+	options := optionMap(sub.Options)
+
+	userID := i.Member.User.ID
+	if opt, ok := options["member"]; ok {
+		userID = opt.UserValue(s).ID
+	}
+
+	member, err := dbClient.GetMember(ctx, userID)
+	if err != nil {
+		if userID == i.Member.User.ID {
+			respondError(s, i, "You are not registered as a guild member. Contact a Maester to be added.")
+		} else {
+			respondError(s, i, "That member is not registered")
+		}
+		return
+	}
+
+	if sub.Name != "list" {
+		name := options["name"].StringValue()
+		switch sub.Name {
+		case "add":
+			primary := false
+			if opt, ok := options["primary"]; ok {
+				primary = opt.BoolValue()
+			}
+			err = member.AddCharacter(name, primary)
+		case "remove":
+			err = member.RemoveCharacter(name)
+		case "primary":
+			err = member.SetPrimaryCharacter(name)
+		}
+		if err != nil {
+			respondError(s, i, err.Error())
+			return
+		}
+		err = dbClient.UpdateMemberCharacters(ctx, member)
+		if errors.Is(err, db.ErrMemberNotFound) {
+			respondError(s, i, "That member is no longer registered")
+			return
+		}
+		if err != nil {
+			respondError(s, i, "Failed to save characters")
+			return
+		}
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title: "Characters of " + member.Username,
+		Color: getRankColor(member.Rank),
+	}
+	if len(member.Characters) == 0 {
+		embed.Description = "No characters registered. Add one with /characters add."
+	} else {
+		embed.Description = member.DescribeCharacters()
+		embed.Footer = &discordgo.MessageEmbedFooter{Text: "Links are traded to the primary character."}
+	}
+	respondEmbed(s, i, embed)
+}
+
+// charactersField shows where a member's links should be traded, or nil if
+// they have registered no characters
+func charactersField(name string, member *models.Member) *discordgo.MessageEmbedField {
+	if len(member.Characters) == 0 {
+		return nil
+	}
+	return &discordgo.MessageEmbedField{Name: name, Value: member.DescribeCharacters()}
+}
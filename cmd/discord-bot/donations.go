@@ -0,0 +1,174 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"flavaflav/internal/models"
+	"flavaflav/internal/stats"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// donorBonus gives donors extra entries in draws, set from the DONOR_BONUS_*
+// variables at startup
+var donorBonus models.DonorBonus
+
+var donorOption = &discordgo.ApplicationCommandOption{
+	Type:        discordgo.ApplicationCommandOptionUser,
+	Name:        "donor",
+	Description: "Member who donated the links",
+	Required:    false,
+}
+
+var donationsCommand = &discordgo.ApplicationCommand{
+	Name:        "donations",
+	Description: "See who has donated the most links, or one member's donations",
+	Options: []*discordgo.ApplicationCommandOption{
+		periodOption,
+		{
+			Type:        discordgo.ApplicationCommandOptionUser,
+			Name:        "member",
+			Description: "Show this member's donations instead of the leaderboard",
+			Required:    false,
+		},
+	},
+}
+
+func handleDonations(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) {
+	options := optionMap(i.ApplicationCommandData().Options)
+	period, err := periodFromOptions(options)
+	if err != nil {
+		respondError(s, i, err.Error())
+		return
+	}
+
+	links, err := dbClient.GetAllInventoryLinks(ctx)
+	if err != nil {
+		respondError(s, i, "Failed to get inventory")
+		return
+	}
+
+	if opt, ok := options["member"]; ok {
+		respondEmbed(s, i, memberDonationsEmbed(opt.UserValue(s), links))
+		return
+	}
+
+	board := stats.DonationLeaderboard(links, period, "")
+	embed := &discordgo.MessageEmbed{
+		Title:  "🎁 Donation Leaderboard",
+		Color:  0x9b59b6,
+		Footer: &discordgo.MessageEmbedFooter{Text: "Period: " + period.Name + donorBonusNote()},
+	}
+
+	if len(board) == 0 {
+		embed.Description = "No donations recorded in this period"
+	} else {
+		var lines []string
+		for rank, entry := range board {
+			if rank == leaderboardSize {
+				break
+			}
+			lines = append(lines, fmt.Sprintf("**%d.** %s: %d %s", rank+1, entry.MemberUsername, entry.Total, formatQualityCounts(entry.ByQuality)))
+		}
+		embed.Description = strings.Join(lines, "\n")
+	}
+
+	respondEmbed(s, i, embed)
+}
+
+func memberDonationsEmbed(user *discordgo.User, links []*models.InventoryLink) *discordgo.MessageEmbed {
+	donated := stats.DonationsBy(links, user.ID)
+	embed := &discordgo.MessageEmbed{
+		Title: "🎁 Donations by " + user.Username,
+		Color: 0x9b59b6,
+	}
+	if len(donated) == 0 {
+		embed.Description = "No donations recorded"
+		return embed
+	}
+
+	counts := make(map[string]int)
+	for _, link := range donated {
+		counts[link.Quality]++
+	}
+	embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+		Name:   "Links Donated",
+		Value:  fmt.Sprintf("%d %s", len(donated), formatQualityCounts(counts)),
+		Inline: true,
+	})
+	if donorBonus.Enabled() {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:   "Bonus Draw Entries",
+			Value:  fmt.Sprintf("%d", donorBonus.Entries(links, time.Now())[user.ID]),
+			Inline: true,
+		})
+	}
+
+	var lines []string
+	for n, link := range donated {
+		if n == maxLinksListed {
+			lines = append(lines, fmt.Sprintf("…and %d more", len(donated)-n))
+			break
+		}
+		lines = append(lines, fmt.Sprintf("%s %s, <t:%d:d>", getQualityEmoji(link.Quality), link.GetDisplayName(), link.AddedDate.Unix()))
+	}
+	embed.Description = truncate(strings.Join(lines, "\n"), 4096)
+	return embed
+}
+
+// donorBonusNote explains the draw bonus in embed footers when it is on
+func donorBonusNote() string {
+	if !donorBonus.Enabled() {
+		return ""
+	}
+	note := fmt.Sprintf(" • Donors get %d extra draw entries per link", donorBonus.EntriesPerLink)
+	if donorBonus.MaxEntries > 0 {
+		note += fmt.Sprintf(" (up to %d)", donorBonus.MaxEntries)
+	}
+	if donorBonus.WindowDays > 0 {
+		note += fmt.Sprintf(" donated in the last %d days", donorBonus.WindowDays)
+	}
+	return note
+}
+
+// donorFromOptions returns the registered member named by the donor option,
+// or nil if no donor was given
+func donorFromOptions(ctx context.Context, s *discordgo.Session, options map[string]*discordgo.ApplicationCommandInteractionDataOption) (*models.Member, error) {
+	opt, ok := options["donor"]
+	if !ok {
+		return nil, nil
+	}
+	donor, err := dbClient.GetMember(ctx, opt.UserValue(s).ID)
+	if err != nil {
+		return nil, fmt.Errorf("The donor is not a registered member. Add them with /add-member first.")
+	}
+	return donor, nil
+}
+
+// creditDonor marks links as donated by a member
+func creditDonor(links []*models.InventoryLink, donor *models.Member) {
+	if donor == nil {
+		return
+	}
+	for _, link := range links {
+		link.SetDonor(donor.DiscordID, donor.Username)
+	}
+}
+
+// donorEntries returns the extra draw entries donors have earned, or nil when
+// the bonus is off. Draws go ahead without the bonus if inventory can't be read.
+func donorEntries(ctx context.Context) map[string]int {
+	if !donorBonus.Enabled() {
+		return nil
+	}
+	links, err := dbClient.GetAllInventoryLinks(ctx)
+	if err != nil {
+		log.Printf("Failed to get donations for the draw: %v", err)
+		return nil
+	}
+	return donorBonus.Entries(links, time.Now())
+}
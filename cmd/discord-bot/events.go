@@ -0,0 +1,449 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"flavaflav/internal/db"
+	"flavaflav/internal/models"
+	"flavaflav/internal/stats"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// attendancePolicy makes event attendance count towards eligibility and
+// draws, set from the ATTENDANCE_* variables at startup
+var attendancePolicy models.AttendancePolicy
+
+// maxEventsListed limits the events shown by /event list
+const maxEventsListed = 15
+
+var eventIDOption = &discordgo.ApplicationCommandOption{
+	Type:        discordgo.ApplicationCommandOptionString,
+	Name:        "event_id",
+	Description: "Event ID (see /event list)",
+	Required:    true,
+}
+
+var attendeeOption = &discordgo.ApplicationCommandOption{
+	Type:        discordgo.ApplicationCommandOptionUser,
+	Name:        "member",
+	Description: "Member who attended",
+	Required:    true,
+}
+
+var attendancePeriodOption = &discordgo.ApplicationCommandOption{
+	Type:        discordgo.ApplicationCommandOptionString,
+	Name:        "period",
+	Description: "Time period (default: the attendance window)",
+	Required:    false,
+	Choices:     periodOption.Choices,
+}
+
+var eventCommand = &discordgo.ApplicationCommand{
+	Name:        "event",
+	Description: "Guild events and attendance",
+	Options: []*discordgo.ApplicationCommandOption{
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "create",
+			Description: "Create a guild event (Maester only)",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "name",
+					Description: "Event name, e.g. Friday Inferno run",
+					Required:    true,
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "kind",
+					Description: "Kind of event",
+					Required:    true,
+					Choices: []*discordgo.ApplicationCommandOptionChoice{
+						{Name: "Boss run", Value: models.EventKindBoss},
+						{Name: "Siege", Value: models.EventKindSiege},
+						{Name: "Other", Value: models.EventKindOther},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "starts_at",
+					Description: "Start time (YYYY-MM-DD HH:MM in the guild time zone, default now)",
+					Required:    false,
+				},
+			},
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "attend",
+			Description: "Record a member as attending (Maester only)",
+			Options:     []*discordgo.ApplicationCommandOption{eventIDOption, attendeeOption},
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "voice",
+			Description: "Record every registered member in a voice channel as attending (Maester only)",
+			Options: []*discordgo.ApplicationCommandOption{
+				eventIDOption,
+				{
+					Type:         discordgo.ApplicationCommandOptionChannel,
+					Name:         "channel",
+					Description:  "Voice channel the event is running in",
+					Required:     true,
+					ChannelTypes: []discordgo.ChannelType{discordgo.ChannelTypeGuildVoice, discordgo.ChannelTypeGuildStageVoice},
+				},
+			},
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "remove",
+			Description: "Remove a member recorded by mistake (Maester only)",
+			Options:     []*discordgo.ApplicationCommandOption{eventIDOption, attendeeOption},
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "list",
+			Description: "Recent events and how many attended",
+			Options:     []*discordgo.ApplicationCommandOption{attendancePeriodOption},
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "attendance",
+			Description: "Who attended the most events, or one member's attendance",
+			Options: []*discordgo.ApplicationCommandOption{
+				attendancePeriodOption,
+				{
+					Type:        discordgo.ApplicationCommandOptionUser,
+					Name:        "member",
+					Description: "Show this member's attendance instead of the leaderboard",
+					Required:    false,
+				},
+			},
+		},
+	},
+}
+
+func handleEvent(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if !dbClient.EventsEnabled() {
+		respondError(s, i, "Events are not configured.")
+		return
+	}
+
+	sub := i.ApplicationCommandData().Options[0]
+	options := optionMap(sub.Options)
+
+	switch sub.Name {
+	case "list":
+		handleEventList(ctx, s, i, options)
+		return
+	case "attendance":
+		handleEventAttendance(ctx, s, i, options)
+		return
+	}
+
+	if !isMaester(s, i.Member) {
+		respondError(s, i, "Only Maesters can manage events.")
+		return
+	}
+
+	switch sub.Name {
+	case "create":
+		handleEventCreate(ctx, s, i, options)
+	case "attend", "remove":
+		user := options["member"].UserValue(s)
+		member, err := dbClient.GetMember(ctx, user.ID)
+		if err != nil {
+			respondError(s, i, "That member is not registered")
+			return
+		}
+		eventID := options["event_id"].StringValue()
+		if sub.Name == "attend" {
+			err = dbClient.AddEventAttendees(ctx, eventID, []string{member.DiscordID})
+		} else {
+			err = dbClient.RemoveEventAttendees(ctx, eventID, []string{member.DiscordID})
+		}
+		respondAttendance(ctx, s, i, eventID, err, nil)
+	case "voice":
+		handleEventVoice(ctx, s, i, options)
+	}
+}
+
+func handleEventCreate(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate, options map[string]*discordgo.ApplicationCommandInteractionDataOption) {
+	startsAt := time.Now()
+	if opt, ok := options["starts_at"]; ok {
+		loc, _ := time.LoadLocation(guildTimeZone)
+		parsed, err := time.ParseInLocation("2006-01-02 15:04", strings.TrimSpace(opt.StringValue()), loc)
+		if err != nil {
+			respondError(s, i, "Invalid starts_at. Use YYYY-MM-DD HH:MM")
+			return
+		}
+		startsAt = parsed
+	}
+
+	name := strings.TrimSpace(options["name"].StringValue())
+	event := models.NewEvent(name, options["kind"].StringValue(), startsAt, i.Member.User.ID)
+	if err := dbClient.CreateEvent(ctx, event); err != nil {
+		log.Printf("Failed to create event: %v", err)
+		respondError(s, i, "Failed to create event")
+		return
+	}
+
+	respondEmbed(s, i, &discordgo.MessageEmbed{
+		Title:       "📅 Event Created",
+		Color:       0x3498db,
+		Description: fmt.Sprintf("**%s** (%s), <t:%d:f>", event.Name, event.Kind, event.StartsAt.Unix()),
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "Event ID", Value: "`" + event.EventID + "`"},
+		},
+		Footer: &discordgo.MessageEmbedFooter{Text: "Record attendance with /event attend or /event voice"},
+	})
+}
+
+// handleEventVoice records every registered member currently in a voice
+// channel as attending
+func handleEventVoice(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate, options map[string]*discordgo.ApplicationCommandInteractionDataOption) {
+	channel := options["channel"].ChannelValue(s)
+	guild, err := s.State.Guild(i.GuildID)
+	if err != nil {
+		respondError(s, i, "Failed to read voice channels")
+		return
+	}
+
+	var memberIDs []string
+	unregistered := 0
+	for _, state := range guild.VoiceStates {
+		if state.ChannelID != channel.ID {
+			continue
+		}
+		if _, err := dbClient.GetMember(ctx, state.UserID); err != nil {
+			unregistered++
+			continue
+		}
+		memberIDs = append(memberIDs, state.UserID)
+	}
+	if len(memberIDs) == 0 {
+		respondError(s, i, fmt.Sprintf("No registered members are in %s", channel.Mention()))
+		return
+	}
+
+	eventID := options["event_id"].StringValue()
+	err = dbClient.AddEventAttendees(ctx, eventID, memberIDs)
+	note := fmt.Sprintf("Recorded %d members from the voice channel", len(memberIDs))
+	if unregistered > 0 {
+		note += fmt.Sprintf("; %d unregistered users skipped", unregistered)
+	}
+	respondAttendance(ctx, s, i, eventID, err, &discordgo.MessageEmbedFooter{Text: note})
+}
+
+// respondAttendance reports the result of an attendance change with the
+// event's attendees
+func respondAttendance(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate, eventID string, err error, footer *discordgo.MessageEmbedFooter) {
+	if errors.Is(err, db.ErrEventNotFound) {
+		respondError(s, i, "Event not found. See /event list for IDs.")
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to record attendance: %v", err)
+		respondError(s, i, "Failed to record attendance")
+		return
+	}
+
+	event, err := dbClient.GetEvent(ctx, eventID)
+	if err != nil {
+		respondError(s, i, "Failed to get event")
+		return
+	}
+
+	attendees := make([]string, len(event.Attendees))
+	for n, id := range event.Attendees {
+		attendees[n] = displayUser(id)
+	}
+	embed := &discordgo.MessageEmbed{
+		Title:       fmt.Sprintf("📅 %s: %d attended", event.Name, len(event.Attendees)),
+		Color:       0x3498db,
+		Description: truncate(strings.Join(attendees, ", "), 4096),
+		Footer:      footer,
+	}
+	if len(attendees) == 0 {
+		embed.Description = "Nobody recorded yet"
+	}
+	respondEmbed(s, i, embed)
+}
+
+func handleEventList(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate, options map[string]*discordgo.ApplicationCommandInteractionDataOption) {
+	period, err := attendancePeriod(options)
+	if err != nil {
+		respondError(s, i, err.Error())
+		return
+	}
+
+	events, err := dbClient.GetAllEvents(ctx)
+	if err != nil {
+		respondError(s, i, "Failed to get events")
+		return
+	}
+	events = stats.EventsIn(events, period)
+
+	embed := &discordgo.MessageEmbed{
+		Title:  "📅 Guild Events",
+		Color:  0x3498db,
+		Footer: &discordgo.MessageEmbedFooter{Text: "Period: " + period.Name},
+	}
+	if len(events) == 0 {
+		embed.Description = "No events in this period"
+		respondEmbed(s, i, embed)
+		return
+	}
+
+	var lines []string
+	for n, event := range events {
+		if n == maxEventsListed {
+			lines = append(lines, fmt.Sprintf("…and %d more", len(events)-n))
+			break
+		}
+		lines = append(lines, fmt.Sprintf("<t:%d:d> **%s** (%s): %d attended\n`%s`", event.StartsAt.Unix(), event.Name, event.Kind, len(event.Attendees), event.EventID))
+	}
+	embed.Description = truncate(strings.Join(lines, "\n"), 4096)
+	respondEmbed(s, i, embed)
+}
+
+func handleEventAttendance(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate, options map[string]*discordgo.ApplicationCommandInteractionDataOption) {
+	period, err := attendancePeriod(options)
+	if err != nil {
+		respondError(s, i, err.Error())
+		return
+	}
+
+	events, err := dbClient.GetAllEvents(ctx)
+	if err != nil {
+		respondError(s, i, "Failed to get events")
+		return
+	}
+	members, err := dbClient.GetAllMembers(ctx)
+	if err != nil {
+		respondError(s, i, "Failed to get members")
+		return
+	}
+	board := stats.AttendanceBoard(events, members, period)
+
+	embed := &discordgo.MessageEmbed{
+		Title:  "📅 Attendance",
+		Color:  0x3498db,
+		Footer: &discordgo.MessageEmbedFooter{Text: "Period: " + period.Name + " • Policy: " + attendancePolicy.Describe()},
+	}
+
+	if opt, ok := options["member"]; ok {
+		user := opt.UserValue(s)
+		for _, entry := range board {
+			if entry.MemberID != user.ID {
+				continue
+			}
+			embed.Title = "📅 Attendance of " + entry.MemberUsername
+			embed.Description = fmt.Sprintf("%d events attended %s", entry.Events, formatEventKinds(entry.ByKind))
+			if !entry.LastAttendedAt.IsZero() {
+				embed.Description += fmt.Sprintf("\nLast attended <t:%d:d>", entry.LastAttendedAt.Unix())
+			}
+			respondEmbed(s, i, embed)
+			return
+		}
+		respondError(s, i, "That member is not registered")
+		return
+	}
+
+	var lines []string
+	for rank, entry := range board {
+		if rank == leaderboardSize || entry.Events == 0 {
+			break
+		}
+		lines = append(lines, fmt.Sprintf("**%d.** %s: %d %s", rank+1, entry.MemberUsername, entry.Events, formatEventKinds(entry.ByKind)))
+	}
+	if len(lines) == 0 {
+		embed.Description = "No attendance recorded in this period"
+	} else {
+		embed.Description = strings.Join(lines, "\n")
+	}
+	respondEmbed(s, i, embed)
+}
+
+// attendancePeriod reads the period option, defaulting to the policy's window
+func attendancePeriod(options map[string]*discordgo.ApplicationCommandInteractionDataOption) (stats.Period, error) {
+	if _, ok := options["period"]; !ok && attendancePolicy.WindowDays > 0 {
+		return stats.ParsePeriod(strconv.Itoa(attendancePolicy.WindowDays)+"d", time.Now())
+	}
+	return periodFromOptions(options)
+}
+
+// formatEventKinds renders counts like "(boss 3, siege 1)"
+func formatEventKinds(counts map[string]int) string {
+	var parts []string
+	for _, kind := range models.AllEventKinds {
+		if counts[kind] > 0 {
+			parts = append(parts, fmt.Sprintf("%s %d", kind, counts[kind]))
+		}
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return "(" + strings.Join(parts, ", ") + ")"
+}
+
+// attendanceCounts returns how many events each member attended within the
+// policy's window, or nil when events aren't configured or can't be read
+func attendanceCounts(ctx context.Context) map[string]int {
+	if !dbClient.EventsEnabled() {
+		return nil
+	}
+	events, err := dbClient.GetAllEvents(ctx)
+	if err != nil {
+		log.Printf("Failed to get attendance: %v", err)
+		return nil
+	}
+	return attendancePolicy.Counts(events, time.Now())
+}
+
+// filterByAttendance drops members who haven't attended enough events when
+// the policy requires attendance. Without attendance data nobody is dropped.
+func filterByAttendance(ctx context.Context, members []*models.Member) []*models.Member {
+	if !attendancePolicy.RequiresAttendance() {
+		return members
+	}
+	counts := attendanceCounts(ctx)
+	if counts == nil {
+		return members
+	}
+	return attendancePolicy.FilterByAttendance(members, counts)
+}
+
+// drawEntries returns the extra draw entries members have earned through
+// donations and attendance
+func drawEntries(ctx context.Context) map[string]int {
+	var attendance map[string]int
+	if attendancePolicy.BonusEnabled() {
+		attendance = attendancePolicy.Entries(attendanceCounts(ctx))
+	}
+	return models.MergeEntries(donorEntries(ctx), attendance)
+}
+
+// attendanceField shows how many events a member attended within the
+// policy's window, or nil when events aren't configured
+func attendanceField(ctx context.Context, memberID string) *discordgo.MessageEmbedField {
+	counts := attendanceCounts(ctx)
+	if counts == nil {
+		return nil
+	}
+	name := "Events Attended"
+	if attendancePolicy.WindowDays > 0 {
+		name += fmt.Sprintf(" (%dd)", attendancePolicy.WindowDays)
+	}
+	value := strconv.Itoa(counts[memberID])
+	if attendancePolicy.RequiresAttendance() {
+		value += fmt.Sprintf(" / %d required", attendancePolicy.MinEvents)
+	}
+	return &discordgo.MessageEmbedField{Name: name, Value: value, Inline: true}
+}
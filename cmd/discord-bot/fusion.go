@@ -0,0 +1,156 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"flavaflav/internal/db"
+	"flavaflav/internal/models"
+	"flavaflav/internal/notify"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+var fusionCommand = &discordgo.ApplicationCommand{
+	Name:        "fusion",
+	Description: "Fuse links into a higher quality and account for them (Maester only)",
+	Options: []*discordgo.ApplicationCommandOption{
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "create",
+			Description: "Record links of one quality fused into a link of the next quality",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "link_ids",
+					Description: "IDs of the links used, separated by spaces or commas (see /link find)",
+					Required:    true,
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "link_type",
+					Description: "Type of the link the fusion produced",
+					Required:    true,
+				},
+				noteOption,
+			},
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "ledger",
+			Description: "Where every link of each quality went, including fusions",
+		},
+	},
+}
+
+func handleFusion(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if !isMaester(s, i.Member) {
+		respondError(s, i, "Only Maesters can fuse links.")
+		return
+	}
+
+	sub := i.ApplicationCommandData().Options[0]
+	options := optionMap(sub.Options)
+
+	switch sub.Name {
+	case "create":
+		var note string
+		if opt, ok := options["note"]; ok {
+			note = opt.StringValue()
+		}
+		linkIDs := strings.FieldsFunc(options["link_ids"].StringValue(), func(r rune) bool {
+			return r == ',' || r == ' '
+		})
+		embed, err := fuseLinks(ctx, linkIDs, strings.TrimSpace(options["link_type"].StringValue()), note, i.Member.User.ID)
+		if err != nil {
+			respondError(s, i, err.Error())
+			return
+		}
+		respondEmbed(s, i, embed)
+		checkStock(ctx, s)
+	case "ledger":
+		handleFusionLedger(ctx, s, i)
+	}
+}
+
+// fuseLinks replaces the given links with one link of the next quality up
+func fuseLinks(ctx context.Context, linkIDs []string, linkType, note, by string) (*discordgo.MessageEmbed, error) {
+	if len(linkIDs) > models.MaxFusionInputs {
+		return nil, fmt.Errorf("At most %d links can be fused at once", models.MaxFusionInputs)
+	}
+
+	inputs := make([]*models.InventoryLink, 0, len(linkIDs))
+	for _, linkID := range linkIDs {
+		link, err := dbClient.GetInventoryLink(ctx, linkID)
+		if err != nil {
+			return nil, fmt.Errorf("Link %s not found", linkID)
+		}
+		inputs = append(inputs, link)
+	}
+	if len(inputs) == 0 {
+		return nil, fmt.Errorf("List the IDs of the links that were fused")
+	}
+
+	resultQuality, ok := models.NextQuality(inputs[0].Quality)
+	if !ok {
+		return nil, fmt.Errorf("%s links can't be fused into a higher quality", inputs[0].Quality)
+	}
+
+	catalog, err := dbClient.GetLinkCatalog(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get link catalog")
+	}
+	category, bonus, err := catalog.InventoryDetails(linkType, resultQuality, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := models.Fuse(inputs, linkType, category, bonus, note, by)
+	if err != nil {
+		return nil, err
+	}
+
+	err = dbClient.FuseLinks(ctx, inputs, result)
+	if errors.Is(err, db.ErrLinkUnavailable) {
+		return nil, fmt.Errorf("A link was distributed, reserved or fused by someone else; nothing was changed.")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fuse links")
+	}
+
+	return notify.FusionEmbed(inputs, result, displayUser(by)), nil
+}
+
+func handleFusionLedger(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) {
+	links, err := dbClient.GetAllInventoryLinks(ctx)
+	if err != nil {
+		respondError(s, i, "Failed to get inventory")
+		return
+	}
+	ledger := models.ReconcileInventory(links)
+
+	embed := &discordgo.MessageEmbed{Title: "Inventory Ledger", Color: 0x00ff00}
+	for _, quality := range []string{models.QualityGold, models.QualitySilver, models.QualityBronze} {
+		entry, ok := ledger[quality]
+		if !ok {
+			continue
+		}
+		value := entry.Describe()
+		if !entry.Balanced() {
+			value += "\n⚠️ Some links have an unknown status"
+			embed.Color = 0xff9900
+		}
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  fmt.Sprintf("%s %s", getQualityEmoji(quality), strings.Title(quality)),
+			Value: value,
+		})
+	}
+	if len(embed.Fields) == 0 {
+		embed.Description = "No links recorded yet"
+	}
+
+	respondEmbed(s, i, embed)
+}
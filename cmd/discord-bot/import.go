@@ -0,0 +1,378 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"flavaflav/internal/importer"
+	"flavaflav/internal/models"
+	"flavaflav/internal/notify"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Custom ID prefix and actions for the import modal and buttons
+const (
+	importPrefix  = "import"
+	importData    = "data"
+	importConfirm = "confirm"
+	importCancel  = "cancel"
+)
+
+const (
+	// importExpiry is how long a preview can be confirmed
+	importExpiry = 15 * time.Minute
+	// maxImportFileSize limits attached CSV files
+	maxImportFileSize = 1 << 20
+	// maxPreviewLines limits the items listed in a preview embed
+	maxPreviewLines = 20
+)
+
+var importCommand = &discordgo.ApplicationCommand{
+	Name:        "import-inventory",
+	Description: "Add many links at once from CSV or pasted item text (Maester only)",
+	Options: []*discordgo.ApplicationCommandOption{
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        "format",
+			Description: "What you are importing",
+			Required:    true,
+			Choices: []*discordgo.ApplicationCommandOptionChoice{
+				{Name: "CSV (link type, quality, count, notes)", Value: importer.FormatCSV},
+				{Name: "Pasted in-game item list", Value: importer.FormatPaste},
+			},
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionAttachment,
+			Name:        "file",
+			Description: "File to import (otherwise a text box opens)",
+			Required:    false,
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionBoolean,
+			Name:        "allow_unknown",
+			Description: "Add unknown link types as custom links",
+			Required:    false,
+		},
+		donorOption,
+	},
+}
+
+// pendingImport is a validated preview waiting for the officer to confirm it
+type pendingImport struct {
+	preview   *importer.Preview
+	userID    string
+	donor     *models.Member // credited with the links, if given
+	expiresAt time.Time
+}
+
+var (
+	pendingImportsMu sync.Mutex
+	pendingImports   = make(map[string]*pendingImport)
+)
+
+func handleImportInventory(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if !isMaester(s, i.Member) {
+		respondError(s, i, "Only Maesters can import inventory.")
+		return
+	}
+
+	data := i.ApplicationCommandData()
+	options := optionMap(data.Options)
+	format := options["format"].StringValue()
+	allowUnknown := false
+	if opt, ok := options["allow_unknown"]; ok {
+		allowUnknown = opt.BoolValue()
+	}
+	donor, err := donorFromOptions(ctx, s, options)
+	if err != nil {
+		respondError(s, i, err.Error())
+		return
+	}
+
+	if opt, ok := options["file"]; ok {
+		attachment := data.Resolved.Attachments[opt.Value.(string)]
+		if attachment == nil {
+			respondError(s, i, "Attachment not found")
+			return
+		}
+		text, err := downloadAttachment(ctx, attachment)
+		if err != nil {
+			respondError(s, i, err.Error())
+			return
+		}
+		showImportPreview(ctx, s, i, format, text, allowUnknown, donor)
+		return
+	}
+
+	// The modal's custom ID carries the options: format:unknown:donor ID
+	flag := ""
+	if allowUnknown {
+		flag = "unknown"
+	}
+	id := format + ":" + flag + ":"
+	if donor != nil {
+		id += donor.DiscordID
+	}
+	placeholder := "Melee Damage, gold, 3, from the vault sort"
+	if format == importer.FormatPaste {
+		placeholder = "3x Gold Mastery Chain Link: Melee Damage +4.50%"
+	}
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseModal,
+		Data: &discordgo.InteractionResponseData{
+			CustomID: importPrefix + ":" + importData + ":" + id,
+			Title:    "Import inventory",
+			Components: []discordgo.MessageComponent{
+				discordgo.ActionsRow{
+					Components: []discordgo.MessageComponent{
+						discordgo.TextInput{
+							CustomID:    "data",
+							Label:       "One item per line",
+							Style:       discordgo.TextInputParagraph,
+							Placeholder: placeholder,
+							Required:    true,
+							MaxLength:   4000,
+						},
+					},
+				},
+			},
+		},
+	})
+}
+
+func handleImportModal(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate, id string) {
+	if !isMaester(s, i.Member) {
+		respondError(s, i, "Only Maesters can import inventory.")
+		return
+	}
+
+	parts := strings.SplitN(id, ":", 3)
+	for len(parts) < 3 {
+		parts = append(parts, "")
+	}
+	format, flag, donorID := parts[0], parts[1], parts[2]
+
+	var donor *models.Member
+	if donorID != "" {
+		var err error
+		donor, err = dbClient.GetMember(ctx, donorID)
+		if err != nil {
+			respondError(s, i, "The donor is no longer a registered member.")
+			return
+		}
+	}
+	showImportPreview(ctx, s, i, format, modalTextValue(i.ModalSubmitData(), "data"), flag == "unknown", donor)
+}
+
+// showImportPreview validates an import and shows it to the officer with
+// confirm and cancel buttons
+func showImportPreview(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate, format, text string, allowUnknown bool, donor *models.Member) {
+	catalog, err := dbClient.GetLinkCatalog(ctx)
+	if err != nil {
+		respondError(s, i, "Failed to get link catalog")
+		return
+	}
+
+	preview, err := importer.Build(format, text, catalog, time.Now())
+	if err != nil {
+		respondError(s, i, err.Error())
+		return
+	}
+
+	canCommit := preview.CanCommit(allowUnknown)
+	components := []discordgo.MessageComponent{}
+	if canCommit {
+		pendingImportsMu.Lock()
+		for key, pending := range pendingImports {
+			if time.Now().After(pending.expiresAt) {
+				delete(pendingImports, key)
+			}
+		}
+		pendingImports[i.ID] = &pendingImport{
+			preview:   preview,
+			userID:    i.Member.User.ID,
+			donor:     donor,
+			expiresAt: time.Now().Add(importExpiry),
+		}
+		pendingImportsMu.Unlock()
+
+		components = []discordgo.MessageComponent{
+			discordgo.ActionsRow{
+				Components: []discordgo.MessageComponent{
+					discordgo.Button{Label: fmt.Sprintf("Add %d links", preview.TotalLinks), Style: discordgo.SuccessButton, CustomID: importPrefix + ":" + importConfirm + ":" + i.ID},
+					discordgo.Button{Label: "Cancel", Style: discordgo.DangerButton, CustomID: importPrefix + ":" + importCancel + ":" + i.ID},
+				},
+			},
+		}
+	}
+
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds:     []*discordgo.MessageEmbed{importPreviewEmbed(preview, canCommit, allowUnknown)},
+			Components: components,
+			Flags:      discordgo.MessageFlagsEphemeral,
+		},
+	})
+}
+
+func handleImportComponent(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate, action, id string) {
+	if !isMaester(s, i.Member) {
+		respondError(s, i, "Only Maesters can import inventory.")
+		return
+	}
+
+	pendingImportsMu.Lock()
+	pending, ok := pendingImports[id]
+	if ok && pending.userID == i.Member.User.ID {
+		delete(pendingImports, id)
+	}
+	pendingImportsMu.Unlock()
+
+	if !ok || time.Now().After(pending.expiresAt) {
+		updateImportMessage(s, i, &discordgo.MessageEmbed{
+			Title:       "Import Expired",
+			Color:       0x666666,
+			Description: "This preview has expired. Run /import-inventory again.",
+		})
+		return
+	}
+	if pending.userID != i.Member.User.ID {
+		respondError(s, i, "Only the officer who started this import can confirm it.")
+		return
+	}
+
+	if action == importCancel {
+		updateImportMessage(s, i, &discordgo.MessageEmbed{
+			Title:       "Import Cancelled",
+			Color:       0x666666,
+			Description: "No links were added.",
+		})
+		return
+	}
+
+	links := pending.preview.Links(i.Member.User.ID)
+	creditDonor(links, pending.donor)
+	if err := dbClient.BatchCreateInventoryLinks(ctx, links); err != nil {
+		updateImportMessage(s, i, &discordgo.MessageEmbed{
+			Title:       "Import Failed",
+			Color:       0xff0000,
+			Description: err.Error(),
+		})
+		return
+	}
+
+	updateImportMessage(s, i, &discordgo.MessageEmbed{
+		Title:       "Import Complete",
+		Color:       0x00ff00,
+		Description: fmt.Sprintf("Added %d links to inventory", len(links)),
+	})
+	s.ChannelMessageSendEmbed(i.ChannelID, notify.InventoryAddedEmbed(links, i.Member.User.Username))
+	checkStock(ctx, s)
+}
+
+func importPreviewEmbed(preview *importer.Preview, canCommit, allowUnknown bool) *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{
+		Title: fmt.Sprintf("Import Preview: %d links", preview.TotalLinks),
+		Color: 0x00ff00,
+	}
+	if !canCommit {
+		embed.Color = 0xff9900
+	}
+
+	var lines []string
+	for n, item := range preview.Items {
+		if n == maxPreviewLines {
+			lines = append(lines, fmt.Sprintf("…and %d more", len(preview.Items)-n))
+			break
+		}
+		line := fmt.Sprintf("%s %d × %s (%s, %s)", getQualityEmoji(item.Quality), item.Count, item.LinkType, item.Category, item.Bonus)
+		if item.Error != "" {
+			line += " ❌ " + item.Error
+		} else if !item.Known {
+			line += " ❓"
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) == 0 {
+		lines = append(lines, "Nothing to import")
+	}
+	embed.Description = strings.Join(lines, "\n")
+
+	if len(preview.UnknownTypes) > 0 {
+		value := strings.Join(preview.UnknownTypes, ", ")
+		if !allowUnknown {
+			value += "\nRe-run with allow_unknown to add these as custom links."
+		}
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "❓ Unknown Link Types", Value: truncate(value, 1024)})
+	}
+	if len(preview.Errors) > 0 {
+		var errLines []string
+		for _, e := range preview.Errors {
+			if e.Line > 0 {
+				errLines = append(errLines, fmt.Sprintf("Line %d: %s", e.Line, e.Error))
+			} else {
+				errLines = append(errLines, e.Error)
+			}
+		}
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "❌ Errors", Value: truncate(strings.Join(errLines, "\n"), 1024)})
+	}
+	if !canCommit {
+		embed.Footer = &discordgo.MessageEmbedFooter{Text: "Fix the problems above and import again; nothing has been added."}
+	}
+
+	return embed
+}
+
+// updateImportMessage replaces the preview message and removes its buttons
+func updateImportMessage(s *discordgo.Session, i *discordgo.InteractionCreate, embed *discordgo.MessageEmbed) {
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Embeds:     []*discordgo.MessageEmbed{embed},
+			Components: []discordgo.MessageComponent{},
+		},
+	})
+}
+
+func downloadAttachment(ctx context.Context, attachment *discordgo.MessageAttachment) (string, error) {
+	if attachment.Size > maxImportFileSize {
+		return "", fmt.Errorf("File is too large (limit %d KB)", maxImportFileSize/1024)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, attachment.URL, nil)
+	if err != nil {
+		return "", fmt.Errorf("Failed to download attachment")
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("Failed to download attachment")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("Failed to download attachment (status %d)", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxImportFileSize))
+	if err != nil {
+		return "", fmt.Errorf("Failed to read attachment")
+	}
+	return string(body), nil
+}
+
+// truncate shortens text to fit an embed field
+func truncate(text string, limit int) string {
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+	return string(runes[:limit-1]) + "…"
+}
@@ -0,0 +1,239 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"flavaflav/internal/db"
+	"flavaflav/internal/models"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// maxLinksListed limits the links shown by /link find and /link retired
+const maxLinksListed = 15
+
+var linkIDOption = &discordgo.ApplicationCommandOption{
+	Type:        discordgo.ApplicationCommandOptionString,
+	Name:        "link_id",
+	Description: "ID shown by /link find or /link retired",
+	Required:    true,
+}
+
+var noteOption = &discordgo.ApplicationCommandOption{
+	Type:        discordgo.ApplicationCommandOptionString,
+	Name:        "note",
+	Description: "Optional note for the link's history",
+	Required:    false,
+}
+
+var linkCommand = &discordgo.ApplicationCommand{
+	Name:        "link",
+	Description: "Edit, retire and restore individual inventory links (Maester only)",
+	Options: []*discordgo.ApplicationCommandOption{
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "find",
+			Description: "List available links of a type with their IDs and notes",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "link_type",
+					Description: "Type of mastery link",
+					Required:    true,
+				},
+			},
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "notes",
+			Description: "Replace a link's notes",
+			Options: []*discordgo.ApplicationCommandOption{
+				linkIDOption,
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "notes",
+					Description: "New notes (empty clears them)",
+					Required:    true,
+				},
+			},
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "retire",
+			Description: "Take a link out of stock",
+			Options: []*discordgo.ApplicationCommandOption{
+				linkIDOption,
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "reason",
+					Description: "Why the link is leaving stock",
+					Required:    true,
+					Choices: []*discordgo.ApplicationCommandOptionChoice{
+						{Name: "Lost", Value: models.RetireLost},
+						{Name: "Sold", Value: models.RetireSold},
+						{Name: "Consumed", Value: models.RetireConsumed},
+						{Name: "Added by mistake", Value: models.RetireMistake},
+					},
+				},
+				noteOption,
+			},
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "restore",
+			Description: "Return a retired link to stock",
+			Options:     []*discordgo.ApplicationCommandOption{linkIDOption, noteOption},
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "retired",
+			Description: "List retired links",
+		},
+	},
+}
+
+func handleLink(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if !isMaester(s, i.Member) {
+		respondError(s, i, "Only Maesters can manage inventory links.")
+		return
+	}
+
+	sub := i.ApplicationCommandData().Options[0]
+	options := optionMap(sub.Options)
+	officer := i.Member.User.ID
+
+	switch sub.Name {
+	case "find":
+		handleLinkFind(ctx, s, i, options["link_type"].StringValue())
+	case "retired":
+		handleLinkRetired(ctx, s, i)
+	case "notes", "retire", "restore":
+		link, err := dbClient.GetInventoryLink(ctx, options["link_id"].StringValue())
+		if err != nil {
+			respondError(s, i, "Link not found")
+			return
+		}
+		var note string
+		if opt, ok := options["note"]; ok {
+			note = opt.StringValue()
+		}
+
+		previous := link.Status
+		title := "Link Notes Updated"
+		switch sub.Name {
+		case "notes":
+			link.EditNotes(options["notes"].StringValue(), officer)
+		case "retire":
+			err = link.Retire(options["reason"].StringValue(), note, officer)
+			title = "Link Retired"
+		case "restore":
+			err = link.Restore(note, officer)
+			title = "Link Restored"
+		}
+		if err != nil {
+			respondError(s, i, err.Error())
+			return
+		}
+
+		if err := dbClient.SaveInventoryLinkChange(ctx, link, previous); err != nil {
+			if errors.Is(err, db.ErrLinkChanged) {
+				respondError(s, i, "That link was changed by someone else; try again.")
+				return
+			}
+			respondError(s, i, "Failed to update link")
+			return
+		}
+
+		respondEmbed(s, i, linkEmbed(title, link))
+		checkStock(ctx, s)
+	}
+}
+
+func handleLinkFind(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate, linkType string) {
+	links, err := dbClient.GetAvailableInventoryLinks(ctx)
+	if err != nil {
+		respondError(s, i, "Failed to get inventory")
+		return
+	}
+
+	var matches []*models.InventoryLink
+	for _, link := range links {
+		if strings.EqualFold(link.LinkType, linkType) {
+			matches = append(matches, link)
+		}
+	}
+
+	respondEmbed(s, i, linkListEmbed("Available "+linkType+" Links", matches, "No available links of that type"))
+}
+
+func handleLinkRetired(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) {
+	links, err := dbClient.GetRetiredInventoryLinks(ctx)
+	if err != nil {
+		respondError(s, i, "Failed to get retired links")
+		return
+	}
+
+	respondEmbed(s, i, linkListEmbed("Retired Links", links, "No retired links"))
+}
+
+func linkEmbed(title string, link *models.InventoryLink) *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{
+		Title:       title,
+		Color:       getQualityColor(link.Quality),
+		Description: fmt.Sprintf("%s %s", getQualityEmoji(link.Quality), link.GetDisplayName()),
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "Link ID", Value: link.LinkID, Inline: true},
+			{Name: "Status", Value: link.Status, Inline: true},
+		},
+	}
+	if link.RetireReason != "" {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "Reason", Value: link.RetireReason, Inline: true})
+	}
+	if link.DonorName != "" {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "Donated By", Value: link.DonorName, Inline: true})
+	}
+	if link.FusedInto != "" {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "Fused Into", Value: link.FusedInto, Inline: true})
+	}
+	if link.IsFusionResult() {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "Fused From", Value: truncate(strings.Join(link.FusedFrom, ", "), 1024)})
+	}
+	if link.Notes != "" {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "Notes", Value: link.Notes})
+	}
+	return embed
+}
+
+func linkListEmbed(title string, links []*models.InventoryLink, empty string) *discordgo.MessageEmbed {
+	sort.Slice(links, func(a, b int) bool {
+		return links[a].AddedDate.Before(links[b].AddedDate)
+	})
+
+	embed := &discordgo.MessageEmbed{Title: title, Color: 0x00ff00}
+	if len(links) == 0 {
+		embed.Description = empty
+		return embed
+	}
+
+	var lines []string
+	for n, link := range links {
+		if n == maxLinksListed {
+			lines = append(lines, fmt.Sprintf("…and %d more", len(links)-n))
+			break
+		}
+		line := fmt.Sprintf("%s `%s` %s", getQualityEmoji(link.Quality), link.LinkID, link.GetDisplayName())
+		if link.RetireReason != "" {
+			line += " — " + link.RetireReason
+		}
+		if link.Notes != "" {
+			line += " — " + link.Notes
+		}
+		lines = append(lines, line)
+	}
+	embed.Description = truncate(strings.Join(lines, "\n"), 4096)
+	return embed
+}
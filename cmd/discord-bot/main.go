@@ -2,9 +2,9 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
-	"math/rand"
 	"os"
 	"os/signal"
 	"strconv"
@@ -13,14 +13,18 @@ import (
 	"time"
 
 	"flavaflav/internal/db"
+	"flavaflav/internal/importer"
 	"flavaflav/internal/models"
+	"flavaflav/internal/notify"
 
 	"github.com/bwmarrin/discordgo"
 )
 
 var (
-	dbClient *db.DynamoDBClient
-	guildID  string
+	dbClient      *db.DynamoDBClient
+	messenger     *notify.Messenger
+	guildID       string
+	guildTimeZone string
 )
 
 func init() {
@@ -58,12 +62,46 @@ func init() {
 		log.Fatal("DISCORD_GUILD_ID environment variable is required")
 	}
 
+	guildTimeZone = os.Getenv("GUILD_TIMEZONE")
+	if guildTimeZone == "" {
+		guildTimeZone = "UTC"
+	}
+	if _, err := time.LoadLocation(guildTimeZone); err != nil {
+		log.Fatalf("Invalid GUILD_TIMEZONE %q: %v", guildTimeZone, err)
+	}
+
 	// Initialize DynamoDB client with four tables
 	var err error
 	dbClient, err = db.NewDynamoDBClient(membersTable, inventoryTable, distributionsTable, listsTable)
 	if err != nil {
 		log.Fatalf("Failed to initialize DynamoDB client: %v", err)
 	}
+
+	// Optional feature tables
+	if schedulesTable := os.Getenv("DYNAMODB_SCHEDULES_TABLE"); schedulesTable != "" {
+		dbClient.SetSchedulesTable(schedulesTable)
+	}
+	if catalogTable := os.Getenv("DYNAMODB_CATALOG_TABLE"); catalogTable != "" {
+		dbClient.SetCatalogTable(catalogTable)
+	}
+	if thresholdsTable := os.Getenv("DYNAMODB_THRESHOLDS_TABLE"); thresholdsTable != "" {
+		dbClient.SetThresholdsTable(thresholdsTable)
+	}
+	if eventsTable := os.Getenv("DYNAMODB_EVENTS_TABLE"); eventsTable != "" {
+		dbClient.SetEventsTable(eventsTable)
+	}
+
+	// Draw and stock settings, read by interaction handlers and background jobs
+	reservationDuration = durationFromEnv("RESERVATION_DURATION", models.DefaultReservationDuration)
+	stockAlertChannelID = os.Getenv("STOCK_ALERT_CHANNEL_ID")
+	donorBonus, err = models.ParseDonorBonus(os.Getenv("DONOR_BONUS_ENTRIES"), os.Getenv("DONOR_BONUS_MAX"), os.Getenv("DONOR_BONUS_DAYS"))
+	if err != nil {
+		log.Printf("Donor bonus disabled: %v", err)
+	}
+	attendancePolicy, err = models.ParseAttendancePolicy(os.Getenv("ATTENDANCE_WINDOW_DAYS"), os.Getenv("ATTENDANCE_MIN_EVENTS"), os.Getenv("ATTENDANCE_BONUS_ENTRIES"), os.Getenv("ATTENDANCE_BONUS_MAX"))
+	if err != nil {
+		log.Printf("Attendance policy disabled: %v", err)
+	}
 }
 
 func main() {
@@ -78,6 +116,8 @@ func main() {
 		log.Fatalf("Error creating Discord session: %v", err)
 	}
 
+	messenger = notify.NewMessenger(dg)
+
 	// Register slash commands
 	dg.AddHandler(func(s *discordgo.Session, r *discordgo.Ready) {
 		log.Printf("Logged in as: %v#%v", s.State.User.Username, s.State.User.Discriminator)
@@ -93,6 +133,16 @@ func main() {
 		log.Fatalf("Error opening connection: %v", err)
 	}
 
+	// Start background jobs
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go watchEligibility(ctx, durationFromEnv("ELIGIBILITY_CHECK_INTERVAL", 6*time.Hour))
+	go watchReservations(ctx, dg, durationFromEnv("RESERVATION_CHECK_INTERVAL", 15*time.Minute))
+	go watchStock(ctx, dg, durationFromEnv("STOCK_CHECK_INTERVAL", time.Hour))
+	if dbClient.SchedulesEnabled() {
+		go runScheduler(ctx, dg)
+	}
+
 	// Wait for interrupt signal
 	fmt.Println("Bot is now running. Press CTRL-C to exit.")
 	sc := make(chan os.Signal, 1)
@@ -124,6 +174,23 @@ var commands = []*discordgo.ApplicationCommand{
 					{Name: "Gold", Value: "gold"},
 				},
 			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "category",
+				Description: "Filter by link category",
+				Required:    false,
+				Choices:     categoryChoices(nil),
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "group_by",
+				Description: "Group counts by link type (default) or category",
+				Required:    false,
+				Choices: []*discordgo.ApplicationCommandOptionChoice{
+					{Name: "Link type", Value: models.GroupByLinkType},
+					{Name: "Category", Value: models.GroupByCategory},
+				},
+			},
 		},
 	},
 	{
@@ -195,6 +262,7 @@ var commands = []*discordgo.ApplicationCommand{
 				Description: "Number of links to add",
 				Required:    true,
 			},
+			donorOption,
 		},
 	},
 	{
@@ -211,11 +279,39 @@ var commands = []*discordgo.ApplicationCommand{
 					{Name: "Gold", Value: "gold"},
 				},
 			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "link_type",
+				Description: "Link type to hand out (defaults to the oldest available link)",
+				Required:    false,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionBoolean,
+				Name:        "match_build",
+				Description: "Only draw members whose build tags suit the link type",
+				Required:    false,
+			},
 		},
 	},
+	importCommand,
+	donationsCommand,
+	linkCommand,
+	fusionCommand,
+	reverseCommand,
+	reservationCommand,
+	stockCommand,
+	notificationsCommand,
+	charactersCommand,
+	buildCommand,
+	eventCommand,
+	scheduleCommand,
+	leaderboardCommand,
+	statsCommand,
+	reportCommand,
 }
 
 func registerCommands(s *discordgo.Session) {
+	refreshCategoryChoices(context.Background())
 	for _, cmd := range commands {
 		_, err := s.ApplicationCommandCreate(s.State.User.ID, guildID, cmd)
 		if err != nil {
@@ -225,11 +321,23 @@ func registerCommands(s *discordgo.Session) {
 }
 
 func interactionHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
-	if i.ApplicationCommandData().Name == "" {
+	ctx := context.Background()
+
+	switch i.Type {
+	case discordgo.InteractionMessageComponent:
+		handleComponent(ctx, s, i)
+		return
+	case discordgo.InteractionModalSubmit:
+		handleModalSubmit(ctx, s, i)
+		return
+	case discordgo.InteractionApplicationCommand:
+	default:
 		return
 	}
 
-	ctx := context.Background()
+	if i.ApplicationCommandData().Name == "" {
+		return
+	}
 
 	switch i.ApplicationCommandData().Name {
 	case "my-status":
@@ -244,8 +352,70 @@ func interactionHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
 		handlePromoteOfficer(ctx, s, i)
 	case "add-inventory":
 		handleAddInventory(ctx, s, i)
+	case "import-inventory":
+		handleImportInventory(ctx, s, i)
+	case "link":
+		handleLink(ctx, s, i)
+	case "fusion":
+		handleFusion(ctx, s, i)
+	case "reverse-distribution":
+		handleReverseDistribution(ctx, s, i)
+	case "reservation":
+		handleReservation(ctx, s, i)
+	case "stock-alerts":
+		handleStockAlerts(ctx, s, i)
 	case "pick-winner":
 		handlePickWinner(ctx, s, i)
+	case "notifications":
+		handleNotifications(ctx, s, i)
+	case "characters":
+		handleCharacters(ctx, s, i)
+	case "build":
+		handleBuild(ctx, s, i)
+	case "event":
+		handleEvent(ctx, s, i)
+	case "schedule":
+		handleSchedule(ctx, s, i)
+	case "leaderboard":
+		handleLeaderboard(ctx, s, i)
+	case "stats":
+		handleStats(ctx, s, i)
+	case "donations":
+		handleDonations(ctx, s, i)
+	case "report":
+		handleReport(ctx, s, i)
+	}
+}
+
+// handleComponent routes button presses by the prefix of their custom ID
+func handleComponent(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) {
+	parts := strings.SplitN(i.MessageComponentData().CustomID, ":", 3)
+	if len(parts) != 3 {
+		return
+	}
+
+	switch parts[0] {
+	case pickWinnerPrefix:
+		handlePickWinnerComponent(ctx, s, i, parts[1], parts[2])
+	case importPrefix:
+		handleImportComponent(ctx, s, i, parts[1], parts[2])
+	case reservationPrefix:
+		handleReservationComponent(ctx, s, i, parts[1], parts[2])
+	}
+}
+
+// handleModalSubmit routes modal submissions by the prefix of their custom ID
+func handleModalSubmit(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) {
+	parts := strings.SplitN(i.ModalSubmitData().CustomID, ":", 3)
+	if len(parts) != 3 {
+		return
+	}
+
+	switch {
+	case parts[0] == pickWinnerPrefix && parts[1] == pickWinnerRerollReason:
+		handlePickWinnerModal(ctx, s, i, parts[2])
+	case parts[0] == importPrefix && parts[1] == importData:
+		handleImportModal(ctx, s, i, parts[2])
 	}
 }
 
@@ -271,9 +441,19 @@ func handleMyStatus(ctx context.Context, s *discordgo.Session, i *discordgo.Inte
 		},
 		Timestamp: time.Now().Format(time.RFC3339),
 	}
+	if field := buildField(member); field != nil {
+		embed.Fields = append(embed.Fields, field)
+	}
+	if field := attendanceField(ctx, member.DiscordID); field != nil {
+		embed.Fields = append(embed.Fields, field)
+	}
+	if field := charactersField("Characters", member); field != nil {
+		embed.Fields = append(embed.Fields, field)
+	}
 
 	// Get distribution history
 	distributions, err := dbClient.GetDistributionsByMember(ctx, userID)
+	distributions = models.ActiveDistributions(distributions)
 	if err == nil && len(distributions) > 0 {
 		historyText := fmt.Sprintf("Total distributions: %d\n", len(distributions))
 		if len(distributions) <= 5 {
@@ -302,10 +482,16 @@ func handleMyStatus(ctx context.Context, s *discordgo.Session, i *discordgo.Inte
 }
 
 func handleInventory(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) {
-	options := i.ApplicationCommandData().Options
-	var quality string
-	if len(options) > 0 {
-		quality = options[0].StringValue()
+	options := optionMap(i.ApplicationCommandData().Options)
+	var quality, category, groupBy string
+	if opt, ok := options["quality"]; ok {
+		quality = opt.StringValue()
+	}
+	if opt, ok := options["category"]; ok {
+		category = opt.StringValue()
+	}
+	if opt, ok := options["group_by"]; ok {
+		groupBy = opt.StringValue()
 	}
 
 	var links []*models.InventoryLink
@@ -322,22 +508,41 @@ func handleInventory(ctx context.Context, s *discordgo.Session, i *discordgo.Int
 		return
 	}
 
-	// Group by link type and quality
-	summary := make(map[string]map[string]int)
-	for _, link := range links {
-		if summary[link.LinkType] == nil {
-			summary[link.LinkType] = make(map[string]int)
+	catalog, err := dbClient.GetLinkCatalog(ctx)
+	if err != nil {
+		respondError(s, i, "Failed to get link catalog")
+		return
+	}
+	if category != "" {
+		var ok bool
+		if category, ok = catalog.ParseCategory(category); !ok {
+			respondError(s, i, "Unknown category")
+			return
 		}
-		summary[link.LinkType][link.Quality]++
 	}
 
+	models.NormalizeCategories(links, catalog)
+	if category != "" {
+		links = models.FilterLinksByCategory(links, category)
+	}
+
+	// Group by link type (or category) and quality
+	summary := models.SummarizeInventory(links, groupBy)
+
 	embed := &discordgo.MessageEmbed{
 		Title: "Mastery Link Inventory",
 		Color: 0x00ff00,
 	}
 
+	var filters []string
+	if category != "" {
+		filters = append(filters, category)
+	}
 	if quality != "" {
-		embed.Title += fmt.Sprintf(" (%s)", strings.Title(quality))
+		filters = append(filters, strings.Title(quality))
+	}
+	if len(filters) > 0 {
+		embed.Title += fmt.Sprintf(" (%s)", strings.Join(filters, ", "))
 	}
 
 	if len(summary) == 0 {
@@ -367,6 +572,46 @@ func handleInventory(ctx context.Context, s *discordgo.Session, i *discordgo.Int
 	})
 }
 
+// maxCommandChoices is the most choices Discord allows on a command option
+const maxCommandChoices = 25
+
+// categoryChoices lists the link categories as slash command choices; a nil
+// catalog gives the built-in categories
+func categoryChoices(catalog *models.LinkCatalog) []*discordgo.ApplicationCommandOptionChoice {
+	categories := models.AllLinkCategories
+	if catalog != nil {
+		categories = catalog.CategoryNames()
+	}
+	if len(categories) > maxCommandChoices {
+		categories = categories[:maxCommandChoices]
+	}
+	choices := make([]*discordgo.ApplicationCommandOptionChoice, len(categories))
+	for j, category := range categories {
+		choices[j] = &discordgo.ApplicationCommandOptionChoice{Name: category, Value: category}
+	}
+	return choices
+}
+
+// refreshCategoryChoices offers the catalog's categories, including custom
+// ones, in /inventory before the commands are registered
+func refreshCategoryChoices(ctx context.Context) {
+	catalog, err := dbClient.GetLinkCatalog(ctx)
+	if err != nil {
+		log.Printf("Failed to load link catalog for category choices: %v", err)
+		return
+	}
+	for _, cmd := range commands {
+		if cmd.Name != "inventory" {
+			continue
+		}
+		for _, opt := range cmd.Options {
+			if opt.Name == "category" {
+				opt.Choices = categoryChoices(catalog)
+			}
+		}
+	}
+}
+
 func handleCheckRank(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) {
 	targetUser := i.ApplicationCommandData().Options[0].UserValue(s)
 
@@ -388,6 +633,15 @@ func handleCheckRank(ctx context.Context, s *discordgo.Session, i *discordgo.Int
 			{Name: "Gold Eligible", Value: boolToEmoji(member.GoldEligible), Inline: true},
 		},
 	}
+	if field := buildField(member); field != nil {
+		embed.Fields = append(embed.Fields, field)
+	}
+	if field := attendanceField(ctx, member.DiscordID); field != nil {
+		embed.Fields = append(embed.Fields, field)
+	}
+	if field := charactersField("Characters", member); field != nil {
+		embed.Fields = append(embed.Fields, field)
+	}
 
 	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 		Type: discordgo.InteractionResponseChannelMessageWithSource,
@@ -416,6 +670,10 @@ func handleAddMember(ctx context.Context, s *discordgo.Session, i *discordgo.Int
 	member := models.NewMember(targetUser.ID, targetUser.Username, joinDate, i.Member.User.ID)
 
 	err = dbClient.CreateMember(ctx, member)
+	if errors.Is(err, db.ErrAlreadyExists) {
+		respondError(s, i, fmt.Sprintf("%s is already registered.", targetUser.Username))
+		return
+	}
 	if err != nil {
 		respondError(s, i, "Failed to add member")
 		return
@@ -461,6 +719,8 @@ func handlePromoteOfficer(ctx context.Context, s *discordgo.Session, i *discordg
 		return
 	}
 
+	notifyMember(member, models.NotifyPromotions, notify.PromotionEmbed(member))
+
 	embed := &discordgo.MessageEmbed{
 		Title:       "Member Promoted",
 		Color:       0x800080,
@@ -485,81 +745,46 @@ func handleAddInventory(ctx context.Context, s *discordgo.Session, i *discordgo.
 	linkType := options[0].StringValue()
 	quality := options[1].StringValue()
 	count := int(options[2].IntValue())
-
-	if count <= 0 {
-		respondError(s, i, "Count must be greater than 0")
+	donor, err := donorFromOptions(ctx, s, optionMap(options))
+	if err != nil {
+		respondError(s, i, err.Error())
 		return
 	}
 
-	bonus := models.GetLinkBonus(linkType, quality)
-	category := models.GetLinkCategory(linkType)
-
-	var createdCount int
-	for j := 0; j < count; j++ {
-		link := models.NewInventoryLink(linkType, quality, category, bonus, i.Member.User.ID)
-		err := dbClient.CreateInventoryLink(ctx, link)
-		if err != nil {
-			break
-		}
-		createdCount++
-	}
-
-	embed := &discordgo.MessageEmbed{
-		Title:       "Inventory Updated",
-		Color:       0x00ff00,
-		Description: fmt.Sprintf("Added %d %s %s links to inventory", createdCount, quality, linkType),
+	if count <= 0 || count > importer.MaxLinks {
+		respondError(s, i, fmt.Sprintf("Count must be between 1 and %d", importer.MaxLinks))
+		return
 	}
 
-	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-		Type: discordgo.InteractionResponseChannelMessageWithSource,
-		Data: &discordgo.InteractionResponseData{
-			Embeds: []*discordgo.MessageEmbed{embed},
-		},
-	})
-}
-
-func handlePickWinner(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) {
-	if !isMaester(s, i.Member) {
-		respondError(s, i, "Only Maesters can pick winners.")
+	catalog, err := dbClient.GetLinkCatalog(ctx)
+	if err != nil {
+		respondError(s, i, "Failed to get link catalog")
 		return
 	}
-
-	quality := i.ApplicationCommandData().Options[0].StringValue()
-
-	// Get eligible members
-	members, err := dbClient.GetAllMembers(ctx)
+	category, bonus, err := catalog.InventoryDetails(linkType, quality, time.Now())
 	if err != nil {
-		respondError(s, i, "Failed to get members")
+		respondError(s, i, err.Error())
 		return
 	}
 
-	var eligibleMembers []*models.Member
-	for _, member := range members {
-		member.UpdateRankAndEligibility()
-		if (quality == "silver" && member.SilverEligible) || (quality == "gold" && member.GoldEligible) {
-			eligibleMembers = append(eligibleMembers, member)
-		}
+	links := make([]*models.InventoryLink, count)
+	for j := range links {
+		links[j] = models.NewInventoryLink(linkType, quality, category, bonus, i.Member.User.ID)
 	}
-
-	if len(eligibleMembers) == 0 {
-		respondError(s, i, fmt.Sprintf("No members eligible for %s links", quality))
+	creditDonor(links, donor)
+	if err := dbClient.BatchCreateInventoryLinks(ctx, links); err != nil {
+		log.Printf("Error adding inventory: %v", err)
+		respondError(s, i, fmt.Sprintf("Failed to add links: %v", err))
 		return
 	}
 
-	// Pick random winner
-	rand.Seed(time.Now().UnixNano())
-	winner := eligibleMembers[rand.Intn(len(eligibleMembers))]
-
 	embed := &discordgo.MessageEmbed{
-		Title:       fmt.Sprintf("🎉 %s Link Winner!", strings.Title(quality)),
-		Color:       getQualityColor(quality),
-		Description: fmt.Sprintf("**%s** has been selected for a %s link!", winner.Username, quality),
-		Fields: []*discordgo.MessageEmbedField{
-			{Name: "Winner Rank", Value: winner.Rank, Inline: true},
-			{Name: "Days in Guild", Value: strconv.Itoa(winner.DaysInGuild), Inline: true},
-			{Name: "Total Eligible", Value: strconv.Itoa(len(eligibleMembers)), Inline: true},
-		},
-		Timestamp: time.Now().Format(time.RFC3339),
+		Title:       "Inventory Updated",
+		Color:       0x00ff00,
+		Description: fmt.Sprintf("Added %d %s %s links to inventory", len(links), quality, linkType),
+	}
+	if donor != nil {
+		embed.Description += fmt.Sprintf(", donated by **%s**. Thank you!", donor.Username)
 	}
 
 	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
@@ -568,6 +793,7 @@ func handlePickWinner(ctx context.Context, s *discordgo.Session, i *discordgo.In
 			Embeds: []*discordgo.MessageEmbed{embed},
 		},
 	})
+	checkStock(ctx, s)
 }
 
 // Helper functions
@@ -586,6 +812,29 @@ func isMaester(s *discordgo.Session, member *discordgo.Member) bool {
 	return false
 }
 
+// durationFromEnv reads a duration such as "6h" from the environment
+func durationFromEnv(key string, fallback time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		log.Printf("Invalid %s %q, using %v", key, value, fallback)
+		return fallback
+	}
+	return d
+}
+
+// optionMap indexes command options by name
+func optionMap(options []*discordgo.ApplicationCommandInteractionDataOption) map[string]*discordgo.ApplicationCommandInteractionDataOption {
+	m := make(map[string]*discordgo.ApplicationCommandInteractionDataOption, len(options))
+	for _, opt := range options {
+		m[opt.Name] = opt
+	}
+	return m
+}
+
 func respondError(s *discordgo.Session, i *discordgo.InteractionCreate, message string) {
 	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 		Type: discordgo.InteractionResponseChannelMessageWithSource,
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"flavaflav/internal/db"
+	"flavaflav/internal/models"
+	"flavaflav/internal/notify"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// eligibilityNoticeWindow limits eligibility DMs to members who crossed a
+// threshold recently, so a first run doesn't message long-standing members
+const eligibilityNoticeWindow = 7
+
+var notificationsCommand = &discordgo.ApplicationCommand{
+	Name:        "notifications",
+	Description: "View or change which direct messages the bot sends you",
+	Options: []*discordgo.ApplicationCommandOption{
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        "type",
+			Description: "Notification type to change",
+			Required:    false,
+			Choices: []*discordgo.ApplicationCommandOptionChoice{
+				{Name: "All", Value: "all"},
+				{Name: "Draw wins", Value: models.NotifyWins},
+				{Name: "Links received", Value: models.NotifyDistributions},
+				{Name: "New eligibility", Value: models.NotifyEligibility},
+				{Name: "Promotions", Value: models.NotifyPromotions},
+			},
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionBoolean,
+			Name:        "enabled",
+			Description: "Whether to receive this notification",
+			Required:    false,
+		},
+	},
+}
+
+func handleNotifications(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) {
+	member, err := dbClient.GetMember(ctx, i.Member.User.ID)
+	if err != nil {
+		respondError(s, i, "You are not registered as a guild member. Contact a Maester to be added.")
+		return
+	}
+
+	options := optionMap(i.ApplicationCommandData().Options)
+	kindOpt, hasKind := options["type"]
+	enabledOpt, hasEnabled := options["enabled"]
+
+	if hasKind != hasEnabled {
+		respondError(s, i, "Provide both type and enabled to change a setting, or neither to view them.")
+		return
+	}
+
+	if hasKind {
+		kinds := []string{kindOpt.StringValue()}
+		if kinds[0] == "all" {
+			kinds = models.AllNotificationKinds
+		}
+		member, err = dbClient.UpdateMemberNotifications(ctx, member.DiscordID, kinds, enabledOpt.BoolValue())
+		if errors.Is(err, db.ErrMemberNotFound) {
+			respondError(s, i, "You are no longer registered as a guild member.")
+			return
+		}
+		if err != nil {
+			respondError(s, i, "Failed to save notification settings")
+			return
+		}
+	}
+
+	var lines []string
+	for _, kind := range models.AllNotificationKinds {
+		lines = append(lines, fmt.Sprintf("%s %s", boolToEmoji(member.WantsNotification(kind)), strings.Title(kind)))
+	}
+
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{{
+				Title:       "Notification Settings",
+				Color:       0x00ff00,
+				Description: strings.Join(lines, "\n"),
+			}},
+			Flags: discordgo.MessageFlagsEphemeral,
+		},
+	})
+}
+
+// notifyMember sends a DM and logs failures; notifications never block a command
+func notifyMember(member *models.Member, kind string, embed *discordgo.MessageEmbed) {
+	if err := messenger.NotifyMember(member, kind, embed); err != nil {
+		log.Printf("Notification %s failed: %v", kind, err)
+	}
+}
+
+// watchEligibility periodically refreshes member ranks, saves any changes and
+// tells members when they become eligible for a new link quality
+func watchEligibility(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		checkEligibility(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func checkEligibility(ctx context.Context) {
+	members, err := dbClient.GetAllMembers(ctx)
+	if err != nil {
+		log.Printf("Eligibility check failed: %v", err)
+		return
+	}
+
+	for _, member := range members {
+		change := member.UpdateRankAndEligibility()
+		if !change.Changed(member) {
+			continue
+		}
+
+		err := dbClient.UpdateMemberEligibility(ctx, member)
+		if errors.Is(err, db.ErrMemberChanged) {
+			continue // removed or promoted since the scan; the next check picks it up
+		}
+		if err != nil {
+			log.Printf("Failed to save eligibility for %s: %v", member.Username, err)
+			continue
+		}
+
+		if member.IsOfficer {
+			continue
+		}
+		change.NewlySilver = change.NewlySilver && member.DaysInGuild < models.SilverEligibilityDays+eligibilityNoticeWindow
+		change.NewlyGold = change.NewlyGold && member.DaysInGuild < models.GoldEligibilityDays+eligibilityNoticeWindow
+		if change.NewlySilver || change.NewlyGold {
+			notifyMember(member, models.NotifyEligibility, notify.EligibilityEmbed(member, change))
+		}
+	}
+}
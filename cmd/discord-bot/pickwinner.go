@@ -0,0 +1,461 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"flavaflav/internal/db"
+	"flavaflav/internal/models"
+	"flavaflav/internal/notify"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Custom ID prefix and actions for the pick-winner buttons and modal
+const (
+	pickWinnerPrefix       = "pick-winner"
+	pickWinnerConfirm      = "confirm"
+	pickWinnerReserve      = "reserve"
+	pickWinnerReroll       = "reroll"
+	pickWinnerRerollReason = "reroll-reason"
+	pickWinnerCancel       = "cancel"
+)
+
+func handlePickWinner(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if !isMaester(s, i.Member) {
+		respondError(s, i, "Only Maesters can pick winners.")
+		return
+	}
+
+	options := optionMap(i.ApplicationCommandData().Options)
+	quality := options["quality"].StringValue()
+	var linkType string
+	if opt, ok := options["link_type"]; ok {
+		linkType = opt.StringValue()
+	}
+	matchBuild := false
+	if opt, ok := options["match_build"]; ok {
+		matchBuild = opt.BoolValue()
+	}
+	if matchBuild && linkType == "" {
+		respondError(s, i, "Choose a link_type to match builds against.")
+		return
+	}
+
+	list, err := findOrCreateActiveList(ctx, quality, i.Member.User.ID)
+	if err != nil {
+		respondError(s, i, err.Error())
+		return
+	}
+
+	winner, err := drawFromList(ctx, list, linkType, matchBuild, i.Member.User.ID)
+	if err != nil {
+		respondError(s, i, err.Error())
+		return
+	}
+
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds:     []*discordgo.MessageEmbed{winnerEmbed(list, winner)},
+			Components: pickWinnerButtons(list.ListID),
+		},
+	})
+}
+
+// findOrCreateActiveList reuses the newest active list for a quality that still
+// has members, or creates a new one from currently eligible members
+func findOrCreateActiveList(ctx context.Context, quality, createdBy string) (*models.DistributionList, error) {
+	lists, err := dbClient.GetActiveDistributionLists(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get distribution lists")
+	}
+
+	var current *models.DistributionList
+	for _, list := range lists {
+		if list.Quality != quality || list.GetMemberCount() == 0 {
+			continue
+		}
+		if current == nil || list.CreatedAt.After(current.CreatedAt) {
+			current = list
+		}
+	}
+	if current != nil {
+		return current, nil
+	}
+
+	listName := fmt.Sprintf("%s Links - %s", strings.Title(quality), time.Now().Format("January 2006"))
+	return createListForQuality(ctx, listName, quality, createdBy)
+}
+
+// createListForQuality creates and saves a list of the members currently eligible for a quality
+func createListForQuality(ctx context.Context, listName, quality, createdBy string) (*models.DistributionList, error) {
+	members, err := dbClient.GetAllMembers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get members")
+	}
+
+	var eligibleMemberIDs []string
+	for _, member := range filterByAttendance(ctx, models.FilterEligibleMembers(members, quality)) {
+		eligibleMemberIDs = append(eligibleMemberIDs, member.DiscordID)
+	}
+	if len(eligibleMemberIDs) == 0 {
+		return nil, fmt.Errorf("No members eligible for %s links", quality)
+	}
+
+	list := models.NewDistributionList(listName, quality, eligibleMemberIDs, createdBy)
+	if err := dbClient.CreateDistributionList(ctx, list); err != nil {
+		return nil, fmt.Errorf("Failed to create distribution list")
+	}
+
+	return list, nil
+}
+
+// noneLeftError reports that nobody on a list could be drawn. The list then
+// differs from what was read only by members who had left being taken off it.
+type noneLeftError struct {
+	message string
+}
+
+func (e *noneLeftError) Error() string {
+	return e.message
+}
+
+// drawFromList picks a winner who has not been marked absent this round and,
+// when matching builds, whose build tags suit the link type, and records the
+// pending draw on the list. Members who have left since the list was made are
+// taken off it and another winner is drawn. If nobody is left it returns a
+// *noneLeftError without saving the list.
+func drawFromList(ctx context.Context, list *models.DistributionList, linkType string, matchBuild bool, drawnBy string) (*models.Member, error) {
+	exclude := list.AbsentMembers()
+	if matchBuild {
+		members, err := dbClient.GetAllMembers(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to get members")
+		}
+		exclude = append(exclude, models.UnsuitedMembers(members, linkType)...)
+	}
+
+	entries := drawEntries(ctx)
+	var winner *models.Member
+	for winner == nil {
+		winnerID, ok := list.PickWeightedMember(exclude, entries)
+		if !ok {
+			if matchBuild {
+				return nil, &noneLeftError{fmt.Sprintf("No eligible members left in %s whose build suits %s", list.ListName, linkType)}
+			}
+			return nil, &noneLeftError{fmt.Sprintf("No eligible members left to draw from %s", list.ListName)}
+		}
+
+		member, err := dbClient.GetMember(ctx, winnerID)
+		if errors.Is(err, db.ErrMemberNotFound) {
+			log.Printf("Removing %s from list %s: no longer a registered member", winnerID, list.ListID)
+			list.RemoveMember(winnerID)
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("Failed to get winner")
+		}
+		winner = member
+	}
+	winner.UpdateRankAndEligibility()
+
+	list.RecordDraw(winner.DiscordID, winner.Username, linkType, drawnBy).MatchBuild = matchBuild
+	err := dbClient.UpdateDistributionList(ctx, list)
+	if errors.Is(err, db.ErrListChanged) {
+		return nil, fmt.Errorf("%s was just changed by someone else. Draw again", list.ListName)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Failed to record draw")
+	}
+
+	notifyMember(winner, models.NotifyWins, notify.WinEmbed(list))
+
+	return winner, nil
+}
+
+func handlePickWinnerComponent(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate, action, listID string) {
+	if !isMaester(s, i.Member) {
+		respondError(s, i, "Only Maesters can resolve draws.")
+		return
+	}
+
+	list, err := dbClient.GetDistributionList(ctx, listID)
+	if err != nil {
+		respondError(s, i, "Distribution list not found")
+		return
+	}
+
+	pending := list.PendingDraw()
+	if pending == nil {
+		respondError(s, i, "This draw has already been resolved.")
+		return
+	}
+
+	switch action {
+	case pickWinnerConfirm:
+		confirmDraw(ctx, s, i, list, pending)
+	case pickWinnerReserve:
+		reserveDraw(ctx, s, i, list, pending)
+	case pickWinnerReroll:
+		s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseModal,
+			Data: &discordgo.InteractionResponseData{
+				CustomID: pickWinnerPrefix + ":" + pickWinnerRerollReason + ":" + list.ListID,
+				Title:    "Re-roll winner",
+				Components: []discordgo.MessageComponent{
+					discordgo.ActionsRow{
+						Components: []discordgo.MessageComponent{
+							discordgo.TextInput{
+								CustomID:    "reason",
+								Label:       "Why is " + pending.MemberUsername + " being skipped?",
+								Style:       discordgo.TextInputParagraph,
+								Placeholder: "e.g. not online for the hand-over",
+								Required:    true,
+								MaxLength:   200,
+							},
+						},
+					},
+				},
+			},
+		})
+	case pickWinnerCancel:
+		list.ResolvePendingDraw(pending.MemberID, models.DrawCancelled, "", i.Member.User.ID)
+		err := dbClient.UpdateDistributionList(ctx, list)
+		if errors.Is(err, db.ErrListChanged) {
+			respondError(s, i, "This list was just changed by someone else. Press Cancel again.")
+			return
+		}
+		if err != nil {
+			respondError(s, i, "Failed to cancel draw")
+			return
+		}
+		updateDrawMessage(s, i, &discordgo.MessageEmbed{
+			Title:       "Draw Cancelled",
+			Color:       0x666666,
+			Description: fmt.Sprintf("The draw for **%s** was cancelled. No link was distributed.", pending.MemberUsername),
+		}, false, list.ListID)
+	}
+}
+
+func handlePickWinnerModal(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate, listID string) {
+	if !isMaester(s, i.Member) {
+		respondError(s, i, "Only Maesters can resolve draws.")
+		return
+	}
+
+	reason := strings.TrimSpace(modalTextValue(i.ModalSubmitData(), "reason"))
+	if reason == "" {
+		respondError(s, i, "A reason is required to re-roll.")
+		return
+	}
+
+	list, err := dbClient.GetDistributionList(ctx, listID)
+	if err != nil {
+		respondError(s, i, "Distribution list not found")
+		return
+	}
+
+	pending := list.PendingDraw()
+	if pending == nil {
+		respondError(s, i, "This draw has already been resolved.")
+		return
+	}
+	absent := pending.MemberUsername
+	linkType, matchBuild := pending.LinkType, pending.MatchBuild
+	list.ResolvePendingDraw(pending.MemberID, models.DrawRerolled, reason, i.Member.User.ID)
+
+	winner, err := drawFromList(ctx, list, linkType, matchBuild, i.Member.User.ID)
+	var noneLeft *noneLeftError
+	if err != nil && !errors.As(err, &noneLeft) {
+		// The list was not saved, so the skipped winner is still pending
+		respondError(s, i, fmt.Sprintf("%s. %s is still the pending winner; try the re-roll again.", strings.TrimSuffix(err.Error(), "."), absent))
+		return
+	}
+	if err != nil {
+		// Nobody left to draw; still persist the re-roll so history is complete
+		if saveErr := dbClient.UpdateDistributionList(ctx, list); saveErr != nil {
+			log.Printf("Failed to save re-roll of %s on list %s: %v", absent, list.ListID, saveErr)
+			respondError(s, i, fmt.Sprintf("%s. The re-roll was not saved, so %s is still the pending winner; try again.", err.Error(), absent))
+			return
+		}
+		updateDrawMessage(s, i, &discordgo.MessageEmbed{
+			Title:       "Re-roll Failed",
+			Color:       0x666666,
+			Description: fmt.Sprintf("%s was skipped (%s). %s.", absent, reason, err.Error()),
+		}, false, list.ListID)
+		return
+	}
+
+	embed := winnerEmbed(list, winner)
+	embed.Footer = &discordgo.MessageEmbedFooter{Text: fmt.Sprintf("Re-rolled: %s was skipped (%s)", absent, reason)}
+	updateDrawMessage(s, i, embed, true, list.ListID)
+}
+
+// confirmDraw hands the oldest matching available link to the pending winner
+func confirmDraw(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate, list *models.DistributionList, pending *models.DrawRecord) {
+	link, err := linkForDraw(ctx, list, pending)
+	if err != nil {
+		respondError(s, i, err.Error())
+		return
+	}
+
+	member, err := dbClient.GetMember(ctx, pending.MemberID)
+	if err != nil {
+		respondError(s, i, "Winner is no longer a registered member")
+		return
+	}
+
+	link.MarkDistributed()
+	distribution := models.NewDistribution(
+		member.DiscordID,
+		member.Username,
+		link.LinkID,
+		link.LinkType,
+		link.Quality,
+		link.Bonus,
+		"discord",
+		i.Member.User.ID,
+	)
+	distribution.ListID = list.ListID
+	distribution.Character = member.PrimaryCharacter()
+
+	list.RemoveMember(member.DiscordID)
+	list.ResolvePendingDraw(member.DiscordID, models.DrawConfirmed, "", i.Member.User.ID)
+
+	err = dbClient.CompleteDistribution(ctx, link, distribution, list)
+	if errors.Is(err, db.ErrLinkUnavailable) {
+		respondError(s, i, "That link was just distributed by someone else. Press Confirm again.")
+		return
+	}
+	if errors.Is(err, db.ErrListChanged) {
+		respondError(s, i, "This list was just changed by someone else. Press Confirm again.")
+		return
+	}
+	if err != nil {
+		respondError(s, i, "Failed to record distribution")
+		return
+	}
+
+	notifyMember(member, models.NotifyDistributions, notify.DistributionEmbed(distribution))
+
+	embed := &discordgo.MessageEmbed{
+		Title:       "Link Distributed",
+		Color:       getQualityColor(link.Quality),
+		Description: fmt.Sprintf("**%s** received %s", member.Username, link.GetDisplayName()),
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "List", Value: list.ListName, Inline: true},
+			{Name: "Remaining in List", Value: strconv.Itoa(list.GetMemberCount()), Inline: true},
+		},
+		Timestamp: time.Now().Format(time.RFC3339),
+	}
+	if distribution.Character != "" {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "Character", Value: distribution.Character, Inline: true})
+	}
+	updateDrawMessage(s, i, embed, false, list.ListID)
+	checkStock(ctx, s)
+}
+
+// linkForDraw returns the oldest available link matching the list's quality
+// and the pending draw's link type
+func linkForDraw(ctx context.Context, list *models.DistributionList, pending *models.DrawRecord) (*models.InventoryLink, error) {
+	links, err := dbClient.GetAvailableInventoryLinksByQuality(ctx, list.Quality)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get inventory")
+	}
+
+	var candidates []*models.InventoryLink
+	for _, link := range links {
+		if pending.LinkType == "" || strings.EqualFold(link.LinkType, pending.LinkType) {
+			candidates = append(candidates, link)
+		}
+	}
+	if len(candidates) == 0 {
+		if pending.LinkType != "" {
+			return nil, fmt.Errorf("No available %s %s links in inventory", list.Quality, pending.LinkType)
+		}
+		return nil, fmt.Errorf("No available %s links in inventory", list.Quality)
+	}
+	sort.Slice(candidates, func(a, b int) bool {
+		return candidates[a].AddedDate.Before(candidates[b].AddedDate)
+	})
+	return candidates[0], nil
+}
+
+func winnerEmbed(list *models.DistributionList, winner *models.Member) *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{
+		Title:       fmt.Sprintf("🎉 %s Link Winner!", strings.Title(list.Quality)),
+		Color:       getQualityColor(list.Quality),
+		Description: fmt.Sprintf("**%s** has been selected for a %s link!", winner.Username, list.Quality),
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "Winner Rank", Value: winner.Rank, Inline: true},
+			{Name: "Days in Guild", Value: strconv.Itoa(winner.DaysInGuild), Inline: true},
+			{Name: "Total Eligible", Value: strconv.Itoa(list.GetMemberCount()), Inline: true},
+			{Name: "List", Value: list.ListName, Inline: true},
+		},
+		Timestamp: time.Now().Format(time.RFC3339),
+	}
+	if pending := list.PendingDraw(); pending != nil && pending.LinkType != "" {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "Link Type", Value: pending.LinkType, Inline: true})
+	}
+	if field := buildField(winner); field != nil {
+		embed.Fields = append(embed.Fields, field)
+	}
+	if field := charactersField("Trade To", winner); field != nil {
+		embed.Fields = append(embed.Fields, field)
+	}
+	return embed
+}
+
+func pickWinnerButtons(listID string) []discordgo.MessageComponent {
+	customID := func(action string) string {
+		return pickWinnerPrefix + ":" + action + ":" + listID
+	}
+	return []discordgo.MessageComponent{
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.Button{Label: "Confirm", Style: discordgo.SuccessButton, CustomID: customID(pickWinnerConfirm)},
+				discordgo.Button{Label: "Winner offline — reserve", Style: discordgo.SecondaryButton, CustomID: customID(pickWinnerReserve)},
+				discordgo.Button{Label: "Winner absent — re-roll", Style: discordgo.PrimaryButton, CustomID: customID(pickWinnerReroll)},
+				discordgo.Button{Label: "Cancel", Style: discordgo.DangerButton, CustomID: customID(pickWinnerCancel)},
+			},
+		},
+	}
+}
+
+// updateDrawMessage replaces the draw message, keeping the buttons only while a draw is pending
+func updateDrawMessage(s *discordgo.Session, i *discordgo.InteractionCreate, embed *discordgo.MessageEmbed, pending bool, listID string) {
+	components := []discordgo.MessageComponent{}
+	if pending {
+		components = pickWinnerButtons(listID)
+	}
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Embeds:     []*discordgo.MessageEmbed{embed},
+			Components: components,
+		},
+	})
+}
+
+// modalTextValue returns the value of a text input in a submitted modal
+func modalTextValue(data discordgo.ModalSubmitInteractionData, customID string) string {
+	for _, row := range data.Components {
+		actionsRow, ok := row.(*discordgo.ActionsRow)
+		if !ok {
+			continue
+		}
+		for _, component := range actionsRow.Components {
+			if input, ok := component.(*discordgo.TextInput); ok && input.CustomID == customID {
+				return input.Value
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"flavaflav/internal/report"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+var reportCommand = &discordgo.ApplicationCommand{
+	Name:        "report",
+	Description: "Post the distribution report as Markdown and CSV (Maester only)",
+	Options: []*discordgo.ApplicationCommandOption{
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        "month",
+			Description: "Month to report on as YYYY-MM (default: last month)",
+			Required:    false,
+		},
+		{
+			Type:         discordgo.ApplicationCommandOptionChannel,
+			Name:         "channel",
+			Description:  "Channel to post the report in (default: here)",
+			Required:     false,
+			ChannelTypes: []discordgo.ChannelType{discordgo.ChannelTypeGuildText},
+		},
+	},
+}
+
+func handleReport(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if !isMaester(s, i.Member) {
+		respondError(s, i, "Only Maesters can post reports.")
+		return
+	}
+
+	options := optionMap(i.ApplicationCommandData().Options)
+	var month string
+	if opt, ok := options["month"]; ok {
+		month = strings.TrimSpace(opt.StringValue())
+	}
+
+	loc, err := time.LoadLocation(guildTimeZone)
+	if err != nil {
+		loc = time.UTC
+	}
+	now := time.Now()
+	rng, err := report.ParseRange(month, "", "", loc, now)
+	if err != nil {
+		respondError(s, i, err.Error())
+		return
+	}
+
+	distributions, err := dbClient.GetAllDistributions(ctx)
+	if err != nil {
+		respondError(s, i, "Failed to get distribution history")
+		return
+	}
+
+	links, err := dbClient.GetAllInventoryLinks(ctx)
+	if err != nil {
+		respondError(s, i, "Failed to get inventory")
+		return
+	}
+
+	lists, err := dbClient.GetAllDistributionLists(ctx)
+	if err != nil {
+		respondError(s, i, "Failed to get distribution lists")
+		return
+	}
+
+	result := report.Build(distributions, links, lists, rng, now)
+
+	var csvData bytes.Buffer
+	if err := result.WriteCSV(&csvData); err != nil {
+		respondError(s, i, "Failed to render the report")
+		return
+	}
+	files := []*discordgo.File{
+		{Name: result.Filename(report.FormatMarkdown), ContentType: "text/markdown", Reader: strings.NewReader(result.Markdown())},
+		{Name: result.Filename(report.FormatCSV), ContentType: "text/csv", Reader: &csvData},
+	}
+	embed := reportEmbed(result)
+
+	if opt, ok := options["channel"]; ok {
+		channel := opt.ChannelValue(s)
+		_, err := s.ChannelMessageSendComplex(channel.ID, &discordgo.MessageSend{
+			Embeds: []*discordgo.MessageEmbed{embed},
+			Files:  files,
+		})
+		if err != nil {
+			respondError(s, i, fmt.Sprintf("Failed to post the report in <#%s>: %v", channel.ID, err))
+			return
+		}
+		s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Content: fmt.Sprintf("✅ Posted the %s report in <#%s>", rng.Name, channel.ID),
+				Flags:   discordgo.MessageFlagsEphemeral,
+			},
+		})
+		return
+	}
+
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{embed},
+			Files:  files,
+		},
+	})
+}
+
+// reportEmbed summarises a report next to its attached files
+func reportEmbed(result *report.Report) *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{
+		Title: "Distribution Report: " + result.Range.Name,
+		Color: 0x00ff00,
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "Links Distributed", Value: fmt.Sprintf("%d %s", result.Distributions.Total, formatQualityCounts(result.Distributions.ByQuality)), Inline: true},
+			{Name: "Members Awarded", Value: strconv.Itoa(len(result.ByMember)), Inline: true},
+			{Name: "Draws Run", Value: strconv.Itoa(result.DrawsRun), Inline: true},
+			{Name: "Links Added", Value: fmt.Sprintf("%d %s", result.InventoryAdded.Total, formatQualityCounts(result.InventoryAdded.ByQuality)), Inline: true},
+			{Name: "Available Now", Value: fmt.Sprintf("%d %s", result.Remaining.Total, formatQualityCounts(result.Remaining.ByQuality)), Inline: true},
+		},
+		Footer: &discordgo.MessageEmbedFooter{Text: "Full breakdown in the attached Markdown and CSV files"},
+	}
+	if result.Reversed > 0 {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "Reversed", Value: strconv.Itoa(result.Reversed), Inline: true})
+	}
+	return embed
+}
@@ -0,0 +1,357 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"flavaflav/internal/db"
+	"flavaflav/internal/models"
+	"flavaflav/internal/notify"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Custom ID prefix and actions for the reservation buttons
+const (
+	reservationPrefix   = "reservation"
+	reservationHandOver = "handover"
+	reservationRelease  = "release"
+)
+
+// reservationDuration is how long a link is held for an offline winner,
+// set from RESERVATION_DURATION at startup
+var reservationDuration = models.DefaultReservationDuration
+
+var reservationCommand = &discordgo.ApplicationCommand{
+	Name:        "reservation",
+	Description: "Manage links held for draw winners (Maester only)",
+	Options: []*discordgo.ApplicationCommandOption{
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "list",
+			Description: "List reserved links and who they are held for",
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "handover",
+			Description: "Record that a reserved link was handed over in game",
+			Options:     []*discordgo.ApplicationCommandOption{linkIDOption},
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "release",
+			Description: "Return a reserved link to stock and put the winner back on the list",
+			Options:     []*discordgo.ApplicationCommandOption{linkIDOption},
+		},
+	},
+}
+
+// reserveDraw holds the oldest matching available link for the pending winner
+func reserveDraw(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate, list *models.DistributionList, pending *models.DrawRecord) {
+	link, err := linkForDraw(ctx, list, pending)
+	if err != nil {
+		respondError(s, i, err.Error())
+		return
+	}
+
+	until := time.Now().Add(reservationDuration)
+	if err := link.Reserve(pending.MemberID, pending.MemberUsername, list.ListID, until, i.Member.User.ID); err != nil {
+		respondError(s, i, err.Error())
+		return
+	}
+	list.RemoveMember(pending.MemberID)
+	list.ResolvePendingDraw(pending.MemberID, models.DrawReserved, "", i.Member.User.ID)
+
+	err = dbClient.ReserveLink(ctx, link, list)
+	if errors.Is(err, db.ErrLinkUnavailable) {
+		respondError(s, i, "That link was just taken by someone else. Press Reserve again.")
+		return
+	}
+	if errors.Is(err, db.ErrListChanged) {
+		respondError(s, i, "This list was just changed by someone else. Press Reserve again.")
+		return
+	}
+	if err != nil {
+		respondError(s, i, "Failed to reserve link")
+		return
+	}
+
+	embed := reservedEmbed(link, list)
+	if member, err := dbClient.GetMember(ctx, pending.MemberID); err == nil {
+		if field := charactersField("Trade To", member); field != nil {
+			embed.Fields = append(embed.Fields, field)
+		}
+	}
+
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Embeds:     []*discordgo.MessageEmbed{embed},
+			Components: reservationButtons(link.LinkID),
+		},
+	})
+	checkStock(ctx, s)
+}
+
+func handleReservation(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if !isMaester(s, i.Member) {
+		respondError(s, i, "Only Maesters can manage reservations.")
+		return
+	}
+
+	sub := i.ApplicationCommandData().Options[0]
+	options := optionMap(sub.Options)
+
+	switch sub.Name {
+	case "list":
+		handleReservationList(ctx, s, i)
+	case "handover":
+		embed, err := handOverReservation(ctx, options["link_id"].StringValue(), i.Member.User.ID)
+		if err != nil {
+			respondError(s, i, err.Error())
+			return
+		}
+		respondEmbed(s, i, embed)
+	case "release":
+		embed, err := releaseReservation(ctx, options["link_id"].StringValue(), "released by an officer", i.Member.User.ID)
+		if err != nil {
+			respondError(s, i, err.Error())
+			return
+		}
+		respondEmbed(s, i, embed)
+		checkStock(ctx, s)
+	}
+}
+
+func handleReservationComponent(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate, action, linkID string) {
+	if !isMaester(s, i.Member) {
+		respondError(s, i, "Only Maesters can manage reservations.")
+		return
+	}
+
+	var embed *discordgo.MessageEmbed
+	var err error
+	switch action {
+	case reservationHandOver:
+		embed, err = handOverReservation(ctx, linkID, i.Member.User.ID)
+	case reservationRelease:
+		embed, err = releaseReservation(ctx, linkID, "released by an officer", i.Member.User.ID)
+	default:
+		return
+	}
+	if err != nil {
+		respondError(s, i, err.Error())
+		return
+	}
+
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Embeds:     []*discordgo.MessageEmbed{embed},
+			Components: []discordgo.MessageComponent{},
+		},
+	})
+	if action == reservationRelease {
+		checkStock(ctx, s)
+	}
+}
+
+func handleReservationList(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) {
+	links, err := dbClient.GetReservedInventoryLinks(ctx)
+	if err != nil {
+		respondError(s, i, "Failed to get reservations")
+		return
+	}
+	sort.Slice(links, func(a, b int) bool {
+		return links[a].ReservedUntil.Before(links[b].ReservedUntil)
+	})
+
+	embed := &discordgo.MessageEmbed{Title: "Reserved Links", Color: 0x3498db}
+	if len(links) == 0 {
+		embed.Description = "No links are reserved"
+		respondEmbed(s, i, embed)
+		return
+	}
+
+	var lines []string
+	for n, link := range links {
+		if n == maxLinksListed {
+			lines = append(lines, fmt.Sprintf("…and %d more", len(links)-n))
+			break
+		}
+		lines = append(lines, fmt.Sprintf("%s `%s` %s for **%s**, expires <t:%d:R>",
+			getQualityEmoji(link.Quality), link.LinkID, link.GetDisplayName(), link.ReservedForUsername, link.ReservedUntil.Unix()))
+	}
+	embed.Description = truncate(strings.Join(lines, "\n"), 4096)
+	respondEmbed(s, i, embed)
+}
+
+// handOverReservation turns a reservation into a distribution
+func handOverReservation(ctx context.Context, linkID, by string) (*discordgo.MessageEmbed, error) {
+	link, err := dbClient.GetInventoryLink(ctx, linkID)
+	if err != nil {
+		return nil, fmt.Errorf("Link not found")
+	}
+	listID := link.ReservedListID
+
+	distribution, err := link.HandOver("discord", by)
+	if err != nil {
+		return nil, err
+	}
+
+	member, memberErr := dbClient.GetMember(ctx, distribution.MemberID)
+	if memberErr == nil {
+		distribution.Character = member.PrimaryCharacter()
+	}
+
+	var list *models.DistributionList
+	if listID != "" {
+		list, err = dbClient.GetDistributionList(ctx, listID)
+		if err != nil {
+			list = nil // the list was removed; nothing to update
+		} else {
+			list.CompleteReservedDraw(distribution.MemberID, by)
+		}
+	}
+
+	err = dbClient.CompleteReservation(ctx, link, distribution, list)
+	if errors.Is(err, db.ErrLinkUnavailable) {
+		return nil, fmt.Errorf("That reservation was already handed over or released.")
+	}
+	if errors.Is(err, db.ErrListChanged) {
+		return nil, fmt.Errorf("The reservation's list was just changed by someone else. Try again.")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Failed to record distribution")
+	}
+
+	if memberErr == nil {
+		notifyMember(member, models.NotifyDistributions, notify.DistributionEmbed(distribution))
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       "Link Distributed",
+		Color:       getQualityColor(link.Quality),
+		Description: fmt.Sprintf("**%s** received %s", distribution.MemberUsername, link.GetDisplayName()),
+		Timestamp:   time.Now().Format(time.RFC3339),
+	}
+	if distribution.Character != "" {
+		embed.Fields = []*discordgo.MessageEmbedField{{Name: "Character", Value: distribution.Character, Inline: true}}
+	}
+	return embed, nil
+}
+
+// releaseReservation returns a reserved link to stock and puts the winner
+// back on the list they were drawn from
+func releaseReservation(ctx context.Context, linkID, reason, by string) (*discordgo.MessageEmbed, error) {
+	link, err := dbClient.GetInventoryLink(ctx, linkID)
+	if err != nil {
+		return nil, fmt.Errorf("Link not found")
+	}
+	memberID, username, listID := link.ReservedFor, link.ReservedForUsername, link.ReservedListID
+
+	if err := link.ReleaseReservation(reason, by); err != nil {
+		return nil, err
+	}
+
+	var list *models.DistributionList
+	if listID != "" {
+		list, err = dbClient.GetDistributionList(ctx, listID)
+		if err != nil {
+			list = nil // the list was removed; nothing to restore
+		} else {
+			list.ReleaseReservedDraw(memberID, reason, by)
+		}
+	}
+
+	err = dbClient.ReleaseReservation(ctx, link, memberID, list)
+	if errors.Is(err, db.ErrLinkChanged) {
+		return nil, fmt.Errorf("That reservation was already handed over or released.")
+	}
+	if errors.Is(err, db.ErrListChanged) {
+		return nil, fmt.Errorf("The reservation's list was just changed by someone else. Try again.")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Failed to release reservation")
+	}
+
+	return &discordgo.MessageEmbed{
+		Title:       "Reservation Released",
+		Color:       0x666666,
+		Description: fmt.Sprintf("%s is back in stock; **%s** (%s) can be drawn again.", link.GetDisplayName(), username, reason),
+	}, nil
+}
+
+// watchReservations releases expired reservations until ctx is cancelled
+func watchReservations(ctx context.Context, s *discordgo.Session, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if releaseExpiredReservations(ctx, time.Now()) > 0 {
+			checkStock(ctx, s)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// releaseExpiredReservations releases reservations past their deadline and
+// returns how many were released
+func releaseExpiredReservations(ctx context.Context, now time.Time) int {
+	links, err := dbClient.GetReservedInventoryLinks(ctx)
+	if err != nil {
+		log.Printf("Reservation check failed: %v", err)
+		return 0
+	}
+
+	released := 0
+	for _, link := range links {
+		if !link.ReservationExpired(now) {
+			continue
+		}
+		if _, err := releaseReservation(ctx, link.LinkID, "reservation expired", "system"); err != nil {
+			log.Printf("Failed to release expired reservation of %s: %v", link.LinkID, err)
+			continue
+		}
+		log.Printf("Released expired reservation of %s for %s", link.LinkID, link.ReservedForUsername)
+		released++
+	}
+	return released
+}
+
+func reservedEmbed(link *models.InventoryLink, list *models.DistributionList) *discordgo.MessageEmbed {
+	return &discordgo.MessageEmbed{
+		Title:       "Link Reserved",
+		Color:       getQualityColor(link.Quality),
+		Description: fmt.Sprintf("%s is held for **%s** until <t:%d:f>", link.GetDisplayName(), link.ReservedForUsername, link.ReservedUntil.Unix()),
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "List", Value: list.ListName, Inline: true},
+			{Name: "Link ID", Value: link.LinkID, Inline: true},
+		},
+		Footer: &discordgo.MessageEmbedFooter{Text: "Press Handed over once the link is traded; unclaimed links return to stock automatically."},
+	}
+}
+
+func reservationButtons(linkID string) []discordgo.MessageComponent {
+	customID := func(action string) string {
+		return reservationPrefix + ":" + action + ":" + linkID
+	}
+	return []discordgo.MessageComponent{
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.Button{Label: "Handed over", Style: discordgo.SuccessButton, CustomID: customID(reservationHandOver)},
+				discordgo.Button{Label: "Release", Style: discordgo.DangerButton, CustomID: customID(reservationRelease)},
+			},
+		},
+	}
+}
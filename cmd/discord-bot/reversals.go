@@ -0,0 +1,146 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"flavaflav/internal/db"
+	"flavaflav/internal/models"
+	"flavaflav/internal/notify"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+var reverseCommand = &discordgo.ApplicationCommand{
+	Name:        "reverse-distribution",
+	Description: "Undo a link given to the wrong member (Maester only)",
+	Options: []*discordgo.ApplicationCommandOption{
+		{
+			Type:        discordgo.ApplicationCommandOptionUser,
+			Name:        "member",
+			Description: "Member who wrongly received the link",
+			Required:    true,
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        "reason",
+			Description: "Why the distribution is being reversed",
+			Required:    true,
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionUser,
+			Name:        "reassign_to",
+			Description: "Give the link to this member instead (otherwise it returns to stock)",
+			Required:    false,
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        "distribution_id",
+			Description: "Distribution to reverse (defaults to the member's most recent)",
+			Required:    false,
+		},
+	},
+}
+
+func handleReverseDistribution(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if !isMaester(s, i.Member) {
+		respondError(s, i, "Only Maesters can reverse distributions.")
+		return
+	}
+
+	options := optionMap(i.ApplicationCommandData().Options)
+	wrongMember := options["member"].UserValue(s)
+	reason := options["reason"].StringValue()
+
+	original, err := findDistributionToReverse(ctx, wrongMember.ID, options)
+	if err != nil {
+		respondError(s, i, err.Error())
+		return
+	}
+
+	link, err := dbClient.GetInventoryLink(ctx, original.LinkID)
+	if err != nil {
+		respondError(s, i, "Link not found")
+		return
+	}
+
+	var newMember *models.Member
+	if opt, ok := options["reassign_to"]; ok {
+		newMember, err = dbClient.GetMember(ctx, opt.UserValue(s).ID)
+		if err != nil {
+			respondError(s, i, "Member to reassign to is not registered")
+			return
+		}
+	}
+
+	var list *models.DistributionList
+	if original.ListID != "" {
+		list, err = dbClient.GetDistributionList(ctx, original.ListID)
+		if err != nil {
+			list = nil // the list was removed; nothing to restore
+		}
+	}
+
+	replacement, err := models.ReverseDistribution(original, link, list, newMember, reason, "discord", i.Member.User.ID)
+	if err != nil {
+		respondError(s, i, err.Error())
+		return
+	}
+
+	err = dbClient.ReverseDistribution(ctx, original, link, replacement, list)
+	if errors.Is(err, db.ErrAlreadyReversed) || errors.Is(err, db.ErrLinkChanged) || errors.Is(err, db.ErrListChanged) {
+		respondError(s, i, "That distribution or link was just changed by someone else.")
+		return
+	}
+	if err != nil {
+		respondError(s, i, "Failed to reverse distribution")
+		return
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       "Distribution Reversed",
+		Color:       0xff9900,
+		Description: fmt.Sprintf("%s taken back from **%s**", link.GetDisplayName(), original.MemberUsername),
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "Reason", Value: reason},
+		},
+	}
+	if replacement != nil {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "Reassigned To", Value: replacement.MemberUsername, Inline: true})
+		notifyMember(newMember, models.NotifyDistributions, notify.DistributionEmbed(replacement))
+	} else {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "Link", Value: "Returned to stock", Inline: true})
+	}
+	if list != nil {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "List", Value: fmt.Sprintf("%s is back on %s", original.MemberUsername, list.ListName), Inline: true})
+	}
+
+	respondEmbed(s, i, embed)
+	checkStock(ctx, s)
+}
+
+// findDistributionToReverse returns the requested distribution, or the
+// member's most recent one that hasn't been reversed
+func findDistributionToReverse(ctx context.Context, memberID string, options map[string]*discordgo.ApplicationCommandInteractionDataOption) (*models.Distribution, error) {
+	if opt, ok := options["distribution_id"]; ok {
+		distribution, err := dbClient.GetDistribution(ctx, opt.StringValue())
+		if err != nil {
+			return nil, fmt.Errorf("Distribution not found")
+		}
+		if distribution.MemberID != memberID {
+			return nil, fmt.Errorf("That distribution belongs to %s", distribution.MemberUsername)
+		}
+		return distribution, nil
+	}
+
+	distributions, err := dbClient.GetDistributionsByMember(ctx, memberID)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get distribution history")
+	}
+	active := models.ActiveDistributions(distributions)
+	if len(active) == 0 {
+		return nil, fmt.Errorf("That member has no distributions to reverse")
+	}
+	return active[0], nil // newest first
+}
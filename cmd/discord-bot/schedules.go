@@ -0,0 +1,420 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"flavaflav/internal/db"
+	"flavaflav/internal/models"
+	"flavaflav/internal/schedule"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// defaultRemindAfterHours is used when a schedule is created without remind_after_hours
+const defaultRemindAfterHours = 48
+
+var scheduleCommand = &discordgo.ApplicationCommand{
+	Name:        "schedule",
+	Description: "Manage recurring distribution rounds (Maester only)",
+	Options: []*discordgo.ApplicationCommandOption{
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "create",
+			Description: "Create a recurring round",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "name",
+					Description: "Schedule name, e.g. Monthly Gold Round",
+					Required:    true,
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "quality",
+					Description: "Quality of links to distribute",
+					Required:    true,
+					Choices: []*discordgo.ApplicationCommandOptionChoice{
+						{Name: "Silver", Value: "silver"},
+						{Name: "Gold", Value: "gold"},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "cron",
+					Description: "When rounds open, e.g. \"0 20 1 * *\" for 20:00 on the 1st",
+					Required:    true,
+				},
+				{
+					Type:         discordgo.ApplicationCommandOptionChannel,
+					Name:         "channel",
+					Description:  "Channel for announcements and reminders",
+					Required:     true,
+					ChannelTypes: []discordgo.ChannelType{discordgo.ChannelTypeGuildText},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionInteger,
+					Name:        "draw_after_minutes",
+					Description: "Automatically draw a winner this many minutes after the round opens",
+					Required:    false,
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionInteger,
+					Name:        "remind_after_hours",
+					Description: "Remind officers when a winner is pending this long (default 48, 0 = never)",
+					Required:    false,
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "timezone",
+					Description: "IANA time zone, e.g. America/Chicago (defaults to the guild time zone)",
+					Required:    false,
+				},
+			},
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "list",
+			Description: "List recurring rounds",
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "delete",
+			Description: "Delete a recurring round",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "schedule_id",
+					Description: "ID shown by /schedule list",
+					Required:    true,
+				},
+			},
+		},
+	},
+}
+
+func handleSchedule(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if !isMaester(s, i.Member) {
+		respondError(s, i, "Only Maesters can manage schedules.")
+		return
+	}
+
+	if !dbClient.SchedulesEnabled() {
+		respondError(s, i, "Schedules are not configured. Set DYNAMODB_SCHEDULES_TABLE.")
+		return
+	}
+
+	sub := i.ApplicationCommandData().Options[0]
+	options := optionMap(sub.Options)
+
+	switch sub.Name {
+	case "create":
+		handleScheduleCreate(ctx, s, i, options)
+	case "list":
+		handleScheduleList(ctx, s, i)
+	case "delete":
+		scheduleID := options["schedule_id"].StringValue()
+		if _, err := dbClient.GetSchedule(ctx, scheduleID); err != nil {
+			respondError(s, i, "Schedule not found")
+			return
+		}
+		if err := dbClient.DeleteSchedule(ctx, scheduleID); err != nil {
+			respondError(s, i, "Failed to delete schedule")
+			return
+		}
+		respondEmbed(s, i, &discordgo.MessageEmbed{
+			Title:       "Schedule Deleted",
+			Color:       0x00ff00,
+			Description: fmt.Sprintf("Schedule `%s` will no longer run.", scheduleID),
+		})
+	}
+}
+
+func handleScheduleCreate(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate, options map[string]*discordgo.ApplicationCommandInteractionDataOption) {
+	cronExpr := options["cron"].StringValue()
+	cron, err := schedule.ParseCron(cronExpr)
+	if err != nil {
+		respondError(s, i, fmt.Sprintf("Invalid cron expression: %v", err))
+		return
+	}
+
+	timeZone := guildTimeZone
+	if opt, ok := options["timezone"]; ok {
+		timeZone = opt.StringValue()
+	}
+	loc, err := time.LoadLocation(timeZone)
+	if err != nil {
+		respondError(s, i, fmt.Sprintf("Unknown time zone %q", timeZone))
+		return
+	}
+
+	drawAfter := 0
+	if opt, ok := options["draw_after_minutes"]; ok {
+		drawAfter = int(opt.IntValue())
+	}
+	remindAfter := defaultRemindAfterHours
+	if opt, ok := options["remind_after_hours"]; ok {
+		remindAfter = int(opt.IntValue())
+	}
+	if drawAfter < 0 || remindAfter < 0 {
+		respondError(s, i, "draw_after_minutes and remind_after_hours cannot be negative")
+		return
+	}
+
+	sched := models.NewSchedule(
+		options["name"].StringValue(),
+		options["quality"].StringValue(),
+		cronExpr,
+		loc.String(),
+		options["channel"].ChannelValue(s).ID,
+		drawAfter,
+		remindAfter,
+		i.Member.User.ID,
+	)
+	sched.NextRunAt = cron.Next(time.Now().In(loc))
+	if sched.NextRunAt.IsZero() {
+		respondError(s, i, "That cron expression never matches")
+		return
+	}
+
+	if err := dbClient.CreateSchedule(ctx, sched); err != nil {
+		respondError(s, i, "Failed to create schedule")
+		return
+	}
+
+	respondEmbed(s, i, &discordgo.MessageEmbed{
+		Title:       "Schedule Created",
+		Color:       0x00ff00,
+		Description: describeSchedule(sched),
+	})
+}
+
+func handleScheduleList(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) {
+	schedules, err := dbClient.GetAllSchedules(ctx)
+	if err != nil {
+		respondError(s, i, "Failed to get schedules")
+		return
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title: "Distribution Schedules",
+		Color: 0x00ff00,
+	}
+	if len(schedules) == 0 {
+		embed.Description = "No schedules configured"
+	}
+	for _, sched := range schedules {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  sched.Name,
+			Value: describeSchedule(sched),
+		})
+	}
+
+	respondEmbed(s, i, embed)
+}
+
+func describeSchedule(sched *models.Schedule) string {
+	lines := []string{
+		fmt.Sprintf("ID: `%s`", sched.ScheduleID),
+		fmt.Sprintf("%s %s links, `%s` (%s)", getQualityEmoji(sched.Quality), strings.Title(sched.Quality), sched.Cron, sched.TimeZone),
+		fmt.Sprintf("Announces in <#%s>", sched.ChannelID),
+		fmt.Sprintf("Next round: <t:%d:F>", sched.NextRunAt.Unix()),
+	}
+	if sched.DrawAfter > 0 {
+		lines = append(lines, fmt.Sprintf("Draws automatically %d minutes after opening", sched.DrawAfter))
+	}
+	if sched.RemindAfter > 0 {
+		lines = append(lines, fmt.Sprintf("Reminds officers after %d hours pending", sched.RemindAfter))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// runScheduler checks schedules every minute until ctx is cancelled
+func runScheduler(ctx context.Context, s *discordgo.Session) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			processSchedules(ctx, s, time.Now())
+		}
+	}
+}
+
+func processSchedules(ctx context.Context, s *discordgo.Session, now time.Time) {
+	schedules, err := dbClient.GetAllSchedules(ctx)
+	if err != nil {
+		log.Printf("Scheduler failed to load schedules: %v", err)
+		return
+	}
+
+	for _, sched := range schedules {
+		readNextRunAt := sched.NextRunAt
+		changed := false
+
+		if sched.IsDue(now) {
+			openRound(ctx, s, sched, now)
+			changed = true
+		}
+		if sched.IsDrawDue(now) {
+			runScheduledDraw(ctx, s, sched)
+			changed = true
+		}
+		if remindPendingWinner(ctx, s, sched, now) {
+			changed = true
+		}
+
+		if changed {
+			err := dbClient.PutSchedule(ctx, sched, readNextRunAt)
+			if errors.Is(err, db.ErrScheduleChanged) {
+				log.Printf("Scheduler skipped saving %s: it was deleted or run elsewhere", sched.ScheduleID)
+				continue
+			}
+			if err != nil {
+				log.Printf("Scheduler failed to save %s: %v", sched.ScheduleID, err)
+			}
+		}
+	}
+}
+
+// openRound creates the round's list, retires the previous round's list and announces it
+func openRound(ctx context.Context, s *discordgo.Session, sched *models.Schedule, now time.Time) {
+	loc := sched.Location()
+	next := time.Time{}
+	if cron, err := schedule.ParseCron(sched.Cron); err == nil {
+		next = cron.Next(now.In(loc))
+	}
+	if next.IsZero() {
+		log.Printf("Schedule %s has an invalid cron expression, deactivating", sched.ScheduleID)
+		sched.IsActive = false
+		return
+	}
+
+	if sched.CurrentListID != "" {
+		if previous, err := dbClient.GetDistributionList(ctx, sched.CurrentListID); err == nil && previous.IsActive {
+			previous.IsActive = false
+			if err := dbClient.UpdateDistributionList(ctx, previous); err != nil {
+				log.Printf("Failed to close list %s for schedule %s: %v", previous.ListID, sched.ScheduleID, err)
+			}
+		}
+	}
+
+	listName := fmt.Sprintf("%s Links - %s", strings.Title(sched.Quality), now.In(loc).Format("January 2006"))
+	list, err := createListForQuality(ctx, listName, sched.Quality, "scheduler:"+sched.ScheduleID)
+	if err != nil {
+		// Skip this occurrence; the next one is still scheduled
+		log.Printf("Schedule %s could not open a round: %v", sched.ScheduleID, err)
+		sched.NextRunAt = next
+		s.ChannelMessageSend(sched.ChannelID, fmt.Sprintf("⚠️ %s could not open this round: %v", sched.Name, err))
+		return
+	}
+
+	sched.StartRound(list.ListID, now, next)
+
+	embed := &discordgo.MessageEmbed{
+		Title:       fmt.Sprintf("%s %s is open", getQualityEmoji(sched.Quality), list.ListName),
+		Color:       getQualityColor(sched.Quality),
+		Description: fmt.Sprintf("%d members are eligible for this %s round.", list.GetMemberCount(), sched.Quality),
+		Timestamp:   now.Format(time.RFC3339),
+	}
+	if !sched.DrawAt.IsZero() {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  "Draw",
+			Value: fmt.Sprintf("<t:%d:F> (<t:%d:R>)", sched.DrawAt.Unix(), sched.DrawAt.Unix()),
+		})
+	} else {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  "Draw",
+			Value: fmt.Sprintf("A Maester will run `/pick-winner quality:%s`", sched.Quality),
+		})
+	}
+
+	if _, err := s.ChannelMessageSendEmbed(sched.ChannelID, embed); err != nil {
+		log.Printf("Schedule %s failed to announce round: %v", sched.ScheduleID, err)
+	}
+}
+
+// runScheduledDraw draws a winner for the current round and posts it with the confirm buttons
+func runScheduledDraw(ctx context.Context, s *discordgo.Session, sched *models.Schedule) {
+	sched.DrawAt = time.Time{}
+
+	list, err := dbClient.GetDistributionList(ctx, sched.CurrentListID)
+	if err != nil {
+		log.Printf("Schedule %s lost its list %s: %v", sched.ScheduleID, sched.CurrentListID, err)
+		return
+	}
+
+	winner, err := drawFromList(ctx, list, "", false, "scheduler:"+sched.ScheduleID)
+	if err != nil {
+		s.ChannelMessageSend(sched.ChannelID, fmt.Sprintf("⚠️ %s could not draw a winner: %v", sched.Name, err))
+		return
+	}
+
+	_, err = s.ChannelMessageSendComplex(sched.ChannelID, &discordgo.MessageSend{
+		Embeds:     []*discordgo.MessageEmbed{winnerEmbed(list, winner)},
+		Components: pickWinnerButtons(list.ListID),
+	})
+	if err != nil {
+		log.Printf("Schedule %s failed to post winner: %v", sched.ScheduleID, err)
+	}
+}
+
+// remindPendingWinner nudges officers about a winner nobody has confirmed yet
+func remindPendingWinner(ctx context.Context, s *discordgo.Session, sched *models.Schedule, now time.Time) bool {
+	if sched.CurrentListID == "" || sched.RemindAfter <= 0 {
+		return false
+	}
+
+	list, err := dbClient.GetDistributionList(ctx, sched.CurrentListID)
+	if err != nil {
+		return false
+	}
+
+	pending := list.PendingDraw()
+	if !sched.NeedsReminder(pending, now) {
+		return false
+	}
+
+	_, err = s.ChannelMessageSendComplex(sched.ChannelID, &discordgo.MessageSend{
+		Content: fmt.Sprintf("⏰ %s **%s** was drawn for a %s link <t:%d:R> and is still waiting. Confirm or re-roll below.",
+			maesterMention(s), pending.MemberUsername, list.Quality, pending.DrawnAt.Unix()),
+		Components: pickWinnerButtons(list.ListID),
+	})
+	if err != nil {
+		log.Printf("Schedule %s failed to send reminder: %v", sched.ScheduleID, err)
+		return false
+	}
+
+	sched.RemindedAt = now
+	return true
+}
+
+// maesterMention returns a mention for the guild's Maester role, if it exists
+func maesterMention(s *discordgo.Session) string {
+	guild, err := s.State.Guild(guildID)
+	if err != nil {
+		return "Maesters:"
+	}
+	for _, role := range guild.Roles {
+		if role.Name == "Maester" {
+			return "<@&" + role.ID + ">"
+		}
+	}
+	return "Maesters:"
+}
+
+func respondEmbed(s *discordgo.Session, i *discordgo.InteractionCreate, embed *discordgo.MessageEmbed) {
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{embed},
+		},
+	})
+}
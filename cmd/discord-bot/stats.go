@@ -0,0 +1,183 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"flavaflav/internal/models"
+	"flavaflav/internal/stats"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// leaderboardSize is the number of members shown by /leaderboard
+const leaderboardSize = 10
+
+var periodOption = &discordgo.ApplicationCommandOption{
+	Type:        discordgo.ApplicationCommandOptionString,
+	Name:        "period",
+	Description: "Time period (default: all time)",
+	Required:    false,
+	Choices: []*discordgo.ApplicationCommandOptionChoice{
+		{Name: "Last week", Value: "week"},
+		{Name: "Last month", Value: "month"},
+		{Name: "Last quarter", Value: "quarter"},
+		{Name: "Last year", Value: "year"},
+		{Name: "All time", Value: "all"},
+	},
+}
+
+var leaderboardCommand = &discordgo.ApplicationCommand{
+	Name:        "leaderboard",
+	Description: "See who has received the most links",
+	Options: []*discordgo.ApplicationCommandOption{
+		periodOption,
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        "quality",
+			Description: "Only count one quality",
+			Required:    false,
+			Choices: []*discordgo.ApplicationCommandOptionChoice{
+				{Name: "Bronze", Value: "bronze"},
+				{Name: "Silver", Value: "silver"},
+				{Name: "Gold", Value: "gold"},
+			},
+		},
+	},
+}
+
+var statsCommand = &discordgo.ApplicationCommand{
+	Name:        "stats",
+	Description: "Guild distribution statistics",
+	Options:     []*discordgo.ApplicationCommandOption{periodOption},
+}
+
+func handleLeaderboard(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) {
+	options := optionMap(i.ApplicationCommandData().Options)
+	period, err := periodFromOptions(options)
+	if err != nil {
+		respondError(s, i, err.Error())
+		return
+	}
+	var quality string
+	if opt, ok := options["quality"]; ok {
+		quality = opt.StringValue()
+	}
+
+	distributions, err := dbClient.GetAllDistributions(ctx)
+	if err != nil {
+		respondError(s, i, "Failed to get distribution history")
+		return
+	}
+
+	board := stats.Leaderboard(distributions, period, quality, time.Now())
+
+	title := "Link Leaderboard"
+	if quality != "" {
+		title += " " + getQualityEmoji(quality)
+	}
+	embed := &discordgo.MessageEmbed{
+		Title:  title,
+		Color:  0xFFD700,
+		Footer: &discordgo.MessageEmbedFooter{Text: "Period: " + period.Name},
+	}
+
+	if len(board) == 0 {
+		embed.Description = "No links distributed in this period"
+	} else {
+		var lines []string
+		for rank, entry := range board {
+			if rank == leaderboardSize {
+				break
+			}
+			line := fmt.Sprintf("**%d.** %s: %d %s", rank+1, entry.MemberUsername, entry.Total, formatQualityCounts(entry.ByQuality))
+			if entry.AvgDaysBetween > 0 {
+				line += fmt.Sprintf(", every %.0f days", entry.AvgDaysBetween)
+			}
+			lines = append(lines, line)
+		}
+		embed.Description = strings.Join(lines, "\n")
+	}
+
+	respondEmbed(s, i, embed)
+}
+
+func handleStats(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) {
+	period, err := periodFromOptions(optionMap(i.ApplicationCommandData().Options))
+	if err != nil {
+		respondError(s, i, err.Error())
+		return
+	}
+
+	distributions, err := dbClient.GetAllDistributions(ctx)
+	if err != nil {
+		respondError(s, i, "Failed to get distribution history")
+		return
+	}
+
+	links, err := dbClient.GetAllInventoryLinks(ctx)
+	if err != nil {
+		respondError(s, i, "Failed to get inventory")
+		return
+	}
+
+	result := stats.Compute(distributions, links, period, time.Now())
+
+	embed := &discordgo.MessageEmbed{
+		Title: "Guild Distribution Stats",
+		Color: 0x00ff00,
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "Links Distributed", Value: fmt.Sprintf("%d %s", result.TotalDistributions, formatQualityCounts(result.DistributionsByQuality)), Inline: true},
+			{Name: "Per Week", Value: fmt.Sprintf("%.1f", result.DistributionsPerWeek), Inline: true},
+			{Name: "Links Added", Value: strconv.Itoa(result.InventoryAdded), Inline: true},
+			{Name: "Members Awarded", Value: strconv.Itoa(len(result.Leaderboard)), Inline: true},
+		},
+		Footer: &discordgo.MessageEmbedFooter{Text: "Period: " + period.Name},
+	}
+
+	if len(result.InventoryByOfficer) > 0 {
+		var lines []string
+		for _, entry := range result.InventoryByOfficer {
+			lines = append(lines, fmt.Sprintf("%s: %d %s", displayUser(entry.AddedBy), entry.Total, formatQualityCounts(entry.ByQuality)))
+		}
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  "Inventory Added By",
+			Value: strings.Join(lines, "\n"),
+		})
+	}
+
+	respondEmbed(s, i, embed)
+}
+
+func periodFromOptions(options map[string]*discordgo.ApplicationCommandInteractionDataOption) (stats.Period, error) {
+	var name string
+	if opt, ok := options["period"]; ok {
+		name = opt.StringValue()
+	}
+	return stats.ParsePeriod(name, time.Now())
+}
+
+// formatQualityCounts renders counts like "(🥇 2 🥈 1)"
+func formatQualityCounts(counts map[string]int) string {
+	var parts []string
+	for _, quality := range []string{models.QualityGold, models.QualitySilver, models.QualityBronze} {
+		if counts[quality] > 0 {
+			parts = append(parts, fmt.Sprintf("%s %d", getQualityEmoji(quality), counts[quality]))
+		}
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return "(" + strings.Join(parts, " ") + ")"
+}
+
+// displayUser mentions Discord IDs and leaves other names (e.g. "web-admin") as-is
+func displayUser(id string) string {
+	if _, err := strconv.ParseUint(id, 10, 64); err == nil {
+		return "<@" + id + ">"
+	}
+	return id
+}
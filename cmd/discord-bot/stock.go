@@ -0,0 +1,219 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"flavaflav/internal/models"
+	"flavaflav/internal/notify"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// stockAlertChannelID receives low-stock alerts, set from STOCK_ALERT_CHANNEL_ID
+// at startup; alerts are off when it is empty
+var stockAlertChannelID string
+
+var stockCommand = &discordgo.ApplicationCommand{
+	Name:        "stock-alerts",
+	Description: "Minimum stock levels that trigger low-stock alerts (Maester only)",
+	Options: []*discordgo.ApplicationCommandOption{
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "list",
+			Description: "Show minimum stock levels and current stock",
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "set",
+			Description: "Set the minimum stock for a quality or one link type (0 removes it)",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "quality",
+					Description: "Link quality",
+					Required:    true,
+					Choices: []*discordgo.ApplicationCommandOptionChoice{
+						{Name: "Bronze", Value: models.QualityBronze},
+						{Name: "Silver", Value: models.QualitySilver},
+						{Name: "Gold", Value: models.QualityGold},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionInteger,
+					Name:        "minimum",
+					Description: "Alert when fewer links than this are available",
+					Required:    true,
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "link_type",
+					Description: "Only count this link type (otherwise every type of the quality)",
+					Required:    false,
+				},
+			},
+		},
+	},
+}
+
+func handleStockAlerts(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if !isMaester(s, i.Member) {
+		respondError(s, i, "Only Maesters can manage stock alerts.")
+		return
+	}
+
+	if !dbClient.ThresholdsEnabled() {
+		respondError(s, i, "Stock thresholds are not configured (set DYNAMODB_THRESHOLDS_TABLE).")
+		return
+	}
+
+	sub := i.ApplicationCommandData().Options[0]
+	options := optionMap(sub.Options)
+
+	switch sub.Name {
+	case "list":
+		handleStockList(ctx, s, i)
+	case "set":
+		quality := options["quality"].StringValue()
+		minimum := int(options["minimum"].IntValue())
+		if minimum < 0 {
+			respondError(s, i, "Minimum cannot be negative")
+			return
+		}
+		var linkType string
+		if opt, ok := options["link_type"]; ok {
+			linkType = strings.TrimSpace(opt.StringValue())
+		}
+
+		if minimum == 0 {
+			if err := dbClient.DeleteStockThreshold(ctx, models.StockThresholdID(quality, linkType)); err != nil {
+				respondError(s, i, "Failed to remove stock threshold")
+				return
+			}
+			respondEmbed(s, i, &discordgo.MessageEmbed{
+				Title:       "Stock Threshold Removed",
+				Color:       0x666666,
+				Description: fmt.Sprintf("No minimum for %s %s any more", quality, linkType),
+			})
+			return
+		}
+
+		threshold, err := models.NewStockThreshold(quality, linkType, minimum, i.Member.User.ID)
+		if err != nil {
+			respondError(s, i, err.Error())
+			return
+		}
+		if err := dbClient.PutStockThreshold(ctx, threshold); err != nil {
+			respondError(s, i, "Failed to save stock threshold")
+			return
+		}
+
+		respondEmbed(s, i, &discordgo.MessageEmbed{
+			Title:       "Stock Threshold Set",
+			Color:       0x00ff00,
+			Description: fmt.Sprintf("Alert when fewer than %d %s links are available", minimum, threshold.Describe()),
+		})
+		checkStock(ctx, s)
+	}
+}
+
+func handleStockList(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) {
+	thresholds, err := dbClient.GetStockThresholds(ctx)
+	if err != nil {
+		respondError(s, i, "Failed to get stock thresholds")
+		return
+	}
+
+	links, err := dbClient.GetAvailableInventoryLinks(ctx)
+	if err != nil {
+		respondError(s, i, "Failed to get inventory")
+		return
+	}
+	summary := models.SummarizeInventory(links, models.GroupByLinkType)
+
+	sort.Slice(thresholds, func(a, b int) bool {
+		return thresholds[a].ThresholdID < thresholds[b].ThresholdID
+	})
+
+	embed := &discordgo.MessageEmbed{Title: "Stock Thresholds", Color: 0x00ff00}
+	if len(thresholds) == 0 {
+		embed.Description = "No minimum stock levels set. Use /stock-alerts set."
+		respondEmbed(s, i, embed)
+		return
+	}
+
+	var lines []string
+	for _, threshold := range thresholds {
+		available := threshold.Available(summary)
+		status := "✅"
+		if available < threshold.Minimum {
+			status = "⚠️"
+			embed.Color = 0xff9900
+		}
+		lines = append(lines, fmt.Sprintf("%s %s: %d available, minimum %d", status, threshold.Describe(), available, threshold.Minimum))
+	}
+	embed.Description = truncate(strings.Join(lines, "\n"), 4096)
+	if stockAlertChannelID == "" {
+		embed.Footer = &discordgo.MessageEmbedFooter{Text: "Set STOCK_ALERT_CHANNEL_ID to receive alerts in Discord."}
+	}
+
+	respondEmbed(s, i, embed)
+}
+
+// checkStock posts thresholds that stock has newly fallen below to the alert
+// channel. It runs after every inventory change; failures are only logged.
+func checkStock(ctx context.Context, s *discordgo.Session) {
+	if stockAlertChannelID == "" || !dbClient.ThresholdsEnabled() {
+		return
+	}
+
+	thresholds, err := dbClient.GetStockThresholds(ctx)
+	if err != nil {
+		log.Printf("Stock check failed: %v", err)
+		return
+	}
+	if len(thresholds) == 0 {
+		return
+	}
+
+	links, err := dbClient.GetAvailableInventoryLinks(ctx)
+	if err != nil {
+		log.Printf("Stock check failed: %v", err)
+		return
+	}
+
+	alerts := models.CheckStock(thresholds, models.SummarizeInventory(links, models.GroupByLinkType))
+	fresh, changed := models.UpdateAlertState(thresholds, alerts)
+	for _, threshold := range changed {
+		if err := dbClient.PutStockThreshold(ctx, threshold); err != nil {
+			log.Printf("Failed to save stock threshold %s: %v", threshold.ThresholdID, err)
+		}
+	}
+
+	if len(fresh) > 0 {
+		if _, err := s.ChannelMessageSendEmbed(stockAlertChannelID, notify.StockAlertEmbed(fresh)); err != nil {
+			log.Printf("Failed to post stock alert: %v", err)
+		}
+	}
+}
+
+// watchStock checks stock periodically until ctx is cancelled, catching
+// changes made outside the bot
+func watchStock(ctx context.Context, s *discordgo.Session, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		checkStock(ctx, s)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
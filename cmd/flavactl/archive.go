@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+
+	"flavaflav/internal/archive"
+	"flavaflav/internal/db"
+)
+
+// runExport writes every member, inventory link, distribution and list to a
+// JSON archive
+func runExport(args []string) {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	output := flags.String("o", "", "archive file to write (default: standard output)")
+	flags.Parse(args)
+
+	ctx := context.Background()
+	dbClient := openStore()
+	members, _, _, _ := tableNames()
+
+	snapshot, err := readAll(ctx, dbClient)
+	if err != nil {
+		log.Fatal(err)
+	}
+	snapshot.Source = members
+
+	out := os.Stdout
+	if *output != "" {
+		out, err = os.Create(*output)
+		if err != nil {
+			log.Fatalf("Failed to create %s: %v", *output, err)
+		}
+		defer out.Close()
+	}
+	if err := snapshot.Write(out); err != nil {
+		log.Fatal(err)
+	}
+
+	log.Printf("Exported %d members, %d inventory links, %d distributions and %d lists",
+		len(snapshot.Members), len(snapshot.Inventory), len(snapshot.Distributions), len(snapshot.Lists))
+}
+
+// runImport loads an archive into the tables. Records already present with
+// the same contents are skipped, so an interrupted import can be run again.
+func runImport(args []string) {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	dryRun := flags.Bool("dry-run", false, "show what would be imported without writing anything")
+	asJSON := flags.Bool("json", false, "print the import plan as JSON")
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		log.Fatal("usage: flavactl import [-dry-run] [-json] <archive.json>")
+	}
+
+	file, err := os.Open(flags.Arg(0))
+	if err != nil {
+		log.Fatalf("Failed to open %s: %v", flags.Arg(0), err)
+	}
+	incoming, err := archive.Read(file)
+	file.Close()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ctx := context.Background()
+	dbClient := openStore()
+	existing, err := readAll(ctx, dbClient)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	plan := archive.Plan(incoming, existing)
+	printPlan(plan, *asJSON)
+	if *dryRun {
+		log.Print("Dry run: nothing was written")
+		return
+	}
+
+	created, err := applyPlan(ctx, dbClient, plan)
+	if err != nil {
+		log.Fatalf("Import stopped after creating %d records: %v (run it again to resume)", created, err)
+	}
+	log.Printf("Imported %d records", created)
+}
+
+// readAll reads the four tables into an archive, failing if any record can't be read
+func readAll(ctx context.Context, dbClient *db.DynamoDBClient) (*archive.Archive, error) {
+	contents, err := dbClient.ReadAllTables(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return archive.New("", contents.Members, contents.Inventory, contents.Distributions, contents.Lists), nil
+}
+
+// applyPlan creates the planned records, returning how many were created
+func applyPlan(ctx context.Context, dbClient *db.DynamoDBClient, plan *archive.ImportPlan) (int, error) {
+	created := 0
+	for _, member := range plan.Members {
+		if err := dbClient.CreateMember(ctx, member); err != nil {
+			return created, fmt.Errorf("member %s: %v", member.DiscordID, err)
+		}
+		created++
+	}
+	for _, list := range plan.Lists {
+		if err := dbClient.CreateDistributionList(ctx, list); err != nil {
+			return created, fmt.Errorf("list %s: %v", list.ListID, err)
+		}
+		created++
+	}
+	for _, link := range plan.Inventory {
+		if err := dbClient.CreateInventoryLink(ctx, link); err != nil {
+			return created, fmt.Errorf("inventory link %s: %v", link.LinkID, err)
+		}
+		created++
+	}
+	for _, d := range plan.Distributions {
+		if err := dbClient.CreateDistribution(ctx, d); err != nil {
+			return created, fmt.Errorf("distribution %s: %v", d.DistributionID, err)
+		}
+		created++
+	}
+	return created, nil
+}
+
+// printPlan describes what an import will do
+func printPlan(plan *archive.ImportPlan, asJSON bool) {
+	if asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		encoder.Encode(map[string]interface{}{
+			"create":       plan.Counts(),
+			"unchanged":    plan.Unchanged,
+			"kept_members": plan.KeptMembers,
+			"remapped_ids": plan.RemappedIDs,
+			"warnings":     plan.Warnings,
+		})
+		return
+	}
+
+	counts := plan.Counts()
+	fmt.Printf("To create: %d members, %d inventory links, %d distributions, %d lists\n",
+		counts["members"], counts["inventory"], counts["distributions"], counts["lists"])
+	fmt.Printf("Already present: %d members, %d inventory links, %d distributions, %d lists\n",
+		plan.Unchanged["members"], plan.Unchanged["inventory"], plan.Unchanged["distributions"], plan.Unchanged["lists"])
+	if len(plan.KeptMembers) > 0 {
+		fmt.Printf("Kept %d existing members whose data differs: %v\n", len(plan.KeptMembers), plan.KeptMembers)
+	}
+	if len(plan.RemappedIDs) > 0 {
+		fmt.Printf("IDs already taken by other records, given new IDs:\n")
+		ids := make([]string, 0, len(plan.RemappedIDs))
+		for id := range plan.RemappedIDs {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		for _, id := range ids {
+			fmt.Printf("  %s -> %s\n", id, plan.RemappedIDs[id])
+		}
+	}
+	for _, warning := range plan.Warnings {
+		fmt.Printf("Warning: %s\n", warning)
+	}
+}
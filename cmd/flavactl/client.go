@@ -0,0 +1,144 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// options are the flags every API command accepts
+type options struct {
+	apiURL string
+	apiKey string
+	json   bool
+}
+
+// commandFlags creates the flag set for an API command, with -api, -key and
+// -json defaulting to FLAVACTL_API_URL and FLAVACTL_API_KEY
+func commandFlags(name string) (*flag.FlagSet, *options) {
+	opts := &options{}
+	flags := flag.NewFlagSet(name, flag.ExitOnError)
+	flags.StringVar(&opts.apiURL, "api", os.Getenv("FLAVACTL_API_URL"), "API base URL, e.g. https://<id>.execute-api.<region>.amazonaws.com/dev")
+	flags.StringVar(&opts.apiKey, "key", os.Getenv("FLAVACTL_API_KEY"), "API key sent in the X-Api-Key header")
+	flags.BoolVar(&opts.json, "json", false, "print JSON instead of a table")
+	return flags, opts
+}
+
+// apiClient calls the FlavaFlav API
+type apiClient struct {
+	baseURL string
+	apiKey  string
+	http    *http.Client
+}
+
+// client returns an API client for the options, exiting if no URL is set
+func (o *options) client() *apiClient {
+	if o.apiURL == "" {
+		log.Fatal("set FLAVACTL_API_URL or pass -api")
+	}
+	return &apiClient{
+		baseURL: strings.TrimSuffix(o.apiURL, "/"),
+		apiKey:  o.apiKey,
+		http:    &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// apiResponse mirrors the API's response envelope
+type apiResponse struct {
+	Success bool            `json:"success"`
+	Data    json.RawMessage `json:"data"`
+	Error   string          `json:"error"`
+}
+
+// get calls a GET endpoint and decodes its data into out
+func (c *apiClient) get(path string, query url.Values, out interface{}) error {
+	return c.do(http.MethodGet, path, query, nil, out)
+}
+
+// post calls a POST endpoint with an optional JSON body and decodes its data into out
+func (c *apiClient) post(path string, query url.Values, body, out interface{}) error {
+	return c.do(http.MethodPost, path, query, body, out)
+}
+
+func (c *apiClient) do(method, path string, query url.Values, body, out interface{}) error {
+	resp, err := c.send(method, path, query, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var envelope apiResponse
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return fmt.Errorf("%s %s: unexpected response (HTTP %d): %v", method, path, resp.StatusCode, err)
+	}
+	if !envelope.Success {
+		return fmt.Errorf("%s (HTTP %d)", envelope.Error, resp.StatusCode)
+	}
+	if out == nil || len(envelope.Data) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(envelope.Data, out); err != nil {
+		return fmt.Errorf("failed to decode %s response: %v", path, err)
+	}
+	return nil
+}
+
+// download calls a GET endpoint that returns a file and copies it to w
+func (c *apiClient) download(path string, query url.Values, w io.Writer) error {
+	resp, err := c.send(http.MethodGet, path, query, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var envelope apiResponse
+		json.NewDecoder(resp.Body).Decode(&envelope)
+		return fmt.Errorf("%s (HTTP %d)", envelope.Error, resp.StatusCode)
+	}
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		return fmt.Errorf("failed to download %s: %v", path, err)
+	}
+	return nil
+}
+
+func (c *apiClient) send(method, path string, query url.Values, body interface{}) (*http.Response, error) {
+	target := c.baseURL + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode request: %v", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, target, reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %v", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.apiKey != "" {
+		req.Header.Set("X-Api-Key", c.apiKey)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s %s: %v", method, path, err)
+	}
+	return resp, nil
+}
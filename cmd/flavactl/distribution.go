@@ -0,0 +1,189 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"flavaflav/internal/models"
+)
+
+const listsUsage = `flavactl lists list
+flavactl lists create -quality silver [-name "Silver Links - October"]`
+
+// runLists lists and creates distribution lists
+func runLists(args []string) {
+	if len(args) == 0 {
+		log.Fatal("usage:\n" + listsUsage)
+	}
+	switch args[0] {
+	case "list":
+		listsList(args[1:])
+	case "create":
+		listsCreate(args[1:])
+	default:
+		log.Fatalf("unknown lists command %q\nusage:\n%s", args[0], listsUsage)
+	}
+}
+
+func listsList(args []string) {
+	flags, opts := commandFlags("lists list")
+	flags.Parse(args)
+
+	var lists []*models.DistributionList
+	if err := opts.client().get("/api/distribution/lists", nil, &lists); err != nil {
+		log.Fatal(err)
+	}
+	showLists(opts, lists, lists...)
+}
+
+func listsCreate(args []string) {
+	flags, opts := commandFlags("lists create")
+	quality := flags.String("quality", "", "silver or gold")
+	name := flags.String("name", "", "list name (default: quality and month)")
+	flags.Parse(args)
+	required(`flavactl lists create -quality silver [-name "..."]`, *quality)
+	if *name == "" && *quality != "" {
+		*name = fmt.Sprintf("%s%s Links - %s", strings.ToUpper((*quality)[:1]), (*quality)[1:], time.Now().Format("January 2006"))
+	}
+
+	var list models.DistributionList
+	err := opts.client().post("/api/distribution/create-list", nil, map[string]string{
+		"list_name": *name,
+		"quality":   *quality,
+	}, &list)
+	if err != nil {
+		log.Fatal(err)
+	}
+	showLists(opts, &list, &list)
+}
+
+// showLists prints distribution lists with their pending winner, if any; v is printed with -json
+func showLists(opts *options, v interface{}, lists ...*models.DistributionList) {
+	rows := make([][]string, 0, len(lists))
+	for _, list := range lists {
+		pending := "-"
+		if draw := list.PendingDraw(); draw != nil {
+			pending = memberLabel(draw.MemberUsername, draw.MemberID)
+		}
+		rows = append(rows, []string{
+			list.ListID, list.ListName, list.Quality, strconv.Itoa(len(list.EligibleMembers)),
+			strconv.Itoa(len(list.Draws)), pending, formatDate(list.CreatedAt),
+		})
+	}
+	opts.show(v, []string{"LIST ID", "NAME", "QUALITY", "MEMBERS", "DRAWS", "PENDING", "CREATED"}, rows)
+}
+
+// runDraw draws a winner from a list, or re-rolls an absent pending winner
+func runDraw(args []string) {
+	flags, opts := commandFlags("draw")
+	listID := flags.String("list", "", "distribution list ID")
+	linkType := flags.String("link-type", "", "link type being handed out")
+	matchBuild := flags.Bool("match-build", false, "only draw members whose build tags suit -link-type")
+	reroll := flags.String("reroll", "", "re-roll the pending winner, giving the reason they were skipped")
+	flags.Parse(args)
+	required("flavactl draw -list <list_id> [-link-type <type> [-match-build]] [-reroll <reason>]", *listID)
+
+	var result struct {
+		Winner *models.Member           `json:"winner"`
+		List   *models.DistributionList `json:"list"`
+	}
+	client := opts.client()
+	var err error
+	if *reroll != "" {
+		err = client.post("/api/distribution/reroll", nil, map[string]string{"list_id": *listID, "reason": *reroll}, &result)
+	} else {
+		query := url.Values{"list_id": {*listID}}
+		if *linkType != "" {
+			query.Set("link_type", *linkType)
+		}
+		if *matchBuild {
+			query.Set("match_build", "true")
+		}
+		err = client.post("/api/distribution/pick-winner", query, nil, &result)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if opts.json {
+		printJSON(result)
+		return
+	}
+	printTable([]string{"WINNER", "DISCORD ID", "RANK", "LIST", "QUALITY", "LEFT ON LIST"}, [][]string{{
+		result.Winner.Username, result.Winner.DiscordID, result.Winner.Rank,
+		result.List.ListName, result.List.Quality, strconv.Itoa(len(result.List.EligibleMembers)),
+	}})
+}
+
+// runDistribute hands a link to a member, confirming their draw on a list
+func runDistribute(args []string) {
+	flags, opts := commandFlags("distribute")
+	memberID := flags.String("member", "", "Discord ID of the member receiving the link")
+	linkID := flags.String("link", "", "ID of the link to hand over")
+	listID := flags.String("list", "", "distribution list the member was drawn from")
+	character := flags.String("character", "", "character to trade the link to (default: the member's primary)")
+	matchBuild := flags.Bool("match-build", false, "refuse links the member's build tags don't suit")
+	flags.Parse(args)
+	required("flavactl distribute -member <discord_id> -link <link_id> [-list <list_id>] [-character <name>]", *memberID, *linkID)
+
+	var result struct {
+		Distribution *models.Distribution `json:"distribution"`
+	}
+	err := opts.client().post("/api/distribution/distribute", url.Values{"member_id": {*memberID}}, map[string]interface{}{
+		"list_id":     *listID,
+		"link_id":     *linkID,
+		"character":   *character,
+		"match_build": *matchBuild,
+	}, &result)
+	if err != nil {
+		log.Fatal(err)
+	}
+	showDistributions(opts, result, result.Distribution)
+}
+
+// runReverse undoes a distribution given to the wrong member
+func runReverse(args []string) {
+	flags, opts := commandFlags("reverse")
+	distributionID := flags.String("distribution", "", "ID of the distribution to undo")
+	reason := flags.String("reason", "", "why the distribution is being undone")
+	reassignTo := flags.String("reassign-to", "", "Discord ID of the member who should have the link (default: return it to stock)")
+	flags.Parse(args)
+	required("flavactl reverse -distribution <distribution_id> -reason <reason> [-reassign-to <discord_id>]", *distributionID, *reason)
+
+	var result struct {
+		Reversed    *models.Distribution `json:"reversed"`
+		Replacement *models.Distribution `json:"replacement"`
+	}
+	err := opts.client().post("/api/distribution/reverse", nil, map[string]string{
+		"distribution_id": *distributionID,
+		"reason":          *reason,
+		"reassign_to":     *reassignTo,
+	}, &result)
+	if err != nil {
+		log.Fatal(err)
+	}
+	showDistributions(opts, result, result.Reversed, result.Replacement)
+}
+
+// showDistributions prints distributions, skipping missing ones; v is printed with -json
+func showDistributions(opts *options, v interface{}, distributions ...*models.Distribution) {
+	var rows [][]string
+	for _, d := range distributions {
+		if d == nil {
+			continue
+		}
+		status := d.Status
+		if status == "" {
+			status = models.DistributionCompleted
+		}
+		rows = append(rows, []string{
+			d.DistributionID, memberLabel(d.MemberUsername, d.MemberID), d.Quality, d.LinkType,
+			dash(d.Character), status, formatDate(d.DistributedAt),
+		})
+	}
+	opts.show(v, []string{"DISTRIBUTION ID", "MEMBER", "QUALITY", "TYPE", "CHARACTER", "STATUS", "DATE"}, rows)
+}
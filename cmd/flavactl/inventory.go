@@ -0,0 +1,88 @@
+package main
+
+import (
+	"log"
+	"net/url"
+
+	"flavaflav/internal/models"
+)
+
+const inventoryUsage = `flavactl inventory list [-quality gold] [-category Melee]
+flavactl inventory add -type "Melee Damage" -quality gold [-count 1] [-donor <discord_id>]`
+
+// runInventory lists and adds mastery links
+func runInventory(args []string) {
+	if len(args) == 0 {
+		log.Fatal("usage:\n" + inventoryUsage)
+	}
+	switch args[0] {
+	case "list":
+		inventoryList(args[1:])
+	case "add":
+		inventoryAdd(args[1:])
+	default:
+		log.Fatalf("unknown inventory command %q\nusage:\n%s", args[0], inventoryUsage)
+	}
+}
+
+func inventoryList(args []string) {
+	flags, opts := commandFlags("inventory list")
+	quality := flags.String("quality", "", "only links of this quality")
+	category := flags.String("category", "", "only links of this category")
+	flags.Parse(args)
+
+	query := url.Values{}
+	if *quality != "" {
+		query.Set("quality", *quality)
+	}
+	if *category != "" {
+		query.Set("category", *category)
+	}
+
+	var links []*models.InventoryLink
+	if err := opts.client().get("/api/inventory", query, &links); err != nil {
+		log.Fatal(err)
+	}
+	showLinks(opts, links)
+}
+
+func inventoryAdd(args []string) {
+	flags, opts := commandFlags("inventory add")
+	linkType := flags.String("type", "", "link type as named in the catalog")
+	quality := flags.String("quality", "", "bronze, silver or gold")
+	count := flags.Int("count", 1, "number of links to add")
+	donor := flags.String("donor", "", "Discord ID of the member who donated the links")
+	flags.Parse(args)
+	required(`flavactl inventory add -type "Melee Damage" -quality gold [-count 1] [-donor <discord_id>]`, *linkType, *quality)
+
+	var result struct {
+		Message string                  `json:"message"`
+		Links   []*models.InventoryLink `json:"links"`
+	}
+	err := opts.client().post("/api/inventory/add", nil, map[string]interface{}{
+		"link_type": *linkType,
+		"quality":   *quality,
+		"count":     *count,
+		"donor_id":  *donor,
+	}, &result)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if opts.json {
+		printJSON(result)
+		return
+	}
+	log.Print(result.Message)
+	showLinks(opts, result.Links)
+}
+
+// showLinks prints inventory links
+func showLinks(opts *options, links []*models.InventoryLink) {
+	rows := make([][]string, 0, len(links))
+	for _, link := range links {
+		rows = append(rows, []string{
+			link.LinkID, link.Quality, link.LinkType, link.Bonus, link.Status, formatDate(link.AddedDate), dash(link.DonorName),
+		})
+	}
+	opts.show(links, []string{"LINK ID", "QUALITY", "TYPE", "BONUS", "STATUS", "ADDED", "DONOR"}, rows)
+}
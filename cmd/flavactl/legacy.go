@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"flavaflav/internal/db"
+)
+
+// runMigrateLegacy copies the items of a legacy single table into the four
+// tables. With -checkpoint, progress is saved after every page and an
+// interrupted run picks up where it stopped.
+func runMigrateLegacy(args []string) {
+	flags := flag.NewFlagSet("migrate-legacy", flag.ExitOnError)
+	table := flags.String("table", os.Getenv("DYNAMODB_TABLE"), "legacy single table to read (default: DYNAMODB_TABLE)")
+	checkpointFile := flags.String("checkpoint", "", "file to save progress to and resume from")
+	dryRun := flags.Bool("dry-run", false, "classify the items without writing anything")
+	asJSON := flags.Bool("json", false, "print the report as JSON")
+	flags.Parse(args)
+	if *table == "" {
+		log.Fatal("usage: flavactl migrate-legacy -table <legacy table> [-checkpoint file] [-dry-run] [-json]")
+	}
+
+	checkpoint := db.NewLegacyCheckpoint(*table)
+	if *checkpointFile != "" && !*dryRun {
+		saved, err := readCheckpoint(*checkpointFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if saved != nil {
+			if saved.SourceTable != *table {
+				log.Fatalf("%s is a checkpoint for %s, not %s", *checkpointFile, saved.SourceTable, *table)
+			}
+			checkpoint = saved
+			if checkpoint.Done {
+				log.Printf("%s was already migrated on %s", *table, checkpoint.UpdatedAt.Format("2006-01-02 15:04"))
+			} else {
+				log.Printf("Resuming after %d items scanned", checkpoint.Report.Scanned)
+			}
+		}
+	}
+
+	var afterPage func(*db.LegacyCheckpoint) error
+	if *checkpointFile != "" && !*dryRun {
+		afterPage = func(c *db.LegacyCheckpoint) error {
+			return writeCheckpoint(*checkpointFile, c)
+		}
+	}
+
+	dbClient := openStore()
+	err := dbClient.MigrateLegacyTable(context.Background(), checkpoint, *dryRun, afterPage)
+	printLegacyReport(&checkpoint.Report, *dryRun, *asJSON)
+	if err != nil {
+		if afterPage != nil {
+			log.Fatalf("%v\nRun the same command again to resume from %s", err, *checkpointFile)
+		}
+		log.Fatal(err)
+	}
+	if *dryRun {
+		log.Print("Dry run: nothing was written")
+	}
+}
+
+// readCheckpoint loads a saved checkpoint, returning nil if the file doesn't exist yet
+func readCheckpoint(path string) (*db.LegacyCheckpoint, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint: %v", err)
+	}
+	var checkpoint db.LegacyCheckpoint
+	if err := json.Unmarshal(data, &checkpoint); err != nil {
+		return nil, fmt.Errorf("failed to parse checkpoint %s: %v", path, err)
+	}
+	if checkpoint.Report.Migrated == nil {
+		checkpoint.Report.Migrated = make(map[string]int)
+	}
+	if checkpoint.Report.AlreadyPresent == nil {
+		checkpoint.Report.AlreadyPresent = make(map[string]int)
+	}
+	return &checkpoint, nil
+}
+
+// writeCheckpoint saves a checkpoint, replacing the file in one step so an
+// interruption can't leave it half written
+func writeCheckpoint(path string, checkpoint *db.LegacyCheckpoint) error {
+	data, err := json.MarshalIndent(checkpoint, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal checkpoint: %v", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %v", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %v", err)
+	}
+	return nil
+}
+
+// printLegacyReport describes what a legacy migration did
+func printLegacyReport(report *db.LegacyReport, dryRun, asJSON bool) {
+	if asJSON {
+		printJSON(report)
+		return
+	}
+
+	verb := "Migrated"
+	if dryRun {
+		verb = "Would migrate"
+	}
+	fmt.Printf("Scanned %d items\n", report.Scanned)
+	fmt.Printf("%s: %d members, %d inventory links, %d distributions, %d lists\n", verb,
+		report.Migrated[db.LegacyMember], report.Migrated[db.LegacyInventory],
+		report.Migrated[db.LegacyDistribution], report.Migrated[db.LegacyList])
+	if !dryRun {
+		fmt.Printf("Already present: %d members, %d inventory links, %d distributions, %d lists\n",
+			report.AlreadyPresent[db.LegacyMember], report.AlreadyPresent[db.LegacyInventory],
+			report.AlreadyPresent[db.LegacyDistribution], report.AlreadyPresent[db.LegacyList])
+	}
+
+	if len(report.Skipped) > 0 {
+		fmt.Printf("Skipped %d items:\n", len(report.Skipped))
+		for _, note := range report.Skipped {
+			fmt.Printf("  %v: %s\n", note.Key, note.Reason)
+		}
+	}
+	if len(report.Ambiguous) > 0 {
+		fmt.Printf("%d ambiguous items, not migrated:\n", len(report.Ambiguous))
+		for _, note := range report.Ambiguous {
+			fmt.Printf("  %v: %s (%v)\n", note.Key, note.Reason, note.Kinds)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+// Command flavactl is the command-line admin tool for FlavaFlav. Most
+// commands call the API; export, import and migrate-legacy work on the
+// tables directly.
+//
+//	flavactl members list|add|edit
+//	flavactl inventory list|add
+//	flavactl lists list|create
+//	flavactl draw|distribute|reverse|report
+//	flavactl export [-o file]
+//	flavactl import [-dry-run] file
+//	flavactl migrate-legacy [-table name] [-checkpoint file] [-dry-run]
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"flavaflav/internal/db"
+)
+
+const usage = `Usage: flavactl <command> [flags]
+
+Commands:
+  members     List, add and edit guild members (list, add, edit)
+  inventory   List and add mastery links (list, add)
+  lists       List and create distribution lists (list, create)
+  draw        Draw a winner from a distribution list, or re-roll an absent one
+  distribute  Hand a link to a member
+  reverse     Undo a distribution given to the wrong member
+  report      Print or download the distribution report for a month
+  export      Write the members, inventory, distributions and lists tables to a JSON archive
+  import      Load a JSON archive into the tables
+  migrate-legacy
+              Copy a legacy single table (DYNAMODB_TABLE) into the four tables
+
+API commands send requests to FLAVACTL_API_URL (or -api) with FLAVACTL_API_KEY
+(or -key) in the X-Api-Key header, and print tables or, with -json, JSON.
+
+export, import and migrate-legacy read DYNAMODB_MEMBERS_TABLE,
+DYNAMODB_INVENTORY_TABLE, DYNAMODB_DISTRIBUTIONS_TABLE and DYNAMODB_LISTS_TABLE,
+with the usual AWS credentials. Run "flavactl <command> -h" for a command's flags.
+`
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("flavactl: ")
+
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	args := os.Args[2:]
+	switch os.Args[1] {
+	case "members":
+		runMembers(args)
+	case "inventory":
+		runInventory(args)
+	case "lists":
+		runLists(args)
+	case "draw":
+		runDraw(args)
+	case "distribute":
+		runDistribute(args)
+	case "reverse":
+		runReverse(args)
+	case "report":
+		runReport(args)
+	case "export":
+		runExport(args)
+	case "import":
+		runImport(args)
+	case "migrate-legacy":
+		runMigrateLegacy(args)
+	case "help", "-h", "--help":
+		fmt.Print(usage)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+}
+
+// tableNames returns the four table names from the environment
+func tableNames() (members, inventory, distributions, lists string) {
+	members = os.Getenv("DYNAMODB_MEMBERS_TABLE")
+	inventory = os.Getenv("DYNAMODB_INVENTORY_TABLE")
+	distributions = os.Getenv("DYNAMODB_DISTRIBUTIONS_TABLE")
+	lists = os.Getenv("DYNAMODB_LISTS_TABLE")
+	if members == "" || inventory == "" || distributions == "" || lists == "" {
+		log.Fatal("DYNAMODB_MEMBERS_TABLE, DYNAMODB_INVENTORY_TABLE, DYNAMODB_DISTRIBUTIONS_TABLE and DYNAMODB_LISTS_TABLE are required")
+	}
+	return members, inventory, distributions, lists
+}
+
+// openStore connects to the four tables named in the environment
+func openStore() *db.DynamoDBClient {
+	dbClient, err := db.NewDynamoDBClient(tableNames())
+	if err != nil {
+		log.Fatalf("Failed to initialize DynamoDB client: %v", err)
+	}
+	return dbClient
+}
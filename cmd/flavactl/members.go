@@ -0,0 +1,138 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"flavaflav/internal/models"
+)
+
+const membersUsage = `flavactl members list
+flavactl members add -id <discord_id> -name <username> -joined YYYY-MM-DD
+flavactl members edit -id <discord_id> [-name <username>] [-joined YYYY-MM-DD] [-promote] [-build-tags melee,mage]`
+
+// runMembers lists, adds and edits guild members
+func runMembers(args []string) {
+	if len(args) == 0 {
+		log.Fatal("usage:\n" + membersUsage)
+	}
+	switch args[0] {
+	case "list":
+		membersList(args[1:])
+	case "add":
+		membersAdd(args[1:])
+	case "edit":
+		membersEdit(args[1:])
+	default:
+		log.Fatalf("unknown members command %q\nusage:\n%s", args[0], membersUsage)
+	}
+}
+
+func membersList(args []string) {
+	flags, opts := commandFlags("members list")
+	flags.Parse(args)
+
+	var members []*models.Member
+	if err := opts.client().get("/api/members", nil, &members); err != nil {
+		log.Fatal(err)
+	}
+	sort.Slice(members, func(i, j int) bool {
+		return strings.ToLower(members[i].Username) < strings.ToLower(members[j].Username)
+	})
+
+	rows := make([][]string, 0, len(members))
+	for _, m := range members {
+		rows = append(rows, []string{
+			m.DiscordID, m.Username, m.Rank, formatDate(m.JoinDate), strconv.Itoa(m.DaysInGuild),
+			yesNo(m.SilverEligible), yesNo(m.GoldEligible), dash(strings.Join(m.BuildTags, ",")),
+		})
+	}
+	opts.show(members, []string{"DISCORD ID", "USERNAME", "RANK", "JOINED", "DAYS", "SILVER", "GOLD", "BUILD"}, rows)
+}
+
+func membersAdd(args []string) {
+	flags, opts := commandFlags("members add")
+	id := flags.String("id", "", "member's Discord ID")
+	name := flags.String("name", "", "member's Discord username")
+	joined := flags.String("joined", "", "date the member joined the guild (YYYY-MM-DD)")
+	flags.Parse(args)
+	required("flavactl members add -id <discord_id> -name <username> -joined YYYY-MM-DD", *id, *name, *joined)
+
+	var member models.Member
+	err := opts.client().post("/api/member/create", nil, map[string]interface{}{
+		"discord_id": *id,
+		"username":   *name,
+		"join_date":  parseDate("joined", *joined),
+	}, &member)
+	if err != nil {
+		log.Fatal(err)
+	}
+	showMember(opts, &member)
+}
+
+func membersEdit(args []string) {
+	flags, opts := commandFlags("members edit")
+	id := flags.String("id", "", "member's Discord ID")
+	name := flags.String("name", "", "new username")
+	joined := flags.String("joined", "", "corrected join date (YYYY-MM-DD)")
+	promote := flags.Bool("promote", false, "promote the member to Maester")
+	buildTags := flags.String("build-tags", "", "comma-separated build tags, replacing the member's tags (empty clears them)")
+	flags.Parse(args)
+	required("flavactl members edit -id <discord_id> [flags]", *id)
+
+	setFlags := make(map[string]bool)
+	flags.Visit(func(f *flag.Flag) { setFlags[f.Name] = true })
+	if !setFlags["name"] && !setFlags["joined"] && !setFlags["promote"] && !setFlags["build-tags"] {
+		log.Fatal("nothing to change: pass -name, -joined, -promote or -build-tags")
+	}
+
+	client := opts.client()
+	var member models.Member
+	if *name != "" || *joined != "" {
+		update := map[string]interface{}{"discord_id": *id, "username": *name}
+		if *joined != "" {
+			update["join_date"] = parseDate("joined", *joined)
+		}
+		if err := client.post("/api/member/update", nil, update, &member); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if *promote {
+		if err := client.post("/api/member/promote", url.Values{"discord_id": {*id}}, nil, &member); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if setFlags["build-tags"] {
+		tags := []string{}
+		for _, tag := range strings.Split(*buildTags, ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				tags = append(tags, tag)
+			}
+		}
+		if err := client.post("/api/member/build-tags", nil, map[string]interface{}{"discord_id": *id, "tags": tags}, &member); err != nil {
+			log.Fatal(err)
+		}
+	}
+	showMember(opts, &member)
+}
+
+// showMember prints one member
+func showMember(opts *options, m *models.Member) {
+	opts.show(m, []string{"DISCORD ID", "USERNAME", "RANK", "JOINED", "OFFICER", "SILVER", "GOLD", "BUILD"}, [][]string{{
+		m.DiscordID, m.Username, m.Rank, formatDate(m.JoinDate), yesNo(m.IsOfficer),
+		yesNo(m.SilverEligible), yesNo(m.GoldEligible), dash(strings.Join(m.BuildTags, ",")),
+	}})
+}
+
+// memberLabel renders a member as "username (id)"
+func memberLabel(username, id string) string {
+	if username == "" {
+		return id
+	}
+	return fmt.Sprintf("%s (%s)", username, id)
+}
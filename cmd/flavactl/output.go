@@ -0,0 +1,81 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// printJSON writes v as indented JSON to standard output
+func printJSON(v interface{}) {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		log.Fatalf("Failed to write JSON: %v", err)
+	}
+}
+
+// printTable writes rows under a header as aligned columns
+func printTable(header []string, rows [][]string) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, strings.Join(header, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	w.Flush()
+}
+
+// show prints v as JSON with -json, or as a table otherwise
+func (o *options) show(v interface{}, header []string, rows [][]string) {
+	if o.json {
+		printJSON(v)
+		return
+	}
+	printTable(header, rows)
+}
+
+// formatDate renders a date for tables, or "-" when unset
+func formatDate(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Format("2006-01-02")
+}
+
+// parseDate reads a YYYY-MM-DD flag value
+func parseDate(name, value string) time.Time {
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		log.Fatalf("-%s must be a date as YYYY-MM-DD", name)
+	}
+	return t
+}
+
+// yesNo renders a boolean for tables
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
+
+// dash renders an empty table cell as "-"
+func dash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// required exits with usage if any of the named flag values is empty
+func required(usage string, values ...string) {
+	for _, value := range values {
+		if value == "" {
+			log.Fatal("usage: " + usage)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"log"
+	"net/url"
+	"os"
+	"strconv"
+
+	"flavaflav/internal/models"
+	"flavaflav/internal/report"
+)
+
+// runReport downloads the distribution report as Markdown or CSV, or prints
+// its summary table
+func runReport(args []string) {
+	flags, opts := commandFlags("report")
+	month := flags.String("month", "", "month to report on as YYYY-MM (default: last month)")
+	from := flags.String("from", "", "first day of a custom range (YYYY-MM-DD)")
+	to := flags.String("to", "", "last day of a custom range (YYYY-MM-DD)")
+	format := flags.String("format", "", "download as csv or markdown instead of printing a table")
+	output := flags.String("o", "", "file to write a csv or markdown report to (default: standard output)")
+	flags.Parse(args)
+
+	query := url.Values{}
+	for name, value := range map[string]string{"month": *month, "from": *from, "to": *to} {
+		if value != "" {
+			query.Set(name, value)
+		}
+	}
+	client := opts.client()
+
+	if *format != "" {
+		query.Set("format", *format)
+		out := os.Stdout
+		if *output != "" {
+			file, err := os.Create(*output)
+			if err != nil {
+				log.Fatalf("Failed to create %s: %v", *output, err)
+			}
+			defer file.Close()
+			out = file
+		}
+		if err := client.download("/api/reports", query, out); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	var result report.Report
+	if err := client.get("/api/reports", query, &result); err != nil {
+		log.Fatal(err)
+	}
+	if opts.json {
+		printJSON(result)
+		return
+	}
+
+	log.Printf("Distribution report: %s (%d draws run, %d reversed)", result.Range.Name, result.DrawsRun, result.Reversed)
+	rows := [][]string{reportRow("total", result.Distributions)}
+	for _, row := range result.ByMember {
+		rows = append(rows, reportRow("member", row))
+	}
+	for _, row := range result.ByLinkType {
+		rows = append(rows, reportRow("link type", row))
+	}
+	rows = append(rows,
+		reportRow("inventory", result.InventoryAdded),
+		reportRow("inventory", result.FusionResults),
+		reportRow("inventory", result.Remaining),
+	)
+	printTable([]string{"SECTION", "NAME", "GOLD", "SILVER", "BRONZE", "TOTAL"}, rows)
+}
+
+// reportRow renders one report row for a table
+func reportRow(section string, row report.Row) []string {
+	return []string{
+		section, row.Name,
+		strconv.Itoa(row.ByQuality[models.QualityGold]), strconv.Itoa(row.ByQuality[models.QualitySilver]), strconv.Itoa(row.ByQuality[models.QualityBronze]),
+		strconv.Itoa(row.Total),
+	}
+}
@@ -7,6 +7,8 @@ import (
 
 	"flavaflav/internal/db"
 	"flavaflav/internal/handlers"
+	"flavaflav/internal/models"
+	"flavaflav/internal/notify"
 
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
@@ -48,9 +50,54 @@ func init() {
 		log.Fatalf("Failed to initialize DynamoDB client: %v", err)
 	}
 
+	// Optional feature tables
+	if schedulesTable := os.Getenv("DYNAMODB_SCHEDULES_TABLE"); schedulesTable != "" {
+		dbClient.SetSchedulesTable(schedulesTable)
+	}
+	if catalogTable := os.Getenv("DYNAMODB_CATALOG_TABLE"); catalogTable != "" {
+		dbClient.SetCatalogTable(catalogTable)
+	}
+	if thresholdsTable := os.Getenv("DYNAMODB_THRESHOLDS_TABLE"); thresholdsTable != "" {
+		dbClient.SetThresholdsTable(thresholdsTable)
+	}
+	if eventsTable := os.Getenv("DYNAMODB_EVENTS_TABLE"); eventsTable != "" {
+		dbClient.SetEventsTable(eventsTable)
+	}
+
 	// Initialize API handlers
 	apiHandlers := handlers.NewAPIHandlers(dbClient)
 
+	// Direct-message notifications are optional and only need the bot token
+	if token := os.Getenv("DISCORD_BOT_TOKEN"); token != "" {
+		messenger, err := notify.NewMessengerFromToken(token)
+		if err != nil {
+			log.Printf("Direct-message notifications disabled: %v", err)
+		} else {
+			apiHandlers.SetMessenger(messenger)
+		}
+	}
+
+	// Extra draw entries for donors are off unless DONOR_BONUS_ENTRIES is set
+	donorBonus, err := models.ParseDonorBonus(os.Getenv("DONOR_BONUS_ENTRIES"), os.Getenv("DONOR_BONUS_MAX"), os.Getenv("DONOR_BONUS_DAYS"))
+	if err != nil {
+		log.Printf("Donor bonus disabled: %v", err)
+	}
+	apiHandlers.SetDonorBonus(donorBonus)
+
+	// Event attendance only affects eligibility and draws when ATTENDANCE_MIN_EVENTS
+	// or ATTENDANCE_BONUS_ENTRIES is set
+	attendance, err := models.ParseAttendancePolicy(os.Getenv("ATTENDANCE_WINDOW_DAYS"), os.Getenv("ATTENDANCE_MIN_EVENTS"), os.Getenv("ATTENDANCE_BONUS_ENTRIES"), os.Getenv("ATTENDANCE_BONUS_MAX"))
+	if err != nil {
+		log.Printf("Attendance policy disabled: %v", err)
+	}
+	apiHandlers.SetAttendancePolicy(attendance)
+
+	// Channel announcements through Discord webhooks are optional
+	apiHandlers.SetWebhooks(notify.NewWebhookNotifierFromEnv())
+
+	// Maester endpoints require ADMIN_API_KEY in the X-Api-Key header when it is set
+	apiHandlers.SetAPIKey(os.Getenv("ADMIN_API_KEY"))
+
 	// Setup routes
 	mux := apiHandlers.SetupRoutes()
 
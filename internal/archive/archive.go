@@ -0,0 +1,131 @@
+// Package archive snapshots the members, inventory, distributions and
+// distribution lists tables as one versioned JSON document, for backups and
+// for moving data between environments.
+package archive
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"flavaflav/internal/models"
+)
+
+// Format identifies a flavaflav archive
+const Format = "flavaflav-archive"
+
+// SchemaVersion is the archive layout written by this version. Bump it when a
+// change to the models means older tools can't read new archives correctly.
+const SchemaVersion = 1
+
+// Archive is a snapshot of the four core tables
+type Archive struct {
+	Format        string                     `json:"format"`
+	SchemaVersion int                        `json:"schema_version"`
+	ExportedAt    time.Time                  `json:"exported_at"`
+	Source        string                     `json:"source,omitempty"` // e.g., the members table it was exported from
+	Members       []*models.Member           `json:"members"`
+	Inventory     []*models.InventoryLink    `json:"inventory"`
+	Distributions []*models.Distribution     `json:"distributions"`
+	Lists         []*models.DistributionList `json:"lists"`
+}
+
+// New creates an archive of the given records
+func New(source string, members []*models.Member, inventory []*models.InventoryLink, distributions []*models.Distribution, lists []*models.DistributionList) *Archive {
+	return &Archive{
+		Format:        Format,
+		SchemaVersion: SchemaVersion,
+		ExportedAt:    time.Now(),
+		Source:        source,
+		Members:       members,
+		Inventory:     inventory,
+		Distributions: distributions,
+		Lists:         lists,
+	}
+}
+
+// Write encodes the archive as indented JSON
+func (a *Archive) Write(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(a); err != nil {
+		return fmt.Errorf("failed to write archive: %v", err)
+	}
+	return nil
+}
+
+// Read decodes an archive and checks that this version can import it
+func Read(r io.Reader) (*Archive, error) {
+	var a Archive
+	if err := json.NewDecoder(r).Decode(&a); err != nil {
+		return nil, fmt.Errorf("failed to read archive: %v", err)
+	}
+	if a.Format != Format {
+		return nil, fmt.Errorf("not a flavaflav archive (format %q)", a.Format)
+	}
+	if a.SchemaVersion < 1 {
+		return nil, fmt.Errorf("archive has no valid schema version")
+	}
+	if a.SchemaVersion > SchemaVersion {
+		return nil, fmt.Errorf("archive schema version %d is newer than this tool supports (%d); upgrade flavactl", a.SchemaVersion, SchemaVersion)
+	}
+	if err := a.Validate(); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// Validate checks that every record has a valid quality and that no key
+// appears twice. Records without an ID are allowed and get one on import.
+func (a *Archive) Validate() error {
+	members := make(map[string]bool)
+	for i, member := range a.Members {
+		if member == nil || member.DiscordID == "" {
+			return fmt.Errorf("member %d has no discord_id", i+1)
+		}
+		if members[member.DiscordID] {
+			return fmt.Errorf("member %s appears twice", member.DiscordID)
+		}
+		members[member.DiscordID] = true
+	}
+
+	links := make(map[string]bool)
+	for i, link := range a.Inventory {
+		if link == nil || !validQuality(link.Quality) {
+			return fmt.Errorf("inventory link %d has no valid quality", i+1)
+		}
+		if link.LinkID != "" && links[link.LinkID] {
+			return fmt.Errorf("inventory link %s appears twice", link.LinkID)
+		}
+		links[link.LinkID] = true
+	}
+
+	distributions := make(map[string]bool)
+	for i, d := range a.Distributions {
+		if d == nil || d.MemberID == "" || !validQuality(d.Quality) {
+			return fmt.Errorf("distribution %d needs a member_id and a valid quality", i+1)
+		}
+		if d.DistributionID != "" && distributions[d.DistributionID] {
+			return fmt.Errorf("distribution %s appears twice", d.DistributionID)
+		}
+		distributions[d.DistributionID] = true
+	}
+
+	lists := make(map[string]bool)
+	for i, list := range a.Lists {
+		if list == nil || !validQuality(list.Quality) {
+			return fmt.Errorf("distribution list %d has no valid quality", i+1)
+		}
+		if list.ListID != "" && lists[list.ListID] {
+			return fmt.Errorf("distribution list %s appears twice", list.ListID)
+		}
+		lists[list.ListID] = true
+	}
+
+	return nil
+}
+
+func validQuality(quality string) bool {
+	return quality == models.QualityBronze || quality == models.QualitySilver || quality == models.QualityGold
+}
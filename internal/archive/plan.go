@@ -0,0 +1,294 @@
+package archive
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"flavaflav/internal/models"
+)
+
+// ImportPlan lists the records an import will create. Records already in the
+// target with identical contents are skipped. A link, distribution or list
+// whose ID is taken by a different record gets a new ID derived from the
+// archived record, and references to it are rewritten; records without an ID
+// get a derived ID too. A later run derives the same IDs and skips records it
+// finds under them, so an archive can be imported twice and an interrupted
+// import resumed. Members are keyed by Discord ID and are never remapped: an
+// existing member is kept as-is.
+type ImportPlan struct {
+	Members       []*models.Member           `json:"-"`
+	Inventory     []*models.InventoryLink    `json:"-"`
+	Distributions []*models.Distribution     `json:"-"`
+	Lists         []*models.DistributionList `json:"-"`
+
+	Unchanged   map[string]int    `json:"unchanged"`    // records already in the target, by kind
+	KeptMembers []string          `json:"kept_members"` // members already in the target with different data
+	RemappedIDs map[string]string `json:"remapped_ids"` // archive ID to the ID it is created with
+	Warnings    []string          `json:"warnings"`     // references to records in neither the archive nor the target
+
+	known map[string]bool // kind:ID of every record in the archive or the target
+}
+
+// idMaps maps IDs of links, distributions and lists to other IDs, such as
+// archive IDs taken by different records to the IDs they are created with
+type idMaps struct {
+	links         map[string]string
+	distributions map[string]string
+	lists         map[string]string
+}
+
+func newIDMaps() *idMaps {
+	return &idMaps{
+		links:         make(map[string]string),
+		distributions: make(map[string]string),
+		lists:         make(map[string]string),
+	}
+}
+
+// Plan works out how to import an archive into a target holding the
+// existing records. It rewrites IDs and references in the archive's records.
+func Plan(a *Archive, existing *Archive) *ImportPlan {
+	plan := &ImportPlan{
+		Unchanged:   make(map[string]int),
+		RemappedIDs: make(map[string]string),
+		known:       make(map[string]bool),
+	}
+
+	existingMembers := make(map[string]*models.Member)
+	for _, member := range existing.Members {
+		existingMembers[member.DiscordID] = member
+		plan.known["member:"+member.DiscordID] = true
+	}
+	for _, member := range a.Members {
+		plan.known["member:"+member.DiscordID] = true
+		if current, ok := existingMembers[member.DiscordID]; ok {
+			if sameRecord(member, current) {
+				plan.Unchanged["members"]++
+			} else {
+				plan.KeptMembers = append(plan.KeptMembers, member.DiscordID)
+			}
+			continue
+		}
+		plan.Members = append(plan.Members, member)
+	}
+
+	existingLinks := make(map[string]*models.InventoryLink)
+	for _, link := range existing.Inventory {
+		existingLinks[link.LinkID] = link
+		plan.known["link:"+link.LinkID] = true
+	}
+	existingDistributions := make(map[string]*models.Distribution)
+	for _, d := range existing.Distributions {
+		existingDistributions[d.DistributionID] = d
+		plan.known["distribution:"+d.DistributionID] = true
+	}
+	existingLists := make(map[string]*models.DistributionList)
+	for _, list := range existing.Lists {
+		existingLists[list.ListID] = list
+		plan.known["list:"+list.ListID] = true
+	}
+
+	// Derive replacement IDs from the records as archived, before any rewriting
+	seeds := newSeeder()
+	linkIDs := make([]string, len(a.Inventory))
+	for i, link := range a.Inventory {
+		plan.known["link:"+link.LinkID] = true
+		linkIDs[i] = models.DerivedID(models.IDPrefixLink, link.AddedDate, seeds.seed("link", link.LinkID, link))
+	}
+	distributionIDs := make([]string, len(a.Distributions))
+	for i, d := range a.Distributions {
+		plan.known["distribution:"+d.DistributionID] = true
+		distributionIDs[i] = models.DerivedID(models.IDPrefixDistribution, d.DistributedAt, seeds.seed("distribution", d.DistributionID, d))
+	}
+	listIDs := make([]string, len(a.Lists))
+	for i, list := range a.Lists {
+		plan.known["list:"+list.ListID] = true
+		listIDs[i] = models.DerivedID(models.IDPrefixList, list.CreatedAt, seeds.seed("list", list.ListID, list))
+	}
+
+	for _, link := range a.Inventory {
+		for _, from := range link.FusedFrom {
+			plan.checkRef("inventory link "+link.LinkID, "link", from)
+		}
+		plan.checkRef("inventory link "+link.LinkID, "link", link.FusedInto)
+	}
+	for _, d := range a.Distributions {
+		plan.checkRef("distribution "+d.DistributionID, "member", d.MemberID)
+		plan.checkRef("distribution "+d.DistributionID, "link", d.LinkID)
+		plan.checkRef("distribution "+d.DistributionID, "list", d.ListID)
+	}
+
+	// Find the archive IDs taken by different records. A record created by an
+	// earlier import has references rewritten to derived IDs, so compare the
+	// target's copy with those turned back into archive IDs.
+	back := newIDMaps()
+	for i, link := range a.Inventory {
+		if link.LinkID != "" {
+			back.links[linkIDs[i]] = link.LinkID
+		}
+	}
+	for i, d := range a.Distributions {
+		if d.DistributionID != "" {
+			back.distributions[distributionIDs[i]] = d.DistributionID
+		}
+	}
+	for i, list := range a.Lists {
+		if list.ListID != "" {
+			back.lists[listIDs[i]] = list.ListID
+		}
+	}
+
+	ids := newIDMaps()
+	for i, link := range a.Inventory {
+		if current, taken := existingLinks[link.LinkID]; taken {
+			restored := clone(current)
+			back.rewriteLink(restored)
+			if !sameRecord(link, restored) {
+				ids.links[link.LinkID] = linkIDs[i]
+			}
+		}
+	}
+	for i, d := range a.Distributions {
+		if current, taken := existingDistributions[d.DistributionID]; taken {
+			restored := clone(current)
+			back.rewriteDistribution(restored)
+			if !sameRecord(d, restored) {
+				ids.distributions[d.DistributionID] = distributionIDs[i]
+			}
+		}
+	}
+	for i, list := range a.Lists {
+		if current, taken := existingLists[list.ListID]; taken && !sameRecord(list, current) {
+			ids.lists[list.ListID] = listIDs[i]
+		}
+	}
+
+	// Assign the final IDs, skip records already in the target and rewrite
+	// references in the rest
+	for i, link := range a.Inventory {
+		id, create := assignID(plan, link.LinkID, ids.links, linkIDs[i], existingLinks)
+		if !create {
+			plan.Unchanged["inventory"]++
+			continue
+		}
+		ids.rewriteLink(link)
+		link.LinkID = id
+		plan.Inventory = append(plan.Inventory, link)
+	}
+	for i, d := range a.Distributions {
+		id, create := assignID(plan, d.DistributionID, ids.distributions, distributionIDs[i], existingDistributions)
+		if !create {
+			plan.Unchanged["distributions"]++
+			continue
+		}
+		ids.rewriteDistribution(d)
+		d.DistributionID = id
+		plan.Distributions = append(plan.Distributions, d)
+	}
+	for i, list := range a.Lists {
+		id, create := assignID(plan, list.ListID, ids.lists, listIDs[i], existingLists)
+		if !create {
+			plan.Unchanged["lists"]++
+			continue
+		}
+		list.ListID = id
+		plan.Lists = append(plan.Lists, list)
+	}
+
+	return plan
+}
+
+// assignID returns the ID an archived record is created with, and false if
+// the target already holds it: identical under its own ID, or created by an
+// earlier run under its derived ID
+func assignID[T any](p *ImportPlan, id string, remapped map[string]string, derived string, existing map[string]T) (string, bool) {
+	if id == "" {
+		id = derived
+	} else if newID, ok := remapped[id]; ok {
+		p.RemappedIDs[id] = newID
+		id = newID
+	}
+	_, exists := existing[id]
+	return id, !exists
+}
+
+// rewriteLink points a link's references at remapped links and lists
+func (m *idMaps) rewriteLink(link *models.InventoryLink) {
+	for i, from := range link.FusedFrom {
+		link.FusedFrom[i] = mapped(m.links, from)
+	}
+	link.FusedInto = mapped(m.links, link.FusedInto)
+	link.ReservedListID = mapped(m.lists, link.ReservedListID)
+}
+
+// rewriteDistribution points a distribution's references at remapped records
+func (m *idMaps) rewriteDistribution(d *models.Distribution) {
+	d.LinkID = mapped(m.links, d.LinkID)
+	d.ListID = mapped(m.lists, d.ListID)
+	d.ReassignedTo = mapped(m.distributions, d.ReassignedTo)
+}
+
+// seeder builds the seeds for derived IDs. Records without an ID are told
+// apart by their contents and, for identical ones, their order in the archive.
+type seeder struct {
+	seen map[string]int
+}
+
+func newSeeder() *seeder {
+	return &seeder{seen: make(map[string]int)}
+}
+
+func (s *seeder) seed(kind, id string, record interface{}) string {
+	contents, _ := json.Marshal(record)
+	seed := kind + "\x00" + id + "\x00" + string(contents)
+	n := s.seen[seed]
+	s.seen[seed]++
+	return fmt.Sprintf("%s\x00%d", seed, n)
+}
+
+// clone copies a record through its JSON encoding
+func clone[T any](record *T) *T {
+	copied := new(T)
+	if data, err := json.Marshal(record); err == nil {
+		json.Unmarshal(data, copied)
+	}
+	return copied
+}
+
+// checkRef warns about a reference to a record in neither the archive nor the target
+func (p *ImportPlan) checkRef(from, kind, id string) {
+	if id != "" && !p.known[kind+":"+id] {
+		p.Warnings = append(p.Warnings, fmt.Sprintf("%s refers to %s %s, which is in neither the archive nor the target", from, kind, id))
+	}
+}
+
+// Counts returns how many records of each kind the import will create
+func (p *ImportPlan) Counts() map[string]int {
+	return map[string]int{
+		"members":       len(p.Members),
+		"inventory":     len(p.Inventory),
+		"distributions": len(p.Distributions),
+		"lists":         len(p.Lists),
+	}
+}
+
+// mapped returns the new ID for a remapped one, or the ID unchanged
+func mapped(ids map[string]string, id string) string {
+	if newID, ok := ids[id]; ok {
+		return newID
+	}
+	return id
+}
+
+// sameRecord compares two records by their JSON encoding
+func sameRecord(a, b interface{}) bool {
+	left, err := json.Marshal(a)
+	if err != nil {
+		return false
+	}
+	right, err := json.Marshal(b)
+	if err != nil {
+		return false
+	}
+	return string(left) == string(right)
+}
@@ -0,0 +1,126 @@
+package archive
+
+import (
+	"testing"
+	"time"
+
+	"flavaflav/internal/models"
+)
+
+var added = time.Date(2026, 9, 1, 12, 0, 0, 0, time.UTC)
+
+func link(id, linkType string) *models.InventoryLink {
+	return &models.InventoryLink{LinkID: id, LinkType: linkType, Quality: models.QualityGold, IsAvailable: "true", AddedDate: added}
+}
+
+func distribution(id, memberID, linkID, listID string) *models.Distribution {
+	return &models.Distribution{DistributionID: id, MemberID: memberID, LinkID: linkID, ListID: listID, Quality: models.QualityGold, DistributedAt: added}
+}
+
+func list(id, name string) *models.DistributionList {
+	return &models.DistributionList{ListID: id, ListName: name, Quality: models.QualityGold, CreatedAt: added}
+}
+
+// incoming builds a fresh archive each time, since Plan rewrites its records
+func incoming() *Archive {
+	return New("",
+		[]*models.Member{{DiscordID: "1", Username: "alice"}, {DiscordID: "2", Username: "bob"}},
+		[]*models.InventoryLink{link("link_A", "Melee Damage"), link("link_B", "Mage Damage"), link("", "Tamer Damage")},
+		[]*models.Distribution{distribution("dist_A", "1", "link_A", "list_A"), distribution("dist_B", "2", "link_B", "list_A")},
+		[]*models.DistributionList{list("list_A", "Gold Links")},
+	)
+}
+
+// apply adds the records a plan creates to the target
+func apply(target *Archive, plan *ImportPlan) {
+	target.Members = append(target.Members, plan.Members...)
+	target.Inventory = append(target.Inventory, plan.Inventory...)
+	target.Distributions = append(target.Distributions, plan.Distributions...)
+	target.Lists = append(target.Lists, plan.Lists...)
+}
+
+func TestPlanIntoEmptyTarget(t *testing.T) {
+	plan := Plan(incoming(), New("", nil, nil, nil, nil))
+
+	want := map[string]int{"members": 2, "inventory": 3, "distributions": 2, "lists": 1}
+	for kind, n := range want {
+		if got := plan.Counts()[kind]; got != n {
+			t.Errorf("%s to create = %d, want %d", kind, got, n)
+		}
+	}
+	if len(plan.RemappedIDs) != 0 {
+		t.Errorf("RemappedIDs = %v, want none", plan.RemappedIDs)
+	}
+	if id := plan.Inventory[2].LinkID; id == "" {
+		t.Error("link without an ID was not given one")
+	}
+}
+
+func TestPlanRemapsTakenIDs(t *testing.T) {
+	target := New("",
+		[]*models.Member{{DiscordID: "1", Username: "alice (renamed)"}},
+		[]*models.InventoryLink{link("link_A", "Ranged Damage")},
+		nil,
+		[]*models.DistributionList{list("list_A", "Another List")},
+	)
+	plan := Plan(incoming(), target)
+
+	if len(plan.KeptMembers) != 1 || plan.KeptMembers[0] != "1" {
+		t.Errorf("KeptMembers = %v, want [1]", plan.KeptMembers)
+	}
+	newLink, newList := plan.RemappedIDs["link_A"], plan.RemappedIDs["list_A"]
+	if newLink == "" || newList == "" {
+		t.Fatalf("RemappedIDs = %v, want link_A and list_A remapped", plan.RemappedIDs)
+	}
+	for _, d := range plan.Distributions {
+		if d.ListID != newList {
+			t.Errorf("distribution %s list = %s, want %s", d.DistributionID, d.ListID, newList)
+		}
+		if d.DistributionID == "dist_A" && d.LinkID != newLink {
+			t.Errorf("distribution dist_A link = %s, want %s", d.LinkID, newLink)
+		}
+	}
+}
+
+func TestPlanIsRepeatable(t *testing.T) {
+	tests := []struct {
+		name   string
+		target func() *Archive
+	}{
+		{"empty target", func() *Archive { return New("", nil, nil, nil, nil) }},
+		{"taken IDs", func() *Archive {
+			return New("", nil,
+				[]*models.InventoryLink{link("link_A", "Ranged Damage")},
+				[]*models.Distribution{distribution("dist_B", "9", "link_Z", "")},
+				[]*models.DistributionList{list("list_A", "Another List")})
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := tt.target()
+			first := Plan(incoming(), target)
+			apply(target, first)
+
+			second := Plan(incoming(), target)
+			for kind, n := range second.Counts() {
+				if n != 0 {
+					t.Errorf("second run creates %d %s", n, kind)
+				}
+			}
+			for id, newID := range first.RemappedIDs {
+				if second.RemappedIDs[id] != newID {
+					t.Errorf("%s remapped to %s, then %s", id, newID, second.RemappedIDs[id])
+				}
+			}
+		})
+	}
+}
+
+func TestPlanWarnsAboutDanglingReferences(t *testing.T) {
+	a := New("", nil, nil, []*models.Distribution{distribution("dist_A", "1", "link_gone", "")}, nil)
+	plan := Plan(a, New("", nil, nil, nil, nil))
+	if len(plan.Warnings) != 2 {
+		t.Errorf("Warnings = %v, want the member and the link", plan.Warnings)
+	}
+}
@@ -0,0 +1,127 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"flavaflav/internal/models"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+const (
+	// batchWriteLimit is the most items DynamoDB accepts in one BatchWriteItem call
+	batchWriteLimit = 25
+	// batchMaxRetries is how many times unprocessed items are resubmitted
+	batchMaxRetries = 6
+	// batchRetryDelay is the first retry delay; it doubles on each retry
+	batchRetryDelay = 50 * time.Millisecond
+)
+
+// BatchWriteError reports a batch create that failed. Items already written
+// are deleted again so the batch is all-or-nothing; RolledBack is false if
+// that cleanup failed too and Written items may remain.
+type BatchWriteError struct {
+	Requested  int
+	Written    int
+	RolledBack bool
+	Err        error
+}
+
+func (e *BatchWriteError) Error() string {
+	if e.RolledBack || e.Written == 0 {
+		return fmt.Sprintf("created none of %d items: %v", e.Requested, e.Err)
+	}
+	return fmt.Sprintf("created %d of %d items and could not remove them: %v", e.Written, e.Requested, e.Err)
+}
+
+func (e *BatchWriteError) Unwrap() error {
+	return e.Err
+}
+
+// ==========================================
+// Batch Operations (Inventory Table)
+// ==========================================
+
+// BatchCreateInventoryLinks creates inventory links with BatchWriteItem,
+// retrying unprocessed items with backoff. Either every link is created or a
+// *BatchWriteError is returned after removing any that were.
+// BatchWriteItem can't be conditional, so this relies on the links having
+// fresh IDs from models.NewID.
+func (db *DynamoDBClient) BatchCreateInventoryLinks(ctx context.Context, links []*models.InventoryLink) error {
+	puts := make([]types.WriteRequest, 0, len(links))
+	deletes := make([]types.WriteRequest, 0, len(links))
+	for _, link := range links {
+		item, err := attributevalue.MarshalMap(link)
+		if err != nil {
+			return &BatchWriteError{Requested: len(links), RolledBack: true, Err: fmt.Errorf("failed to marshal inventory link: %v", err)}
+		}
+		puts = append(puts, types.WriteRequest{PutRequest: &types.PutRequest{Item: item}})
+		deletes = append(deletes, types.WriteRequest{DeleteRequest: &types.DeleteRequest{
+			Key: map[string]types.AttributeValue{
+				"link_id": &types.AttributeValueMemberS{Value: link.LinkID},
+			},
+		}})
+	}
+
+	written, err := db.batchWrite(ctx, db.inventoryTable, puts)
+	if err == nil {
+		return nil
+	}
+
+	batchErr := &BatchWriteError{Requested: len(links), Written: written, Err: err}
+	if written == 0 {
+		batchErr.RolledBack = true
+		return batchErr
+	}
+
+	// Delete every requested key: links that were never written are no-ops
+	if _, rollbackErr := db.batchWrite(context.WithoutCancel(ctx), db.inventoryTable, deletes); rollbackErr == nil {
+		batchErr.RolledBack = true
+	}
+	return batchErr
+}
+
+// batchWrite submits write requests in batches of 25, resubmitting unprocessed
+// items with exponential backoff. It returns the number of requests applied.
+func (db *DynamoDBClient) batchWrite(ctx context.Context, table string, requests []types.WriteRequest) (int, error) {
+	written := 0
+	for start := 0; start < len(requests); start += batchWriteLimit {
+		end := start + batchWriteLimit
+		if end > len(requests) {
+			end = len(requests)
+		}
+
+		pending := requests[start:end]
+		delay := batchRetryDelay
+		for attempt := 0; len(pending) > 0; attempt++ {
+			if attempt > 0 {
+				if attempt > batchMaxRetries {
+					return written, fmt.Errorf("%d items still unprocessed after %d retries", len(pending), batchMaxRetries)
+				}
+				select {
+				case <-ctx.Done():
+					return written, ctx.Err()
+				case <-time.After(delay):
+				}
+				delay *= 2
+			}
+
+			result, err := db.client.BatchWriteItem(ctx, &dynamodb.BatchWriteItemInput{
+				RequestItems: map[string][]types.WriteRequest{table: pending},
+			})
+			if err != nil {
+				return written, fmt.Errorf("failed to batch write to %s: %v", table, err)
+			}
+
+			unprocessed := result.UnprocessedItems[table]
+			written += len(pending) - len(unprocessed)
+			pending = unprocessed
+		}
+	}
+
+	return written, nil
+}
@@ -0,0 +1,153 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"flavaflav/internal/models"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// ErrCatalogDisabled is returned when no Catalog table is configured
+var ErrCatalogDisabled = errors.New("catalog table is not configured")
+
+// ErrCatalogEntryNotFound is returned when deleting a version that isn't stored
+var ErrCatalogEntryNotFound = errors.New("catalog entry not found")
+
+// ==========================================
+// Link Catalog Operations (Catalog Table)
+// ==========================================
+
+// SetCatalogTable enables the optional link Catalog table
+func (db *DynamoDBClient) SetCatalogTable(catalogTable string) {
+	db.catalogTable = catalogTable
+}
+
+// CatalogEnabled returns true if a Catalog table is configured
+func (db *DynamoDBClient) CatalogEnabled() bool {
+	return db.catalogTable != ""
+}
+
+// GetLinkCatalog loads the link catalog. Each built-in link type keeps its
+// built-in values as the version in force since the beginning of time unless
+// the table stores one for that time, so the catalog works before seeding,
+// without a Catalog table at all, and for links added before a type's first
+// stored version.
+func (db *DynamoDBClient) GetLinkCatalog(ctx context.Context) (*models.LinkCatalog, error) {
+	if !db.CatalogEnabled() {
+		return models.DefaultLinkCatalog(), nil
+	}
+
+	entries, err := db.GetCatalogEntries(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return models.NewLinkCatalog(models.WithBuiltinBaselines(entries)), nil
+}
+
+// GetCatalogEntries retrieves every stored catalog version
+func (db *DynamoDBClient) GetCatalogEntries(ctx context.Context) ([]*models.CatalogEntry, error) {
+	if !db.CatalogEnabled() {
+		return nil, ErrCatalogDisabled
+	}
+
+	var entries []*models.CatalogEntry
+	paginator := dynamodb.NewScanPaginator(db.client, &dynamodb.ScanInput{
+		TableName: aws.String(db.catalogTable),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan link catalog: %v", err)
+		}
+		for _, item := range page.Items {
+			var entry models.CatalogEntry
+			if err := attributevalue.UnmarshalMap(item, &entry); err != nil {
+				continue // Skip invalid items
+			}
+			entries = append(entries, &entry)
+		}
+	}
+
+	return entries, nil
+}
+
+// PutCatalogEntry creates or replaces one catalog version. EffectiveFrom is
+// stored in UTC, so the same instant always gives the same sort key.
+func (db *DynamoDBClient) PutCatalogEntry(ctx context.Context, entry *models.CatalogEntry) error {
+	if !db.CatalogEnabled() {
+		return ErrCatalogDisabled
+	}
+
+	entry.EffectiveFrom = entry.EffectiveFrom.UTC()
+
+	item, err := attributevalue.MarshalMap(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal catalog entry: %v", err)
+	}
+
+	_, err = db.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(db.catalogTable),
+		Item:      item,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save catalog entry: %v", err)
+	}
+
+	return nil
+}
+
+// DeleteCatalogEntry removes the version of a link type effective from an
+// instant, whatever time zone and precision it was given or stored with
+func (db *DynamoDBClient) DeleteCatalogEntry(ctx context.Context, name string, effectiveFrom time.Time) error {
+	if !db.CatalogEnabled() {
+		return ErrCatalogDisabled
+	}
+
+	result, err := db.client.Query(ctx, &dynamodb.QueryInput{
+		TableName:                aws.String(db.catalogTable),
+		KeyConditionExpression:   aws.String("#name = :name"),
+		ExpressionAttributeNames: map[string]string{"#name": "name"},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":name": &types.AttributeValueMemberS{Value: name},
+		},
+		ProjectionExpression: aws.String("effective_from"),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to query catalog entry: %v", err)
+	}
+
+	// Versions written before effective_from was stored in UTC may carry
+	// another offset, so match on the instant and delete by the stored key
+	for _, item := range result.Items {
+		stored, ok := item["effective_from"].(*types.AttributeValueMemberS)
+		if !ok {
+			continue
+		}
+		at, err := time.Parse(time.RFC3339Nano, stored.Value)
+		if err != nil || !at.Equal(effectiveFrom) {
+			continue
+		}
+
+		_, err = db.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+			TableName: aws.String(db.catalogTable),
+			Key: map[string]types.AttributeValue{
+				"name":           &types.AttributeValueMemberS{Value: name},
+				"effective_from": stored,
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to delete catalog entry: %v", err)
+		}
+		return nil
+	}
+
+	return ErrCatalogEntryNotFound
+}
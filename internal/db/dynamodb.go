@@ -2,8 +2,10 @@ package db
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strconv"
+	"time"
 
 	"flavaflav/internal/models"
 
@@ -14,13 +16,40 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 )
 
+// ErrLinkUnavailable is returned when a link was distributed by someone else first
+var ErrLinkUnavailable = errors.New("inventory link is no longer available")
+
+// ErrLinkChanged is returned when a link's status changed while it was being edited
+var ErrLinkChanged = errors.New("inventory link was changed by someone else")
+
+// ErrAlreadyReversed is returned when a distribution was reversed by someone else first
+var ErrAlreadyReversed = errors.New("distribution was already reversed")
+
+// ErrMemberNotFound is returned when no member is registered with a Discord ID
+var ErrMemberNotFound = errors.New("member not found")
+
+// ErrMemberChanged is returned when a member was removed or promoted since it was read
+var ErrMemberChanged = errors.New("member was changed by someone else")
+
+// ErrListChanged is returned when a distribution list was saved by someone else
+// since it was read
+var ErrListChanged = errors.New("distribution list was changed by someone else")
+
+// ErrAlreadyExists is returned when creating an item whose key is already taken
+var ErrAlreadyExists = errors.New("item already exists")
+
 // DynamoDBClient wraps the AWS DynamoDB client with four tables
+// plus optional feature tables enabled through setters
 type DynamoDBClient struct {
 	client             *dynamodb.Client
 	membersTable       string
 	inventoryTable     string
 	distributionsTable string
 	listsTable         string
+	schedulesTable     string
+	catalogTable       string
+	thresholdsTable    string
+	eventsTable        string
 }
 
 // NewDynamoDBClient creates a new DynamoDB client for four tables
@@ -43,17 +72,18 @@ func NewDynamoDBClient(membersTable, inventoryTable, distributionsTable, listsTa
 // Member Operations (Members Table)
 // ==========================================
 
-// CreateMember creates a new member in the Members table
+// CreateMember creates a new member in the Members table; it returns
+// ErrAlreadyExists if the member is already registered
 func (db *DynamoDBClient) CreateMember(ctx context.Context, member *models.Member) error {
 	item, err := attributevalue.MarshalMap(member)
 	if err != nil {
 		return fmt.Errorf("failed to marshal member: %v", err)
 	}
 
-	_, err = db.client.PutItem(ctx, &dynamodb.PutItemInput{
-		TableName: aws.String(db.membersTable),
-		Item:      item,
-	})
+	err = db.putNew(ctx, db.membersTable, "discord_id", item)
+	if errors.Is(err, ErrAlreadyExists) {
+		return err
+	}
 	if err != nil {
 		return fmt.Errorf("failed to create member: %v", err)
 	}
@@ -74,7 +104,7 @@ func (db *DynamoDBClient) GetMember(ctx context.Context, discordID string) (*mod
 	}
 
 	if result.Item == nil {
-		return nil, fmt.Errorf("member not found")
+		return nil, ErrMemberNotFound
 	}
 
 	var member models.Member
@@ -104,23 +134,155 @@ func (db *DynamoDBClient) UpdateMember(ctx context.Context, member *models.Membe
 	return nil
 }
 
-// GetAllMembers retrieves all members from the Members table
-func (db *DynamoDBClient) GetAllMembers(ctx context.Context) ([]*models.Member, error) {
-	result, err := db.client.Scan(ctx, &dynamodb.ScanInput{
+// UpdateMemberEligibility saves only a member's rank, eligibility and days in
+// the guild, so edits made since the member was read are kept. It returns
+// ErrMemberChanged if the member was removed or promoted in the meantime.
+func (db *DynamoDBClient) UpdateMemberEligibility(ctx context.Context, member *models.Member) error {
+	values, err := attributevalue.MarshalMap(map[string]interface{}{
+		":rank":       member.Rank,
+		":silver":     member.SilverEligible,
+		":gold":       member.GoldEligible,
+		":days":       member.DaysInGuild,
+		":updated_at": member.UpdatedAt,
+		":is_officer": member.IsOfficer,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal member eligibility: %v", err)
+	}
+
+	_, err = db.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(db.membersTable),
+		Key: map[string]types.AttributeValue{
+			"discord_id": &types.AttributeValueMemberS{Value: member.DiscordID},
+		},
+		UpdateExpression:          aws.String("SET #rank = :rank, silver_eligible = :silver, gold_eligible = :gold, days_in_guild = :days, updated_at = :updated_at"),
+		ConditionExpression:       aws.String("attribute_exists(discord_id) AND is_officer = :is_officer"),
+		ExpressionAttributeNames:  map[string]string{"#rank": "rank"},
+		ExpressionAttributeValues: values,
+	})
+	var conditionFailed *types.ConditionalCheckFailedException
+	if errors.As(err, &conditionFailed) {
+		return ErrMemberChanged
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update member eligibility: %v", err)
+	}
+
+	return nil
+}
+
+// UpdateMemberCharacters saves only a member's characters, so edits made since
+// the member was read are kept. It returns ErrMemberNotFound if the member was
+// removed in the meantime.
+func (db *DynamoDBClient) UpdateMemberCharacters(ctx context.Context, member *models.Member) error {
+	return db.updateMemberAttribute(ctx, member, "characters", member.Characters, len(member.Characters) == 0)
+}
+
+// UpdateMemberBuildTags saves only a member's build tags, so edits made since
+// the member was read are kept. It returns ErrMemberNotFound if the member was
+// removed in the meantime.
+func (db *DynamoDBClient) UpdateMemberBuildTags(ctx context.Context, member *models.Member) error {
+	return db.updateMemberAttribute(ctx, member, "build_tags", member.BuildTags, len(member.BuildTags) == 0)
+}
+
+// UpdateMemberNotifications turns notification kinds on or off for a member by
+// adding them to or deleting them from the member's opted-out set, so
+// concurrent changes to other kinds or fields are kept. It returns the member
+// as saved, or ErrMemberNotFound if the member doesn't exist.
+func (db *DynamoDBClient) UpdateMemberNotifications(ctx context.Context, discordID string, kinds []string, enabled bool) (*models.Member, error) {
+	values, err := attributevalue.MarshalMap(map[string]interface{}{
+		":updated_at": time.Now(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal member notifications: %v", err)
+	}
+	values[":kinds"] = &types.AttributeValueMemberSS{Value: kinds}
+	update := "SET updated_at = :updated_at ADD disabled_notifications :kinds"
+	if enabled {
+		update = "SET updated_at = :updated_at DELETE disabled_notifications :kinds"
+	}
+
+	result, err := db.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(db.membersTable),
+		Key: map[string]types.AttributeValue{
+			"discord_id": &types.AttributeValueMemberS{Value: discordID},
+		},
+		UpdateExpression:          aws.String(update),
+		ConditionExpression:       aws.String("attribute_exists(discord_id)"),
+		ExpressionAttributeValues: values,
+		ReturnValues:              types.ReturnValueAllNew,
+	})
+	var conditionFailed *types.ConditionalCheckFailedException
+	if errors.As(err, &conditionFailed) {
+		return nil, ErrMemberNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update member notifications: %v", err)
+	}
+
+	var member models.Member
+	if err := attributevalue.UnmarshalMap(result.Attributes, &member); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal member: %v", err)
+	}
+	return &member, nil
+}
+
+// updateMemberAttribute sets one attribute of a member and stamps updated_at,
+// or removes the attribute when it is empty, as a full put with omitempty would
+func (db *DynamoDBClient) updateMemberAttribute(ctx context.Context, member *models.Member, name string, value interface{}, empty bool) error {
+	member.UpdatedAt = time.Now()
+	updatedAt, err := attributevalue.Marshal(member.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to marshal member %s: %v", name, err)
+	}
+	values := map[string]types.AttributeValue{":updated_at": updatedAt}
+	update := "SET updated_at = :updated_at REMOVE #attr"
+	if !empty {
+		if values[":value"], err = attributevalue.Marshal(value); err != nil {
+			return fmt.Errorf("failed to marshal member %s: %v", name, err)
+		}
+		update = "SET updated_at = :updated_at, #attr = :value"
+	}
+
+	_, err = db.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
 		TableName: aws.String(db.membersTable),
+		Key: map[string]types.AttributeValue{
+			"discord_id": &types.AttributeValueMemberS{Value: member.DiscordID},
+		},
+		UpdateExpression:          aws.String(update),
+		ConditionExpression:       aws.String("attribute_exists(discord_id)"),
+		ExpressionAttributeNames:  map[string]string{"#attr": name},
+		ExpressionAttributeValues: values,
 	})
+	var conditionFailed *types.ConditionalCheckFailedException
+	if errors.As(err, &conditionFailed) {
+		return ErrMemberNotFound
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to scan members: %v", err)
+		return fmt.Errorf("failed to update member %s: %v", name, err)
 	}
 
+	return nil
+}
+
+// GetAllMembers retrieves all members from the Members table
+func (db *DynamoDBClient) GetAllMembers(ctx context.Context) ([]*models.Member, error) {
 	var members []*models.Member
-	for _, item := range result.Items {
-		var member models.Member
-		err = attributevalue.UnmarshalMap(item, &member)
+	paginator := dynamodb.NewScanPaginator(db.client, &dynamodb.ScanInput{
+		TableName: aws.String(db.membersTable),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
 		if err != nil {
-			continue // Skip invalid items
+			return nil, fmt.Errorf("failed to scan members: %v", err)
+		}
+		for _, item := range page.Items {
+			var member models.Member
+			if err := attributevalue.UnmarshalMap(item, &member); err != nil {
+				continue // Skip invalid items
+			}
+			members = append(members, &member)
 		}
-		members = append(members, &member)
 	}
 
 	return members, nil
@@ -130,17 +292,17 @@ func (db *DynamoDBClient) GetAllMembers(ctx context.Context) ([]*models.Member,
 // Inventory Operations (Inventory Table)
 // ==========================================
 
-// CreateInventoryLink creates a new inventory link
+// CreateInventoryLink creates a new inventory link, refusing to overwrite an existing one
 func (db *DynamoDBClient) CreateInventoryLink(ctx context.Context, link *models.InventoryLink) error {
 	item, err := attributevalue.MarshalMap(link)
 	if err != nil {
 		return fmt.Errorf("failed to marshal inventory link: %v", err)
 	}
 
-	_, err = db.client.PutItem(ctx, &dynamodb.PutItemInput{
-		TableName: aws.String(db.inventoryTable),
-		Item:      item,
-	})
+	err = db.putNew(ctx, db.inventoryTable, "link_id", item)
+	if errors.Is(err, ErrAlreadyExists) {
+		return err
+	}
 	if err != nil {
 		return fmt.Errorf("failed to create inventory link: %v", err)
 	}
@@ -169,6 +331,7 @@ func (db *DynamoDBClient) GetInventoryLink(ctx context.Context, linkID string) (
 	if err != nil {
 		return nil, fmt.Errorf("failed to unmarshal inventory link: %v", err)
 	}
+	link.Normalize()
 
 	return &link, nil
 }
@@ -191,6 +354,80 @@ func (db *DynamoDBClient) UpdateInventoryLink(ctx context.Context, link *models.
 	return nil
 }
 
+// SaveInventoryLinkChange saves an edited link only if its status is still
+// previousStatus, so two officers can't e.g. retire and distribute the same link
+func (db *DynamoDBClient) SaveInventoryLinkChange(ctx context.Context, link *models.InventoryLink, previousStatus string) error {
+	item, err := attributevalue.MarshalMap(link)
+	if err != nil {
+		return fmt.Errorf("failed to marshal inventory link: %v", err)
+	}
+
+	// Links stored before statuses were tracked only have is_available
+	condition := "#status = :status OR (attribute_not_exists(#status) AND is_available = :available)"
+	available := "false"
+	if previousStatus == models.LinkStatusAvailable {
+		available = "true"
+	}
+
+	_, err = db.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:                aws.String(db.inventoryTable),
+		Item:                     item,
+		ConditionExpression:      aws.String(condition),
+		ExpressionAttributeNames: map[string]string{"#status": "status"},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":status":    &types.AttributeValueMemberS{Value: previousStatus},
+			":available": &types.AttributeValueMemberS{Value: available},
+		},
+	})
+	var conditionFailed *types.ConditionalCheckFailedException
+	if errors.As(err, &conditionFailed) {
+		return ErrLinkChanged
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update inventory link: %v", err)
+	}
+
+	return nil
+}
+
+// GetRetiredInventoryLinks retrieves links taken out of stock
+func (db *DynamoDBClient) GetRetiredInventoryLinks(ctx context.Context) ([]*models.InventoryLink, error) {
+	return db.getInventoryLinksByStatus(ctx, models.LinkStatusRetired)
+}
+
+// GetReservedInventoryLinks retrieves links held for draw winners
+func (db *DynamoDBClient) GetReservedInventoryLinks(ctx context.Context) ([]*models.InventoryLink, error) {
+	return db.getInventoryLinksByStatus(ctx, models.LinkStatusReserved)
+}
+
+func (db *DynamoDBClient) getInventoryLinksByStatus(ctx context.Context, status string) ([]*models.InventoryLink, error) {
+	var links []*models.InventoryLink
+	paginator := dynamodb.NewScanPaginator(db.client, &dynamodb.ScanInput{
+		TableName:                aws.String(db.inventoryTable),
+		FilterExpression:         aws.String("#status = :status"),
+		ExpressionAttributeNames: map[string]string{"#status": "status"},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":status": &types.AttributeValueMemberS{Value: status},
+		},
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan %s inventory links: %v", status, err)
+		}
+		for _, item := range page.Items {
+			var link models.InventoryLink
+			if err := attributevalue.UnmarshalMap(item, &link); err != nil {
+				continue // Skip invalid items
+			}
+			link.Normalize()
+			links = append(links, &link)
+		}
+	}
+
+	return links, nil
+}
+
 // GetAvailableInventoryLinks retrieves all available inventory links
 func (db *DynamoDBClient) GetAvailableInventoryLinks(ctx context.Context) ([]*models.InventoryLink, error) {
 	result, err := db.client.Scan(ctx, &dynamodb.ScanInput{
@@ -211,12 +448,37 @@ func (db *DynamoDBClient) GetAvailableInventoryLinks(ctx context.Context) ([]*mo
 		if err != nil {
 			continue // Skip invalid items
 		}
+		link.Normalize()
 		links = append(links, &link)
 	}
 
 	return links, nil
 }
 
+// GetAllInventoryLinks retrieves every inventory link, including distributed ones
+func (db *DynamoDBClient) GetAllInventoryLinks(ctx context.Context) ([]*models.InventoryLink, error) {
+	var links []*models.InventoryLink
+	paginator := dynamodb.NewScanPaginator(db.client, &dynamodb.ScanInput{
+		TableName: aws.String(db.inventoryTable),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan all inventory links: %v", err)
+		}
+		for _, item := range page.Items {
+			var link models.InventoryLink
+			if err := attributevalue.UnmarshalMap(item, &link); err != nil {
+				continue // Skip invalid items
+			}
+			link.Normalize()
+			links = append(links, &link)
+		}
+	}
+
+	return links, nil
+}
+
 // GetAvailableInventoryLinksByQuality retrieves available links by quality
 func (db *DynamoDBClient) GetAvailableInventoryLinksByQuality(ctx context.Context, quality string) ([]*models.InventoryLink, error) {
 	result, err := db.client.Scan(ctx, &dynamodb.ScanInput{
@@ -238,6 +500,7 @@ func (db *DynamoDBClient) GetAvailableInventoryLinksByQuality(ctx context.Contex
 		if err != nil {
 			continue // Skip invalid items
 		}
+		link.Normalize()
 		links = append(links, &link)
 	}
 
@@ -248,26 +511,48 @@ func (db *DynamoDBClient) GetAvailableInventoryLinksByQuality(ctx context.Contex
 // Distribution Operations (Distributions Table)
 // ==========================================
 
-// CreateDistribution creates a new distribution record
+// CreateDistribution creates a new distribution record, refusing to overwrite an existing one
 func (db *DynamoDBClient) CreateDistribution(ctx context.Context, distribution *models.Distribution) error {
-	item, err := attributevalue.MarshalMap(distribution)
+	item, err := marshalDistribution(distribution)
 	if err != nil {
-		return fmt.Errorf("failed to marshal distribution: %v", err)
+		return err
 	}
 
-	// Add distribution_date for date-based queries (YYYY-MM-DD format)
-	distributionDate := distribution.DistributedAt.Format("2006-01-02")
-	item["distribution_date"] = &types.AttributeValueMemberS{Value: distributionDate}
+	err = db.putNew(ctx, db.distributionsTable, "distribution_id", item)
+	if errors.Is(err, ErrAlreadyExists) {
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create distribution: %v", err)
+	}
 
-	_, err = db.client.PutItem(ctx, &dynamodb.PutItemInput{
+	return nil
+}
+
+// GetDistribution retrieves a distribution by ID
+func (db *DynamoDBClient) GetDistribution(ctx context.Context, distributionID string) (*models.Distribution, error) {
+	result, err := db.client.GetItem(ctx, &dynamodb.GetItemInput{
 		TableName: aws.String(db.distributionsTable),
-		Item:      item,
+		Key: map[string]types.AttributeValue{
+			"distribution_id": &types.AttributeValueMemberS{Value: distributionID},
+		},
 	})
 	if err != nil {
-		return fmt.Errorf("failed to create distribution: %v", err)
+		return nil, fmt.Errorf("failed to get distribution: %v", err)
 	}
 
-	return nil
+	if result.Item == nil {
+		return nil, fmt.Errorf("distribution not found")
+	}
+
+	var distribution models.Distribution
+	err = attributevalue.UnmarshalMap(result.Item, &distribution)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal distribution: %v", err)
+	}
+	distribution.NormalizeBonus()
+
+	return &distribution, nil
 }
 
 // GetDistributionsByMember retrieves all distributions for a specific member
@@ -292,6 +577,7 @@ func (db *DynamoDBClient) GetDistributionsByMember(ctx context.Context, memberID
 		if err != nil {
 			continue // Skip invalid items
 		}
+		distribution.NormalizeBonus()
 		distributions = append(distributions, &distribution)
 	}
 
@@ -300,21 +586,23 @@ func (db *DynamoDBClient) GetDistributionsByMember(ctx context.Context, memberID
 
 // GetAllDistributions retrieves all distribution records
 func (db *DynamoDBClient) GetAllDistributions(ctx context.Context) ([]*models.Distribution, error) {
-	result, err := db.client.Scan(ctx, &dynamodb.ScanInput{
+	var distributions []*models.Distribution
+	paginator := dynamodb.NewScanPaginator(db.client, &dynamodb.ScanInput{
 		TableName: aws.String(db.distributionsTable),
 	})
-	if err != nil {
-		return nil, fmt.Errorf("failed to scan all distributions: %v", err)
-	}
-
-	var distributions []*models.Distribution
-	for _, item := range result.Items {
-		var distribution models.Distribution
-		err = attributevalue.UnmarshalMap(item, &distribution)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
 		if err != nil {
-			continue // Skip invalid items
+			return nil, fmt.Errorf("failed to scan all distributions: %v", err)
+		}
+		for _, item := range page.Items {
+			var distribution models.Distribution
+			if err := attributevalue.UnmarshalMap(item, &distribution); err != nil {
+				continue // Skip invalid items
+			}
+			distribution.NormalizeBonus()
+			distributions = append(distributions, &distribution)
 		}
-		distributions = append(distributions, &distribution)
 	}
 
 	return distributions, nil
@@ -324,22 +612,17 @@ func (db *DynamoDBClient) GetAllDistributions(ctx context.Context) ([]*models.Di
 // Distribution List Operations (Lists Table)
 // ==========================================
 
-// CreateDistributionList creates a new distribution list
+// CreateDistributionList creates a new distribution list, refusing to overwrite an existing one
 func (db *DynamoDBClient) CreateDistributionList(ctx context.Context, list *models.DistributionList) error {
-	item, err := attributevalue.MarshalMap(list)
+	item, err := marshalDistributionList(list)
 	if err != nil {
-		return fmt.Errorf("failed to marshal distribution list: %v", err)
+		return err
 	}
 
-	// Add string version of is_active for GSI
-	item["is_active_str"] = &types.AttributeValueMemberS{
-		Value: strconv.FormatBool(list.IsActive),
+	err = db.putNew(ctx, db.listsTable, "list_id", item)
+	if errors.Is(err, ErrAlreadyExists) {
+		return err
 	}
-
-	_, err = db.client.PutItem(ctx, &dynamodb.PutItemInput{
-		TableName: aws.String(db.listsTable),
-		Item:      item,
-	})
 	if err != nil {
 		return fmt.Errorf("failed to create distribution list: %v", err)
 	}
@@ -372,29 +655,71 @@ func (db *DynamoDBClient) GetDistributionList(ctx context.Context, listID string
 	return &list, nil
 }
 
-// UpdateDistributionList updates an existing distribution list
+// UpdateDistributionList saves a distribution list read earlier. It returns
+// ErrListChanged if the list was saved by someone else in the meantime.
 func (db *DynamoDBClient) UpdateDistributionList(ctx context.Context, list *models.DistributionList) error {
-	item, err := attributevalue.MarshalMap(list)
+	put, err := db.listPut(list)
 	if err != nil {
-		return fmt.Errorf("failed to marshal distribution list: %v", err)
-	}
-
-	// Add string version of is_active for GSI
-	item["is_active_str"] = &types.AttributeValueMemberS{
-		Value: strconv.FormatBool(list.IsActive),
+		return err
 	}
 
 	_, err = db.client.PutItem(ctx, &dynamodb.PutItemInput{
-		TableName: aws.String(db.listsTable),
-		Item:      item,
+		TableName:                 put.TableName,
+		Item:                      put.Item,
+		ConditionExpression:       put.ConditionExpression,
+		ExpressionAttributeValues: put.ExpressionAttributeValues,
 	})
+	var conditionFailed *types.ConditionalCheckFailedException
+	if errors.As(err, &conditionFailed) {
+		list.Version--
+		return ErrListChanged
+	}
 	if err != nil {
+		list.Version--
 		return fmt.Errorf("failed to update distribution list: %v", err)
 	}
 
 	return nil
 }
 
+// listPut bumps a list's version and returns a put that only succeeds if the
+// stored list still has the version it was read with. Lists saved before
+// versions were tracked have none.
+func (db *DynamoDBClient) listPut(list *models.DistributionList) (*types.Put, error) {
+	read := list.Version
+	list.Version++
+	item, err := marshalDistributionList(list)
+	if err != nil {
+		list.Version = read
+		return nil, err
+	}
+
+	put := &types.Put{
+		TableName:           aws.String(db.listsTable),
+		Item:                item,
+		ConditionExpression: aws.String("version = :read"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":read": &types.AttributeValueMemberN{Value: strconv.Itoa(read)},
+		},
+	}
+	if read == 0 {
+		put.ConditionExpression = aws.String("attribute_not_exists(version) OR version = :read")
+	}
+	return put, nil
+}
+
+// listConflict reports whether a cancelled transaction failed on the list put
+// at index i, undoing the version bump of any failed transaction
+func listConflict(err error, list *models.DistributionList, i int) bool {
+	if list == nil {
+		return false
+	}
+	list.Version--
+	var canceled *types.TransactionCanceledException
+	return errors.As(err, &canceled) && len(canceled.CancellationReasons) > i &&
+		aws.ToString(canceled.CancellationReasons[i].Code) == "ConditionalCheckFailed"
+}
+
 // GetActiveDistributionLists retrieves all active distribution lists
 func (db *DynamoDBClient) GetActiveDistributionLists(ctx context.Context) ([]*models.DistributionList, error) {
 	result, err := db.client.Scan(ctx, &dynamodb.ScanInput{
@@ -420,3 +745,443 @@ func (db *DynamoDBClient) GetActiveDistributionLists(ctx context.Context) ([]*mo
 
 	return lists, nil
 }
+
+// GetAllDistributionLists retrieves every distribution list, active or not
+func (db *DynamoDBClient) GetAllDistributionLists(ctx context.Context) ([]*models.DistributionList, error) {
+	var lists []*models.DistributionList
+	paginator := dynamodb.NewScanPaginator(db.client, &dynamodb.ScanInput{
+		TableName: aws.String(db.listsTable),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan all distribution lists: %v", err)
+		}
+		for _, item := range page.Items {
+			var list models.DistributionList
+			if err := attributevalue.UnmarshalMap(item, &list); err != nil {
+				continue // Skip invalid items
+			}
+			lists = append(lists, &list)
+		}
+	}
+
+	return lists, nil
+}
+
+// ==========================================
+// Cross-Table Operations
+// ==========================================
+
+// CompleteDistribution atomically marks a link distributed, records the
+// distribution and saves the updated distribution list (if any)
+func (db *DynamoDBClient) CompleteDistribution(ctx context.Context, link *models.InventoryLink, distribution *models.Distribution, list *models.DistributionList) error {
+	// Refuse to hand out a link someone else already distributed or reserved
+	return db.completeDistribution(ctx, link, distribution, list, linkIsAvailable())
+}
+
+// CompleteReservation atomically hands a reserved link to the member it was
+// held for, records the distribution and saves the updated distribution list
+// (if any). It returns ErrLinkUnavailable if the reservation was released or
+// handed over in the meantime.
+func (db *DynamoDBClient) CompleteReservation(ctx context.Context, link *models.InventoryLink, distribution *models.Distribution, list *models.DistributionList) error {
+	return db.completeDistribution(ctx, link, distribution, list, linkIsReservedFor(distribution.MemberID))
+}
+
+func (db *DynamoDBClient) completeDistribution(ctx context.Context, link *models.InventoryLink, distribution *models.Distribution, list *models.DistributionList, condition linkCondition) error {
+	linkItem, err := attributevalue.MarshalMap(link)
+	if err != nil {
+		return fmt.Errorf("failed to marshal inventory link: %v", err)
+	}
+
+	distributionItem, err := marshalDistribution(distribution)
+	if err != nil {
+		return err
+	}
+
+	linkPut := condition.put(db.inventoryTable, linkItem)
+
+	transactItems := []types.TransactWriteItem{
+		{Put: linkPut},
+		{
+			Put: &types.Put{
+				TableName:           aws.String(db.distributionsTable),
+				Item:                distributionItem,
+				ConditionExpression: aws.String("attribute_not_exists(distribution_id)"),
+			},
+		},
+	}
+
+	if list != nil {
+		listPut, err := db.listPut(list)
+		if err != nil {
+			return err
+		}
+		transactItems = append(transactItems, types.TransactWriteItem{Put: listPut})
+	}
+
+	_, err = db.client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: transactItems,
+	})
+	if err != nil {
+		if listConflict(err, list, len(transactItems)-1) {
+			return ErrListChanged
+		}
+		var canceled *types.TransactionCanceledException
+		if errors.As(err, &canceled) && len(canceled.CancellationReasons) > 1 {
+			if aws.ToString(canceled.CancellationReasons[0].Code) == "ConditionalCheckFailed" {
+				return ErrLinkUnavailable
+			}
+			if aws.ToString(canceled.CancellationReasons[1].Code) == "ConditionalCheckFailed" {
+				return ErrAlreadyExists
+			}
+		}
+		return fmt.Errorf("failed to complete distribution: %v", err)
+	}
+
+	return nil
+}
+
+// ReserveLink atomically holds an available link for a draw winner and saves
+// the updated distribution list (if any). It returns ErrLinkUnavailable if the
+// link was distributed or reserved by someone else first.
+func (db *DynamoDBClient) ReserveLink(ctx context.Context, link *models.InventoryLink, list *models.DistributionList) error {
+	return db.saveLinkWithList(ctx, link, list, linkIsAvailable(), ErrLinkUnavailable)
+}
+
+// ReleaseReservation atomically returns a link held for memberID to stock and
+// saves the distribution list the member is restored to (if any). It returns
+// ErrLinkChanged if the reservation was handed over or released first.
+func (db *DynamoDBClient) ReleaseReservation(ctx context.Context, link *models.InventoryLink, memberID string, list *models.DistributionList) error {
+	return db.saveLinkWithList(ctx, link, list, linkIsReservedFor(memberID), ErrLinkChanged)
+}
+
+// saveLinkWithList saves a link under a condition together with a list,
+// returning conflict if the link's condition fails
+func (db *DynamoDBClient) saveLinkWithList(ctx context.Context, link *models.InventoryLink, list *models.DistributionList, condition linkCondition, conflict error) error {
+	linkItem, err := attributevalue.MarshalMap(link)
+	if err != nil {
+		return fmt.Errorf("failed to marshal inventory link: %v", err)
+	}
+
+	linkPut := condition.put(db.inventoryTable, linkItem)
+
+	transactItems := []types.TransactWriteItem{{Put: linkPut}}
+	if list != nil {
+		listPut, err := db.listPut(list)
+		if err != nil {
+			return err
+		}
+		transactItems = append(transactItems, types.TransactWriteItem{Put: listPut})
+	}
+
+	_, err = db.client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: transactItems,
+	})
+	if err != nil {
+		if listConflict(err, list, 1) {
+			return ErrListChanged
+		}
+		var canceled *types.TransactionCanceledException
+		if errors.As(err, &canceled) && len(canceled.CancellationReasons) > 0 &&
+			aws.ToString(canceled.CancellationReasons[0].Code) == "ConditionalCheckFailed" {
+			return conflict
+		}
+		return fmt.Errorf("failed to update inventory link: %v", err)
+	}
+
+	return nil
+}
+
+// linkCondition is the state a link must be in for a transactional write to succeed
+type linkCondition struct {
+	expression string
+	names      map[string]string
+	values     map[string]types.AttributeValue
+}
+
+func linkIsAvailable() linkCondition {
+	return linkCondition{
+		expression: "is_available = :available",
+		values: map[string]types.AttributeValue{
+			":available": &types.AttributeValueMemberS{Value: "true"},
+		},
+	}
+}
+
+func linkIsReservedFor(memberID string) linkCondition {
+	return linkCondition{
+		expression: "#status = :reserved AND reserved_for = :member",
+		names:      map[string]string{"#status": "status"},
+		values: map[string]types.AttributeValue{
+			":reserved": &types.AttributeValueMemberS{Value: models.LinkStatusReserved},
+			":member":   &types.AttributeValueMemberS{Value: memberID},
+		},
+	}
+}
+
+func (c linkCondition) put(table string, item map[string]types.AttributeValue) *types.Put {
+	return &types.Put{
+		TableName:                 aws.String(table),
+		Item:                      item,
+		ConditionExpression:       aws.String(c.expression),
+		ExpressionAttributeNames:  c.names,
+		ExpressionAttributeValues: c.values,
+	}
+}
+
+// FuseLinks atomically creates the link made by a fusion and marks its inputs
+// fused. It returns ErrLinkUnavailable if any input was distributed, reserved
+// or fused by someone else first.
+func (db *DynamoDBClient) FuseLinks(ctx context.Context, inputs []*models.InventoryLink, result *models.InventoryLink) error {
+	resultItem, err := attributevalue.MarshalMap(result)
+	if err != nil {
+		return fmt.Errorf("failed to marshal inventory link: %v", err)
+	}
+
+	transactItems := []types.TransactWriteItem{
+		{
+			Put: &types.Put{
+				TableName:           aws.String(db.inventoryTable),
+				Item:                resultItem,
+				ConditionExpression: aws.String("attribute_not_exists(link_id)"),
+			},
+		},
+	}
+	for _, link := range inputs {
+		linkItem, err := attributevalue.MarshalMap(link)
+		if err != nil {
+			return fmt.Errorf("failed to marshal inventory link: %v", err)
+		}
+		transactItems = append(transactItems, types.TransactWriteItem{Put: linkIsAvailable().put(db.inventoryTable, linkItem)})
+	}
+
+	_, err = db.client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: transactItems,
+	})
+	if err != nil {
+		var canceled *types.TransactionCanceledException
+		if errors.As(err, &canceled) {
+			for n, reason := range canceled.CancellationReasons {
+				if aws.ToString(reason.Code) != "ConditionalCheckFailed" {
+					continue
+				}
+				if n == 0 {
+					return ErrAlreadyExists
+				}
+				return ErrLinkUnavailable
+			}
+		}
+		return fmt.Errorf("failed to fuse links: %v", err)
+	}
+
+	return nil
+}
+
+// ReverseDistribution atomically saves a reversed distribution together with
+// its link, which is either back in stock or handed to another member through
+// replacement, and the distribution list the member is restored to (if any).
+// It returns ErrAlreadyReversed or ErrLinkChanged if another officer got there
+// first.
+func (db *DynamoDBClient) ReverseDistribution(ctx context.Context, reversed *models.Distribution, link *models.InventoryLink, replacement *models.Distribution, list *models.DistributionList) error {
+	reversedItem, err := marshalDistribution(reversed)
+	if err != nil {
+		return err
+	}
+
+	linkItem, err := attributevalue.MarshalMap(link)
+	if err != nil {
+		return fmt.Errorf("failed to marshal inventory link: %v", err)
+	}
+
+	transactItems := []types.TransactWriteItem{
+		{
+			Put: &types.Put{
+				TableName:                aws.String(db.distributionsTable),
+				Item:                     reversedItem,
+				ConditionExpression:      aws.String("attribute_exists(distribution_id) AND (attribute_not_exists(#status) OR #status <> :reversed)"),
+				ExpressionAttributeNames: map[string]string{"#status": "status"},
+				ExpressionAttributeValues: map[string]types.AttributeValue{
+					":reversed": &types.AttributeValueMemberS{Value: models.DistributionReversed},
+				},
+			},
+		},
+		{
+			// The link must still be out with the member being reversed
+			Put: &types.Put{
+				TableName:                aws.String(db.inventoryTable),
+				Item:                     linkItem,
+				ConditionExpression:      aws.String("#status = :distributed OR (attribute_not_exists(#status) AND is_available = :available)"),
+				ExpressionAttributeNames: map[string]string{"#status": "status"},
+				ExpressionAttributeValues: map[string]types.AttributeValue{
+					":distributed": &types.AttributeValueMemberS{Value: models.LinkStatusDistributed},
+					":available":   &types.AttributeValueMemberS{Value: "false"},
+				},
+			},
+		},
+	}
+
+	if replacement != nil {
+		replacementItem, err := marshalDistribution(replacement)
+		if err != nil {
+			return err
+		}
+		transactItems = append(transactItems, types.TransactWriteItem{
+			Put: &types.Put{
+				TableName:           aws.String(db.distributionsTable),
+				Item:                replacementItem,
+				ConditionExpression: aws.String("attribute_not_exists(distribution_id)"),
+			},
+		})
+	}
+
+	if list != nil {
+		listPut, err := db.listPut(list)
+		if err != nil {
+			return err
+		}
+		transactItems = append(transactItems, types.TransactWriteItem{Put: listPut})
+	}
+
+	_, err = db.client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: transactItems,
+	})
+	if err != nil {
+		if listConflict(err, list, len(transactItems)-1) {
+			return ErrListChanged
+		}
+		var canceled *types.TransactionCanceledException
+		if errors.As(err, &canceled) && len(canceled.CancellationReasons) > 1 {
+			if aws.ToString(canceled.CancellationReasons[0].Code) == "ConditionalCheckFailed" {
+				return ErrAlreadyReversed
+			}
+			if aws.ToString(canceled.CancellationReasons[1].Code) == "ConditionalCheckFailed" {
+				return ErrLinkChanged
+			}
+			if len(canceled.CancellationReasons) > 2 && replacement != nil &&
+				aws.ToString(canceled.CancellationReasons[2].Code) == "ConditionalCheckFailed" {
+				return ErrAlreadyExists
+			}
+		}
+		return fmt.Errorf("failed to reverse distribution: %v", err)
+	}
+
+	return nil
+}
+
+// ==========================================
+// Marshalling Helpers
+// ==========================================
+
+// putNew puts an item only if no item with the same key exists, returning
+// ErrAlreadyExists otherwise
+func (db *DynamoDBClient) putNew(ctx context.Context, table, keyName string, item map[string]types.AttributeValue) error {
+	_, err := db.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:                aws.String(table),
+		Item:                     item,
+		ConditionExpression:      aws.String("attribute_not_exists(#key)"),
+		ExpressionAttributeNames: map[string]string{"#key": keyName},
+	})
+	var conditionFailed *types.ConditionalCheckFailedException
+	if errors.As(err, &conditionFailed) {
+		return ErrAlreadyExists
+	}
+	return err
+}
+
+// marshalDistribution marshals a distribution with its date-index attribute
+func marshalDistribution(distribution *models.Distribution) (map[string]types.AttributeValue, error) {
+	item, err := attributevalue.MarshalMap(distribution)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal distribution: %v", err)
+	}
+
+	// Add distribution_date for date-based queries (YYYY-MM-DD format)
+	distributionDate := distribution.DistributedAt.Format("2006-01-02")
+	item["distribution_date"] = &types.AttributeValueMemberS{Value: distributionDate}
+
+	return item, nil
+}
+
+// marshalDistributionList marshals a distribution list with its GSI attribute
+func marshalDistributionList(list *models.DistributionList) (map[string]types.AttributeValue, error) {
+	item, err := attributevalue.MarshalMap(list)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal distribution list: %v", err)
+	}
+
+	// Add string version of is_active for GSI
+	item["is_active_str"] = &types.AttributeValueMemberS{
+		Value: strconv.FormatBool(list.IsActive),
+	}
+
+	return item, nil
+}
+
+// ==========================================
+// Migrations
+// ==========================================
+
+// MigrateBonusValues adds numeric bonus values to inventory links and
+// distributions stored before they were tracked, and normalizes their bonus
+// strings. Records with a non-numeric bonus such as "TBD" are left as-is. It
+// returns the number of links and distributions updated.
+func (db *DynamoDBClient) MigrateBonusValues(ctx context.Context) (int, int, error) {
+	links, err := db.migrateBonusTable(ctx, db.inventoryTable, "link_id")
+	if err != nil {
+		return links, 0, err
+	}
+	distributions, err := db.migrateBonusTable(ctx, db.distributionsTable, "distribution_id")
+	return links, distributions, err
+}
+
+func (db *DynamoDBClient) migrateBonusTable(ctx context.Context, table, keyName string) (int, error) {
+	updated := 0
+	paginator := dynamodb.NewScanPaginator(db.client, &dynamodb.ScanInput{
+		TableName: aws.String(table),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return updated, fmt.Errorf("failed to scan %s: %v", table, err)
+		}
+		for _, item := range page.Items {
+			var record struct {
+				Bonus      string             `dynamodbav:"bonus"`
+				BonusValue *models.BonusValue `dynamodbav:"bonus_value"`
+			}
+			if err := attributevalue.UnmarshalMap(item, &record); err != nil {
+				continue // Skip invalid items
+			}
+			key, ok := item[keyName]
+			if !ok {
+				continue // Skip invalid items
+			}
+
+			bonus, value := models.NormalizeBonus(record.Bonus)
+			if value == nil || (bonus == record.Bonus && record.BonusValue != nil && *record.BonusValue == *value) {
+				continue
+			}
+
+			av, err := attributevalue.Marshal(value)
+			if err != nil {
+				return updated, fmt.Errorf("failed to marshal bonus value: %v", err)
+			}
+			_, err = db.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+				TableName:        aws.String(table),
+				Key:              map[string]types.AttributeValue{keyName: key},
+				UpdateExpression: aws.String("SET bonus = :bonus, bonus_value = :value"),
+				ExpressionAttributeValues: map[string]types.AttributeValue{
+					":bonus": &types.AttributeValueMemberS{Value: bonus},
+					":value": av,
+				},
+			})
+			if err != nil {
+				return updated, fmt.Errorf("failed to update bonus in %s: %v", table, err)
+			}
+			updated++
+		}
+	}
+
+	return updated, nil
+}
@@ -0,0 +1,172 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"flavaflav/internal/models"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// ErrEventsDisabled is returned when no Events table is configured
+var ErrEventsDisabled = errors.New("events table is not configured")
+
+// ErrEventNotFound is returned when recording attendance for an unknown event
+var ErrEventNotFound = errors.New("event not found")
+
+// ==========================================
+// Event Operations (Events Table)
+// ==========================================
+
+// SetEventsTable enables the optional Events table
+func (db *DynamoDBClient) SetEventsTable(eventsTable string) {
+	db.eventsTable = eventsTable
+}
+
+// EventsEnabled returns true if an Events table is configured
+func (db *DynamoDBClient) EventsEnabled() bool {
+	return db.eventsTable != ""
+}
+
+// CreateEvent creates a new event, refusing to overwrite an existing one
+func (db *DynamoDBClient) CreateEvent(ctx context.Context, event *models.Event) error {
+	if !db.EventsEnabled() {
+		return ErrEventsDisabled
+	}
+
+	item, err := attributevalue.MarshalMap(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %v", err)
+	}
+
+	err = db.putNew(ctx, db.eventsTable, "event_id", item)
+	if errors.Is(err, ErrAlreadyExists) {
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create event: %v", err)
+	}
+
+	return nil
+}
+
+// GetEvent retrieves an event by ID
+func (db *DynamoDBClient) GetEvent(ctx context.Context, eventID string) (*models.Event, error) {
+	if !db.EventsEnabled() {
+		return nil, ErrEventsDisabled
+	}
+
+	result, err := db.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(db.eventsTable),
+		Key: map[string]types.AttributeValue{
+			"event_id": &types.AttributeValueMemberS{Value: eventID},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get event: %v", err)
+	}
+
+	if result.Item == nil {
+		return nil, ErrEventNotFound
+	}
+
+	var event models.Event
+	err = attributevalue.UnmarshalMap(result.Item, &event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal event: %v", err)
+	}
+
+	return &event, nil
+}
+
+// GetAllEvents retrieves every event
+func (db *DynamoDBClient) GetAllEvents(ctx context.Context) ([]*models.Event, error) {
+	if !db.EventsEnabled() {
+		return nil, ErrEventsDisabled
+	}
+
+	var events []*models.Event
+	paginator := dynamodb.NewScanPaginator(db.client, &dynamodb.ScanInput{
+		TableName: aws.String(db.eventsTable),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan events: %v", err)
+		}
+		for _, item := range page.Items {
+			var event models.Event
+			if err := attributevalue.UnmarshalMap(item, &event); err != nil {
+				continue // Skip invalid items
+			}
+			events = append(events, &event)
+		}
+	}
+
+	return events, nil
+}
+
+// AddEventAttendees records members as attending an event. Attendees are a
+// string set, so concurrent recordings from the web and the bot both land.
+func (db *DynamoDBClient) AddEventAttendees(ctx context.Context, eventID string, memberIDs []string) error {
+	return db.updateEventAttendees(ctx, "ADD", eventID, memberIDs)
+}
+
+// RemoveEventAttendees removes members from an event's attendees
+func (db *DynamoDBClient) RemoveEventAttendees(ctx context.Context, eventID string, memberIDs []string) error {
+	return db.updateEventAttendees(ctx, "DELETE", eventID, memberIDs)
+}
+
+func (db *DynamoDBClient) updateEventAttendees(ctx context.Context, action, eventID string, memberIDs []string) error {
+	if !db.EventsEnabled() {
+		return ErrEventsDisabled
+	}
+	if len(memberIDs) == 0 {
+		return nil
+	}
+
+	_, err := db.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(db.eventsTable),
+		Key: map[string]types.AttributeValue{
+			"event_id": &types.AttributeValueMemberS{Value: eventID},
+		},
+		UpdateExpression:    aws.String(action + " attendees :ids"),
+		ConditionExpression: aws.String("attribute_exists(event_id)"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":ids": &types.AttributeValueMemberSS{Value: memberIDs},
+		},
+	})
+	var conditionFailed *types.ConditionalCheckFailedException
+	if errors.As(err, &conditionFailed) {
+		return ErrEventNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update event attendees: %v", err)
+	}
+
+	return nil
+}
+
+// DeleteEvent removes an event and its attendance
+func (db *DynamoDBClient) DeleteEvent(ctx context.Context, eventID string) error {
+	if !db.EventsEnabled() {
+		return ErrEventsDisabled
+	}
+
+	_, err := db.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(db.eventsTable),
+		Key: map[string]types.AttributeValue{
+			"event_id": &types.AttributeValueMemberS{Value: eventID},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete event: %v", err)
+	}
+
+	return nil
+}
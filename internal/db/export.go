@@ -0,0 +1,72 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"flavaflav/internal/models"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+)
+
+// ==========================================
+// Export (All Tables)
+// ==========================================
+
+// TableContents holds every record in the four tables
+type TableContents struct {
+	Members       []*models.Member
+	Inventory     []*models.InventoryLink
+	Distributions []*models.Distribution
+	Lists         []*models.DistributionList
+}
+
+// ReadAllTables reads every member, inventory link, distribution and list.
+// Unlike the GetAll functions, which skip items they can't read, it fails on
+// the first unreadable item, so a backup is never silently incomplete.
+func (db *DynamoDBClient) ReadAllTables(ctx context.Context) (*TableContents, error) {
+	var contents TableContents
+	var err error
+	if contents.Members, err = scanStrict[models.Member](ctx, db, db.membersTable, "discord_id", nil); err != nil {
+		return nil, err
+	}
+	if contents.Inventory, err = scanStrict(ctx, db, db.inventoryTable, "link_id", (*models.InventoryLink).Normalize); err != nil {
+		return nil, err
+	}
+	if contents.Distributions, err = scanStrict(ctx, db, db.distributionsTable, "distribution_id", (*models.Distribution).NormalizeBonus); err != nil {
+		return nil, err
+	}
+	if contents.Lists, err = scanStrict[models.DistributionList](ctx, db, db.listsTable, "list_id", nil); err != nil {
+		return nil, err
+	}
+	return &contents, nil
+}
+
+// scanStrict reads every item in a table, failing on any it can't unmarshal
+func scanStrict[T any](ctx context.Context, db *DynamoDBClient, table, keyName string, normalize func(*T)) ([]*T, error) {
+	var records []*T
+	paginator := dynamodb.NewScanPaginator(db.client, &dynamodb.ScanInput{
+		TableName: aws.String(table),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan %s: %v", table, err)
+		}
+		for _, item := range page.Items {
+			record := new(T)
+			if err := attributevalue.UnmarshalMap(item, record); err != nil {
+				var key string
+				attributevalue.Unmarshal(item[keyName], &key)
+				return nil, fmt.Errorf("failed to read %s %s=%q: %v", table, keyName, key, err)
+			}
+			if normalize != nil {
+				normalize(record)
+			}
+			records = append(records, record)
+		}
+	}
+	return records, nil
+}
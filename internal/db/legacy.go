@@ -0,0 +1,348 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"flavaflav/internal/models"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// Kinds of item found in a legacy single table
+const (
+	LegacyMember       = "member"
+	LegacyInventory    = "inventory"
+	LegacyDistribution = "distribution"
+	LegacyList         = "list"
+)
+
+// legacyShape recognises one kind of item by the attributes it must and must
+// not have. Distributions carry link and member IDs too, so the shapes exclude
+// each other's own attributes.
+type legacyShape struct {
+	kind      string
+	required  []string
+	forbidden []string
+}
+
+var legacyShapes = []legacyShape{
+	{kind: LegacyMember, required: []string{"discord_id", "username"}, forbidden: []string{"link_id", "distribution_id", "list_id"}},
+	{kind: LegacyInventory, required: []string{"link_id", "link_type", "quality"}, forbidden: []string{"distribution_id", "list_name"}},
+	{kind: LegacyDistribution, required: []string{"distribution_id", "member_id", "link_id", "distributed_at"}, forbidden: []string{"list_name"}},
+	{kind: LegacyList, required: []string{"list_id", "list_name", "quality"}, forbidden: []string{"distribution_id", "link_id"}},
+}
+
+// ClassifyLegacyItem returns every kind whose shape a legacy item matches;
+// exactly one match means the item can be migrated
+func ClassifyLegacyItem(item map[string]types.AttributeValue) []string {
+	var kinds []string
+	for _, shape := range legacyShapes {
+		if hasAll(item, shape.required) && !hasAny(item, shape.forbidden) {
+			kinds = append(kinds, shape.kind)
+		}
+	}
+	return kinds
+}
+
+func hasAll(item map[string]types.AttributeValue, names []string) bool {
+	for _, name := range names {
+		if _, ok := item[name]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func hasAny(item map[string]types.AttributeValue, names []string) bool {
+	for _, name := range names {
+		if _, ok := item[name]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// LegacyItemNote identifies a legacy item that was not migrated
+type LegacyItemNote struct {
+	Key    map[string]interface{} `json:"key"`
+	Kinds  []string               `json:"kinds,omitempty"` // shapes an ambiguous item matched
+	Reason string                 `json:"reason"`
+}
+
+// LegacyReport counts what a legacy migration did
+type LegacyReport struct {
+	Scanned        int              `json:"scanned"`
+	Migrated       map[string]int   `json:"migrated"`        // by kind
+	AlreadyPresent map[string]int   `json:"already_present"` // by kind; left untouched in the new tables
+	Skipped        []LegacyItemNote `json:"skipped"`         // unrecognised or unreadable items
+	Ambiguous      []LegacyItemNote `json:"ambiguous"`       // items matching more than one kind
+}
+
+// LegacyCheckpoint records how far a legacy migration got so it can resume
+// after the last page it finished
+type LegacyCheckpoint struct {
+	SourceTable string                  `json:"source_table"`
+	LastKey     map[string]KeyAttribute `json:"last_key,omitempty"` // nil before the first page and once done
+	Done        bool                    `json:"done"`
+	Report      LegacyReport            `json:"report"`
+	UpdatedAt   time.Time               `json:"updated_at"`
+}
+
+// KeyAttribute is a key attribute in DynamoDB's JSON form, e.g. {"N": "42"},
+// so number and binary keys are saved exactly and keep their type
+type KeyAttribute struct {
+	S *string `json:"S,omitempty"`
+	N *string `json:"N,omitempty"`
+	B []byte  `json:"B,omitempty"`
+}
+
+// encodeKey converts a scan's LastEvaluatedKey for a checkpoint
+func encodeKey(key map[string]types.AttributeValue) (map[string]KeyAttribute, error) {
+	if key == nil {
+		return nil, nil
+	}
+	encoded := make(map[string]KeyAttribute, len(key))
+	for name, value := range key {
+		switch v := value.(type) {
+		case *types.AttributeValueMemberS:
+			encoded[name] = KeyAttribute{S: aws.String(v.Value)}
+		case *types.AttributeValueMemberN:
+			encoded[name] = KeyAttribute{N: aws.String(v.Value)}
+		case *types.AttributeValueMemberB:
+			encoded[name] = KeyAttribute{B: v.Value}
+		default:
+			return nil, fmt.Errorf("key attribute %s is not a string, number or binary", name)
+		}
+	}
+	return encoded, nil
+}
+
+// decodeKey converts a checkpoint's key back into a scan's ExclusiveStartKey
+func decodeKey(key map[string]KeyAttribute) (map[string]types.AttributeValue, error) {
+	if key == nil {
+		return nil, nil
+	}
+	decoded := make(map[string]types.AttributeValue, len(key))
+	for name, value := range key {
+		switch {
+		case value.S != nil:
+			decoded[name] = &types.AttributeValueMemberS{Value: *value.S}
+		case value.N != nil:
+			decoded[name] = &types.AttributeValueMemberN{Value: *value.N}
+		case value.B != nil:
+			decoded[name] = &types.AttributeValueMemberB{Value: value.B}
+		default:
+			return nil, fmt.Errorf("key attribute %s has no S, N or B value", name)
+		}
+	}
+	return decoded, nil
+}
+
+// NewLegacyCheckpoint starts a migration of a legacy table from the beginning
+func NewLegacyCheckpoint(sourceTable string) *LegacyCheckpoint {
+	return &LegacyCheckpoint{
+		SourceTable: sourceTable,
+		Report: LegacyReport{
+			Migrated:       make(map[string]int),
+			AlreadyPresent: make(map[string]int),
+		},
+	}
+}
+
+// ==========================================
+// Legacy Migration (Single Table)
+// ==========================================
+
+// MigrateLegacyTable copies members, inventory links, distributions and lists
+// from a legacy single table into the four tables, starting after the
+// checkpoint's last key. Each item is classified by shape and written the
+// way the live code writes it, with distribution_date and is_active_str.
+// Items already in the new tables are left alone, so a page that was
+// interrupted can be processed again. afterPage is called with the updated
+// checkpoint after every page. With dryRun, nothing is written.
+func (db *DynamoDBClient) MigrateLegacyTable(ctx context.Context, checkpoint *LegacyCheckpoint, dryRun bool, afterPage func(*LegacyCheckpoint) error) error {
+	source := checkpoint.SourceTable
+	for _, table := range []string{db.membersTable, db.inventoryTable, db.distributionsTable, db.listsTable} {
+		if source == table {
+			return fmt.Errorf("legacy table %s is also one of the new tables", source)
+		}
+	}
+	if checkpoint.Done {
+		return nil
+	}
+
+	keyNames, err := db.tableKeyNames(ctx, source)
+	if err != nil {
+		return err
+	}
+
+	input := &dynamodb.ScanInput{TableName: aws.String(source)}
+	input.ExclusiveStartKey, err = decodeKey(checkpoint.LastKey)
+	if err != nil {
+		return fmt.Errorf("failed to read checkpoint key: %v", err)
+	}
+
+	report := &checkpoint.Report
+	paginator := dynamodb.NewScanPaginator(db.client, input)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to scan %s: %v", source, err)
+		}
+
+		for _, item := range page.Items {
+			report.Scanned++
+			key := legacyKey(item, keyNames)
+
+			kinds := ClassifyLegacyItem(item)
+			switch len(kinds) {
+			case 0:
+				report.Skipped = append(report.Skipped, LegacyItemNote{Key: key, Reason: "does not look like a member, link, distribution or list"})
+				continue
+			case 1:
+			default:
+				report.Ambiguous = append(report.Ambiguous, LegacyItemNote{Key: key, Kinds: kinds, Reason: "matches more than one kind"})
+				continue
+			}
+
+			keyName, converted, err := convertLegacyItem(kinds[0], item)
+			if err != nil {
+				report.Skipped = append(report.Skipped, LegacyItemNote{Key: key, Kinds: kinds, Reason: err.Error()})
+				continue
+			}
+			if dryRun {
+				report.Migrated[kinds[0]]++
+				continue
+			}
+
+			err = db.putNew(ctx, db.legacyTarget(kinds[0]), keyName, converted)
+			if errors.Is(err, ErrAlreadyExists) {
+				report.AlreadyPresent[kinds[0]]++
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("failed to write %s %v: %v", kinds[0], key, err)
+			}
+			report.Migrated[kinds[0]]++
+		}
+
+		checkpoint.LastKey, err = encodeKey(page.LastEvaluatedKey)
+		if err != nil {
+			return fmt.Errorf("failed to record checkpoint key: %v", err)
+		}
+		checkpoint.Done = page.LastEvaluatedKey == nil
+		checkpoint.UpdatedAt = time.Now()
+		if afterPage != nil {
+			if err := afterPage(checkpoint); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// legacyTarget returns the new table for a kind of item
+func (db *DynamoDBClient) legacyTarget(kind string) string {
+	switch kind {
+	case LegacyMember:
+		return db.membersTable
+	case LegacyInventory:
+		return db.inventoryTable
+	case LegacyDistribution:
+		return db.distributionsTable
+	default:
+		return db.listsTable
+	}
+}
+
+// convertLegacyItem reads a legacy item as its model and marshals it the way
+// the live code stores it, returning the name of its key attribute
+func convertLegacyItem(kind string, item map[string]types.AttributeValue) (string, map[string]types.AttributeValue, error) {
+	switch kind {
+	case LegacyMember:
+		var member models.Member
+		if err := attributevalue.UnmarshalMap(item, &member); err != nil {
+			return "", nil, fmt.Errorf("unreadable member: %v", err)
+		}
+		converted, err := attributevalue.MarshalMap(&member)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to marshal member: %v", err)
+		}
+		return "discord_id", converted, nil
+
+	case LegacyInventory:
+		var link models.InventoryLink
+		if err := attributevalue.UnmarshalMap(item, &link); err != nil {
+			return "", nil, fmt.Errorf("unreadable inventory link: %v", err)
+		}
+		if link.IsAvailable == "" {
+			return "", nil, fmt.Errorf("inventory link has no is_available")
+		}
+		link.Normalize()
+		converted, err := attributevalue.MarshalMap(&link)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to marshal inventory link: %v", err)
+		}
+		return "link_id", converted, nil
+
+	case LegacyDistribution:
+		var distribution models.Distribution
+		if err := attributevalue.UnmarshalMap(item, &distribution); err != nil {
+			return "", nil, fmt.Errorf("unreadable distribution: %v", err)
+		}
+		if distribution.BonusValue == nil {
+			distribution.Bonus, distribution.BonusValue = models.NormalizeBonus(distribution.Bonus)
+		}
+		converted, err := marshalDistribution(&distribution)
+		return "distribution_id", converted, err
+
+	default:
+		var list models.DistributionList
+		if err := attributevalue.UnmarshalMap(item, &list); err != nil {
+			return "", nil, fmt.Errorf("unreadable distribution list: %v", err)
+		}
+		converted, err := marshalDistributionList(&list)
+		return "list_id", converted, err
+	}
+}
+
+// tableKeyNames returns the names of a table's key attributes
+func (db *DynamoDBClient) tableKeyNames(ctx context.Context, table string) ([]string, error) {
+	result, err := db.client.DescribeTable(ctx, &dynamodb.DescribeTableInput{TableName: aws.String(table)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe %s: %v", table, err)
+	}
+	var names []string
+	for _, key := range result.Table.KeySchema {
+		names = append(names, aws.ToString(key.AttributeName))
+	}
+	return names, nil
+}
+
+// legacyKey returns an item's key attributes for the report
+func legacyKey(item map[string]types.AttributeValue, keyNames []string) map[string]interface{} {
+	keyItem := make(map[string]types.AttributeValue, len(keyNames))
+	for _, name := range keyNames {
+		if value, ok := item[name]; ok {
+			keyItem[name] = value
+		}
+	}
+	var key map[string]interface{}
+	if err := attributevalue.UnmarshalMap(keyItem, &key); err != nil {
+		names := make([]string, 0, len(item))
+		for name := range item {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return map[string]interface{}{"attributes": names}
+	}
+	return key
+}
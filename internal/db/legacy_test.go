@@ -0,0 +1,113 @@
+package db
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+func TestClassifyLegacyItem(t *testing.T) {
+	s := func(v string) types.AttributeValue { return &types.AttributeValueMemberS{Value: v} }
+
+	tests := []struct {
+		name string
+		item map[string]types.AttributeValue
+		want []string
+	}{
+		{
+			name: "member",
+			item: map[string]types.AttributeValue{"discord_id": s("1"), "username": s("alice"), "rank": s("Sage")},
+			want: []string{LegacyMember},
+		},
+		{
+			name: "inventory link",
+			item: map[string]types.AttributeValue{"link_id": s("link_1"), "link_type": s("Melee Damage"), "quality": s("gold"), "is_available": s("true")},
+			want: []string{LegacyInventory},
+		},
+		{
+			name: "distribution",
+			item: map[string]types.AttributeValue{"distribution_id": s("dist_1"), "member_id": s("1"), "link_id": s("link_1"), "link_type": s("Melee Damage"), "quality": s("gold"), "distributed_at": s("2026-01-01T00:00:00Z")},
+			want: []string{LegacyDistribution},
+		},
+		{
+			name: "list",
+			item: map[string]types.AttributeValue{"list_id": s("list_1"), "list_name": s("Gold Links"), "quality": s("gold")},
+			want: []string{LegacyList},
+		},
+		{
+			name: "distribution missing its date",
+			item: map[string]types.AttributeValue{"distribution_id": s("dist_1"), "member_id": s("1"), "link_id": s("link_1")},
+		},
+		{
+			name: "member with a list",
+			item: map[string]types.AttributeValue{"discord_id": s("1"), "username": s("alice"), "list_id": s("list_1")},
+		},
+		{
+			name: "unrelated item",
+			item: map[string]types.AttributeValue{"pk": s("CONFIG"), "value": s("x")},
+		},
+		{
+			name: "member and link",
+			item: map[string]types.AttributeValue{"discord_id": s("1"), "username": s("alice"), "link_id": s("link_1"), "link_type": s("Melee Damage"), "quality": s("gold")},
+			want: []string{LegacyInventory},
+		},
+		{
+			name: "link and list",
+			item: map[string]types.AttributeValue{"list_id": s("list_1"), "list_name": s("Gold Links"), "quality": s("gold"), "link_type": s("Melee Damage")},
+			want: []string{LegacyList},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ClassifyLegacyItem(tt.item); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ClassifyLegacyItem = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckpointKeyRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		key  map[string]types.AttributeValue
+	}{
+		{"none", nil},
+		{"string", map[string]types.AttributeValue{"pk": &types.AttributeValueMemberS{Value: "member#1"}}},
+		{"large number", map[string]types.AttributeValue{"id": &types.AttributeValueMemberN{Value: "12345678901234567890"}}},
+		{"binary and sort key", map[string]types.AttributeValue{
+			"pk": &types.AttributeValueMemberB{Value: []byte{0, 1, 254, 255}},
+			"sk": &types.AttributeValueMemberN{Value: "-1.5"},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoded, err := encodeKey(tt.key)
+			if err != nil {
+				t.Fatalf("encodeKey: %v", err)
+			}
+			saved, err := json.Marshal(&LegacyCheckpoint{LastKey: encoded})
+			if err != nil {
+				t.Fatalf("json.Marshal: %v", err)
+			}
+			var loaded LegacyCheckpoint
+			if err := json.Unmarshal(saved, &loaded); err != nil {
+				t.Fatalf("json.Unmarshal: %v", err)
+			}
+			decoded, err := decodeKey(loaded.LastKey)
+			if err != nil {
+				t.Fatalf("decodeKey: %v", err)
+			}
+			if !reflect.DeepEqual(decoded, tt.key) {
+				t.Errorf("key = %#v after a checkpoint, want %#v", decoded, tt.key)
+			}
+		})
+	}
+
+	if _, err := encodeKey(map[string]types.AttributeValue{"pk": &types.AttributeValueMemberBOOL{Value: true}}); err == nil {
+		t.Error("encodeKey accepted a boolean key")
+	}
+}
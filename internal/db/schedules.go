@@ -0,0 +1,169 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"flavaflav/internal/models"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// ErrSchedulesDisabled is returned when no Schedules table is configured
+var ErrSchedulesDisabled = errors.New("schedules table is not configured")
+
+// ErrScheduleChanged is returned when a schedule was deleted or run elsewhere
+// since it was read
+var ErrScheduleChanged = errors.New("schedule was changed by someone else")
+
+// ==========================================
+// Schedule Operations (Schedules Table)
+// ==========================================
+
+// SetSchedulesTable enables the optional Schedules table
+func (db *DynamoDBClient) SetSchedulesTable(schedulesTable string) {
+	db.schedulesTable = schedulesTable
+}
+
+// SchedulesEnabled returns true if a Schedules table is configured
+func (db *DynamoDBClient) SchedulesEnabled() bool {
+	return db.schedulesTable != ""
+}
+
+// CreateSchedule creates a new schedule, refusing to overwrite an existing one
+func (db *DynamoDBClient) CreateSchedule(ctx context.Context, schedule *models.Schedule) error {
+	if !db.SchedulesEnabled() {
+		return ErrSchedulesDisabled
+	}
+
+	item, err := attributevalue.MarshalMap(schedule)
+	if err != nil {
+		return fmt.Errorf("failed to marshal schedule: %v", err)
+	}
+
+	err = db.putNew(ctx, db.schedulesTable, "schedule_id", item)
+	if errors.Is(err, ErrAlreadyExists) {
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create schedule: %v", err)
+	}
+
+	return nil
+}
+
+// PutSchedule replaces a schedule read earlier, as long as it still exists
+// and its next run is still readNextRunAt. Otherwise it returns
+// ErrScheduleChanged, so a deleted schedule is not brought back and a run
+// saved by another process is not overwritten.
+func (db *DynamoDBClient) PutSchedule(ctx context.Context, schedule *models.Schedule, readNextRunAt time.Time) error {
+	if !db.SchedulesEnabled() {
+		return ErrSchedulesDisabled
+	}
+
+	item, err := attributevalue.MarshalMap(schedule)
+	if err != nil {
+		return fmt.Errorf("failed to marshal schedule: %v", err)
+	}
+	readValue, err := attributevalue.Marshal(readNextRunAt)
+	if err != nil {
+		return fmt.Errorf("failed to marshal schedule: %v", err)
+	}
+
+	_, err = db.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(db.schedulesTable),
+		Item:                item,
+		ConditionExpression: aws.String("attribute_exists(schedule_id) AND next_run_at = :read"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":read": readValue,
+		},
+	})
+	var conditionFailed *types.ConditionalCheckFailedException
+	if errors.As(err, &conditionFailed) {
+		return ErrScheduleChanged
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save schedule: %v", err)
+	}
+
+	return nil
+}
+
+// GetSchedule retrieves a schedule by ID
+func (db *DynamoDBClient) GetSchedule(ctx context.Context, scheduleID string) (*models.Schedule, error) {
+	if !db.SchedulesEnabled() {
+		return nil, ErrSchedulesDisabled
+	}
+
+	result, err := db.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(db.schedulesTable),
+		Key: map[string]types.AttributeValue{
+			"schedule_id": &types.AttributeValueMemberS{Value: scheduleID},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get schedule: %v", err)
+	}
+
+	if result.Item == nil {
+		return nil, fmt.Errorf("schedule not found")
+	}
+
+	var schedule models.Schedule
+	err = attributevalue.UnmarshalMap(result.Item, &schedule)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal schedule: %v", err)
+	}
+
+	return &schedule, nil
+}
+
+// GetAllSchedules retrieves every schedule
+func (db *DynamoDBClient) GetAllSchedules(ctx context.Context) ([]*models.Schedule, error) {
+	if !db.SchedulesEnabled() {
+		return nil, ErrSchedulesDisabled
+	}
+
+	result, err := db.client.Scan(ctx, &dynamodb.ScanInput{
+		TableName: aws.String(db.schedulesTable),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan schedules: %v", err)
+	}
+
+	var schedules []*models.Schedule
+	for _, item := range result.Items {
+		var schedule models.Schedule
+		err = attributevalue.UnmarshalMap(item, &schedule)
+		if err != nil {
+			continue // Skip invalid items
+		}
+		schedules = append(schedules, &schedule)
+	}
+
+	return schedules, nil
+}
+
+// DeleteSchedule removes a schedule
+func (db *DynamoDBClient) DeleteSchedule(ctx context.Context, scheduleID string) error {
+	if !db.SchedulesEnabled() {
+		return ErrSchedulesDisabled
+	}
+
+	_, err := db.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(db.schedulesTable),
+		Key: map[string]types.AttributeValue{
+			"schedule_id": &types.AttributeValueMemberS{Value: scheduleID},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete schedule: %v", err)
+	}
+
+	return nil
+}
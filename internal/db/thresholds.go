@@ -0,0 +1,99 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"flavaflav/internal/models"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// ErrThresholdsDisabled is returned when no Thresholds table is configured
+var ErrThresholdsDisabled = errors.New("thresholds table is not configured")
+
+// ==========================================
+// Stock Threshold Operations (Thresholds Table)
+// ==========================================
+
+// SetThresholdsTable enables the optional stock Thresholds table
+func (db *DynamoDBClient) SetThresholdsTable(thresholdsTable string) {
+	db.thresholdsTable = thresholdsTable
+}
+
+// ThresholdsEnabled returns true if a Thresholds table is configured
+func (db *DynamoDBClient) ThresholdsEnabled() bool {
+	return db.thresholdsTable != ""
+}
+
+// GetStockThresholds retrieves every stock threshold
+func (db *DynamoDBClient) GetStockThresholds(ctx context.Context) ([]*models.StockThreshold, error) {
+	if !db.ThresholdsEnabled() {
+		return nil, ErrThresholdsDisabled
+	}
+
+	var thresholds []*models.StockThreshold
+	paginator := dynamodb.NewScanPaginator(db.client, &dynamodb.ScanInput{
+		TableName: aws.String(db.thresholdsTable),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan stock thresholds: %v", err)
+		}
+		for _, item := range page.Items {
+			var threshold models.StockThreshold
+			if err := attributevalue.UnmarshalMap(item, &threshold); err != nil {
+				continue // Skip invalid items
+			}
+			thresholds = append(thresholds, &threshold)
+		}
+	}
+
+	return thresholds, nil
+}
+
+// PutStockThreshold creates or replaces a stock threshold
+func (db *DynamoDBClient) PutStockThreshold(ctx context.Context, threshold *models.StockThreshold) error {
+	if !db.ThresholdsEnabled() {
+		return ErrThresholdsDisabled
+	}
+
+	item, err := attributevalue.MarshalMap(threshold)
+	if err != nil {
+		return fmt.Errorf("failed to marshal stock threshold: %v", err)
+	}
+
+	_, err = db.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(db.thresholdsTable),
+		Item:      item,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save stock threshold: %v", err)
+	}
+
+	return nil
+}
+
+// DeleteStockThreshold removes a stock threshold
+func (db *DynamoDBClient) DeleteStockThreshold(ctx context.Context, thresholdID string) error {
+	if !db.ThresholdsEnabled() {
+		return ErrThresholdsDisabled
+	}
+
+	_, err := db.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(db.thresholdsTable),
+		Key: map[string]types.AttributeValue{
+			"threshold_id": &types.AttributeValueMemberS{Value: thresholdID},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete stock threshold: %v", err)
+	}
+
+	return nil
+}
@@ -1,19 +1,30 @@
 package handlers
 
 import (
+	"crypto/subtle"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"math/rand"
 	"net/http"
 	"time"
 
 	"flavaflav/internal/db"
+	"flavaflav/internal/importer"
 	"flavaflav/internal/models"
+	"flavaflav/internal/notify"
+
+	"github.com/bwmarrin/discordgo"
 )
 
 // APIHandlers contains all HTTP handlers for the API
 type APIHandlers struct {
-	db *db.DynamoDBClient
+	db        *db.DynamoDBClient
+	messenger *notify.Messenger
+	webhooks  *notify.WebhookNotifier
+
+	donorBonus models.DonorBonus
+	attendance models.AttendancePolicy
+	apiKey     string
 }
 
 // NewAPIHandlers creates a new API handlers instance
@@ -23,6 +34,33 @@ func NewAPIHandlers(database *db.DynamoDBClient) *APIHandlers {
 	}
 }
 
+// SetMessenger enables direct-message notifications for API actions
+func (h *APIHandlers) SetMessenger(messenger *notify.Messenger) {
+	h.messenger = messenger
+}
+
+// SetWebhooks enables channel announcements through Discord webhooks
+func (h *APIHandlers) SetWebhooks(webhooks *notify.WebhookNotifier) {
+	h.webhooks = webhooks
+}
+
+// SetDonorBonus gives members who donated links extra entries in draws
+func (h *APIHandlers) SetDonorBonus(bonus models.DonorBonus) {
+	h.donorBonus = bonus
+}
+
+// SetAttendancePolicy makes event attendance count towards eligibility and draws
+func (h *APIHandlers) SetAttendancePolicy(policy models.AttendancePolicy) {
+	h.attendance = policy
+}
+
+// SetAPIKey requires the key in the X-Api-Key header for Maester endpoints,
+// and rejects any request presenting a different key. With no key set, the
+// header is ignored.
+func (h *APIHandlers) SetAPIKey(key string) {
+	h.apiKey = key
+}
+
 // Response structures
 type APIResponse struct {
 	Success bool        `json:"success"`
@@ -36,10 +74,17 @@ type CreateMemberRequest struct {
 	JoinDate  time.Time `json:"join_date"`
 }
 
+type UpdateMemberRequest struct {
+	DiscordID string    `json:"discord_id"`
+	Username  string    `json:"username"`  // unchanged if empty
+	JoinDate  time.Time `json:"join_date"` // unchanged if zero
+}
+
 type AddInventoryRequest struct {
 	LinkType string `json:"link_type"`
 	Quality  string `json:"quality"`
 	Count    int    `json:"count"`
+	DonorID  string `json:"donor_id"` // optional member who donated the links
 }
 
 type CreateDistributionListRequest struct {
@@ -48,8 +93,15 @@ type CreateDistributionListRequest struct {
 }
 
 type DistributeRequest struct {
+	ListID     string `json:"list_id"`
+	LinkID     string `json:"link_id"`
+	Character  string `json:"character"`   // defaults to the member's primary character
+	MatchBuild bool   `json:"match_build"` // refuse links the member's build tags don't suit
+}
+
+type RerollRequest struct {
 	ListID string `json:"list_id"`
-	LinkID string `json:"link_id"`
+	Reason string `json:"reason"`
 }
 
 // Member endpoints
@@ -123,6 +175,10 @@ func (h *APIHandlers) CreateMember(w http.ResponseWriter, r *http.Request) {
 	member := models.NewMember(req.DiscordID, req.Username, req.JoinDate, "web-admin")
 
 	err := h.db.CreateMember(r.Context(), member)
+	if errors.Is(err, db.ErrAlreadyExists) {
+		h.sendErrorResponse(w, "Member already exists", http.StatusConflict)
+		return
+	}
 	if err != nil {
 		h.sendErrorResponse(w, "Failed to create member", http.StatusInternalServerError)
 		return
@@ -160,6 +216,55 @@ func (h *APIHandlers) PromoteMember(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	h.notifyMember(member, models.NotifyPromotions, notify.PromotionEmbed(member))
+
+	h.sendSuccessResponse(w, member)
+}
+
+// UpdateMember changes a member's username or join date and recalculates
+// their rank and eligibility (Maester only)
+func (h *APIHandlers) UpdateMember(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.sendErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// TODO: Add authentication check for Maester role
+
+	var req UpdateMemberRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendErrorResponse(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.DiscordID == "" || (req.Username == "" && req.JoinDate.IsZero()) {
+		h.sendErrorResponse(w, "discord_id and a username or join_date are required", http.StatusBadRequest)
+		return
+	}
+
+	member, err := h.db.GetMember(r.Context(), req.DiscordID)
+	if err != nil {
+		h.sendErrorResponse(w, "Member not found", http.StatusNotFound)
+		return
+	}
+
+	if req.Username != "" {
+		member.Username = req.Username
+	}
+	if !req.JoinDate.IsZero() {
+		member.JoinDate = req.JoinDate
+	}
+	change := member.UpdateRankAndEligibility()
+
+	if err := h.db.UpdateMember(r.Context(), member); err != nil {
+		h.sendErrorResponse(w, "Failed to update member", http.StatusInternalServerError)
+		return
+	}
+
+	if !member.IsOfficer && (change.NewlySilver || change.NewlyGold) {
+		h.notifyMember(member, models.NotifyEligibility, notify.EligibilityEmbed(member, change))
+	}
+
 	h.sendSuccessResponse(w, member)
 }
 
@@ -173,9 +278,18 @@ func (h *APIHandlers) GetInventory(w http.ResponseWriter, r *http.Request) {
 	}
 
 	quality := r.URL.Query().Get("quality")
+	catalog, err := h.db.GetLinkCatalog(r.Context())
+	if err != nil {
+		h.sendErrorResponse(w, "Failed to get link catalog", http.StatusInternalServerError)
+		return
+	}
+	category, err := categoryParam(r, catalog)
+	if err != nil {
+		h.sendErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 
 	var links []*models.InventoryLink
-	var err error
 
 	if quality != "" {
 		links, err = h.db.GetAvailableInventoryLinksByQuality(r.Context(), quality)
@@ -188,32 +302,55 @@ func (h *APIHandlers) GetInventory(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	models.NormalizeCategories(links, catalog)
+	if category != "" {
+		links = models.FilterLinksByCategory(links, category)
+	}
+
+	if r.URL.Query().Get("sort") == "bonus" {
+		models.SortLinksByBonus(links)
+	}
+
 	h.sendSuccessResponse(w, links)
 }
 
-// GetInventorySummary returns inventory counts by type and quality
+// GetInventorySummary returns inventory counts by type (or ?group_by=category) and quality
 func (h *APIHandlers) GetInventorySummary(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		h.sendErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
+	groupBy := r.URL.Query().Get("group_by")
+	if groupBy != "" && groupBy != models.GroupByLinkType && groupBy != models.GroupByCategory {
+		h.sendErrorResponse(w, "group_by must be 'link_type' or 'category'", http.StatusBadRequest)
+		return
+	}
+
+	catalog, err := h.db.GetLinkCatalog(r.Context())
+	if err != nil {
+		h.sendErrorResponse(w, "Failed to get link catalog", http.StatusInternalServerError)
+		return
+	}
+	category, err := categoryParam(r, catalog)
+	if err != nil {
+		h.sendErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	links, err := h.db.GetAvailableInventoryLinks(r.Context())
 	if err != nil {
 		h.sendErrorResponse(w, "Failed to get inventory", http.StatusInternalServerError)
 		return
 	}
 
-	// Group by link type and quality
-	summary := make(map[string]map[string]int)
-	for _, link := range links {
-		if summary[link.LinkType] == nil {
-			summary[link.LinkType] = make(map[string]int)
-		}
-		summary[link.LinkType][link.Quality]++
+	models.NormalizeCategories(links, catalog)
+	if category != "" {
+		links = models.FilterLinksByCategory(links, category)
 	}
 
-	h.sendSuccessResponse(w, summary)
+	// Group by link type (or category) and quality
+	h.sendSuccessResponse(w, models.SummarizeInventory(links, groupBy))
 }
 
 // AddInventory adds new inventory links (Maester only)
@@ -236,22 +373,46 @@ func (h *APIHandlers) AddInventory(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Get bonus and category for this link type
-	bonus := models.GetLinkBonus(req.LinkType, req.Quality)
-	category := models.GetLinkCategory(req.LinkType)
+	if req.Count > importer.MaxLinks {
+		h.sendErrorResponse(w, fmt.Sprintf("count cannot exceed %d", importer.MaxLinks), http.StatusBadRequest)
+		return
+	}
 
-	var createdLinks []*models.InventoryLink
-	for i := 0; i < req.Count; i++ {
-		link := models.NewInventoryLink(req.LinkType, req.Quality, category, bonus, "web-admin")
-		err := h.db.CreateInventoryLink(r.Context(), link)
-		if err != nil {
-			// Log the actual error for debugging
-			fmt.Printf("ERROR creating inventory link: %v\n", err)
-			h.sendErrorResponse(w, fmt.Sprintf("Failed to create inventory link %d: %v", i+1, err), http.StatusInternalServerError)
-			return
+	// Get bonus and category for this link type as the catalog defines it today
+	catalog, err := h.db.GetLinkCatalog(r.Context())
+	if err != nil {
+		h.sendErrorResponse(w, "Failed to get link catalog", http.StatusInternalServerError)
+		return
+	}
+	category, bonus, err := catalog.InventoryDetails(req.LinkType, req.Quality, time.Now())
+	if err != nil {
+		h.sendErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	donor, err := h.findDonor(r, req.DonorID)
+	if err != nil {
+		h.sendErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	createdLinks := make([]*models.InventoryLink, req.Count)
+	for i := range createdLinks {
+		createdLinks[i] = models.NewInventoryLink(req.LinkType, req.Quality, category, bonus, "web-admin")
+		if donor != nil {
+			createdLinks[i].SetDonor(donor.DiscordID, donor.Username)
 		}
-		createdLinks = append(createdLinks, link)
 	}
+	if err := h.db.BatchCreateInventoryLinks(r.Context(), createdLinks); err != nil {
+		// Log the actual error for debugging
+		fmt.Printf("ERROR creating inventory links: %v\n", err)
+		h.sendErrorResponse(w, fmt.Sprintf("Failed to create inventory links: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	h.announce(r, notify.EventInventory, notify.InventoryAddedEmbed(createdLinks, "web-admin"))
+
+	h.checkStock(r)
 
 	h.sendSuccessResponse(w, map[string]interface{}{
 		"message": fmt.Sprintf("Added %d %s %s links", req.Count, req.Quality, req.LinkType),
@@ -261,7 +422,8 @@ func (h *APIHandlers) AddInventory(w http.ResponseWriter, r *http.Request) {
 
 // Distribution endpoints
 
-// GetEligibleMembers returns members eligible for a specific quality
+// GetEligibleMembers returns members eligible for a specific quality,
+// optionally only those whose build tags suit a link_type
 func (h *APIHandlers) GetEligibleMembers(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		h.sendErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed)
@@ -280,12 +442,9 @@ func (h *APIHandlers) GetEligibleMembers(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	var eligibleMembers []*models.Member
-	for _, member := range members {
-		member.UpdateRankAndEligibility()
-		if (quality == "silver" && member.SilverEligible) || (quality == "gold" && member.GoldEligible) {
-			eligibleMembers = append(eligibleMembers, member)
-		}
+	eligibleMembers := h.filterByAttendance(r, models.FilterEligibleMembers(members, quality))
+	if linkType := r.URL.Query().Get("link_type"); linkType != "" {
+		eligibleMembers = models.FilterMembersForLinkType(eligibleMembers, linkType)
 	}
 
 	h.sendSuccessResponse(w, eligibleMembers)
@@ -319,11 +478,8 @@ func (h *APIHandlers) CreateDistributionList(w http.ResponseWriter, r *http.Requ
 	}
 
 	var eligibleMemberIDs []string
-	for _, member := range members {
-		member.UpdateRankAndEligibility()
-		if (req.Quality == "silver" && member.SilverEligible) || (req.Quality == "gold" && member.GoldEligible) {
-			eligibleMemberIDs = append(eligibleMemberIDs, member.DiscordID)
-		}
+	for _, member := range h.filterByAttendance(r, models.FilterEligibleMembers(members, req.Quality)) {
+		eligibleMemberIDs = append(eligibleMemberIDs, member.DiscordID)
 	}
 
 	list := models.NewDistributionList(req.ListName, req.Quality, eligibleMemberIDs, "web-admin")
@@ -353,7 +509,9 @@ func (h *APIHandlers) GetDistributionLists(w http.ResponseWriter, r *http.Reques
 	h.sendSuccessResponse(w, lists)
 }
 
-// PickWinner randomly selects a winner from a distribution list (Maester only)
+// PickWinner randomly selects a winner from a distribution list. With
+// match_build=true, only members whose build tags suit link_type are drawn
+// (Maester only).
 func (h *APIHandlers) PickWinner(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		h.sendErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed)
@@ -379,22 +537,119 @@ func (h *APIHandlers) PickWinner(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Pick random member
-	rand.Seed(time.Now().UnixNano())
-	randomIndex := rand.Intn(len(list.EligibleMembers))
-	winnerID := list.EligibleMembers[randomIndex]
+	linkType := r.URL.Query().Get("link_type")
+	matchBuild := r.URL.Query().Get("match_build") == "true"
+	if matchBuild && linkType == "" {
+		h.sendErrorResponse(w, "link_type is required to match builds", http.StatusBadRequest)
+		return
+	}
+
+	h.drawWinner(w, r, list, linkType, matchBuild)
+}
+
+// RerollWinner records the pending winner as absent and draws another (Maester only)
+func (h *APIHandlers) RerollWinner(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.sendErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// TODO: Add authentication check for Maester role
+
+	var req RerollRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendErrorResponse(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.ListID == "" || req.Reason == "" {
+		h.sendErrorResponse(w, "list_id and reason are required", http.StatusBadRequest)
+		return
+	}
+
+	list, err := h.db.GetDistributionList(r.Context(), req.ListID)
+	if err != nil {
+		h.sendErrorResponse(w, "Distribution list not found", http.StatusNotFound)
+		return
+	}
+
+	pending := list.PendingDraw()
+	if pending == nil {
+		h.sendErrorResponse(w, "No pending draw to re-roll", http.StatusBadRequest)
+		return
+	}
+	linkType, matchBuild := pending.LinkType, pending.MatchBuild
+	list.ResolvePendingDraw(pending.MemberID, models.DrawRerolled, req.Reason, "web-admin")
+
+	h.drawWinner(w, r, list, linkType, matchBuild)
+}
+
+// drawWinner picks a winner from the list, skipping absent members and, when
+// matching builds, members the link type doesn't suit, and records the draw.
+// Members who are no longer registered are taken off the list.
+func (h *APIHandlers) drawWinner(w http.ResponseWriter, r *http.Request, list *models.DistributionList, linkType string, matchBuild bool) {
+	exclude := list.AbsentMembers()
+	if matchBuild {
+		members, err := h.db.GetAllMembers(r.Context())
+		if err != nil {
+			h.sendErrorResponse(w, "Failed to get members", http.StatusInternalServerError)
+			return
+		}
+		exclude = append(exclude, models.UnsuitedMembers(members, linkType)...)
+	}
+
+	entries := h.drawEntries(r)
+	var winner *models.Member
+	for winner == nil {
+		winnerID, ok := list.PickWeightedMember(exclude, entries)
+		if !ok {
+			if matchBuild {
+				h.sendErrorResponse(w, fmt.Sprintf("No eligible members left whose build suits %s", linkType), http.StatusBadRequest)
+				return
+			}
+			h.sendErrorResponse(w, "No eligible members left to draw", http.StatusBadRequest)
+			return
+		}
+
+		// Get winner details
+		member, err := h.db.GetMember(r.Context(), winnerID)
+		if errors.Is(err, db.ErrMemberNotFound) {
+			list.RemoveMember(winnerID)
+			continue
+		}
+		if err != nil {
+			h.sendErrorResponse(w, "Failed to get winner", http.StatusInternalServerError)
+			return
+		}
+		winner = member
+	}
 
-	// Get winner details
-	winner, err := h.db.GetMember(r.Context(), winnerID)
+	list.RecordDraw(winner.DiscordID, winner.Username, linkType, "web-admin").MatchBuild = matchBuild
+	err := h.db.UpdateDistributionList(r.Context(), list)
+	if errors.Is(err, db.ErrListChanged) {
+		h.sendErrorResponse(w, "Distribution list was changed by someone else; reload and try again", http.StatusConflict)
+		return
+	}
 	if err != nil {
-		h.sendErrorResponse(w, "Winner member not found", http.StatusNotFound)
+		h.sendErrorResponse(w, "Failed to record draw", http.StatusInternalServerError)
 		return
 	}
 
+	h.notifyMember(winner, models.NotifyWins, notify.WinEmbed(list))
+	h.announce(r, notify.EventWinners, notify.WinnerAnnouncementEmbed(list, winner))
+
+	winnerIndex := 0
+	for i, id := range list.EligibleMembers {
+		if id == winner.DiscordID {
+			winnerIndex = i
+			break
+		}
+	}
+
 	h.sendSuccessResponse(w, map[string]interface{}{
 		"winner":       winner,
 		"list":         list,
-		"winner_index": randomIndex,
+		"winner_index": winnerIndex,
 	})
 }
 
@@ -437,14 +692,20 @@ func (h *APIHandlers) DistributeLink(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Mark link as distributed
-	link.MarkDistributed()
-	err = h.db.UpdateInventoryLink(r.Context(), link)
+	if req.MatchBuild && !member.SuitsLinkType(link.LinkType) {
+		h.sendErrorResponse(w, fmt.Sprintf("%s doesn't suit %s's build", link.LinkType, member.Username), http.StatusBadRequest)
+		return
+	}
+
+	character, err := member.TargetCharacter(req.Character)
 	if err != nil {
-		h.sendErrorResponse(w, "Failed to update link", http.StatusInternalServerError)
+		h.sendErrorResponse(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
+	// Mark link as distributed
+	link.MarkDistributed()
+
 	// Create distribution record
 	distribution := models.NewDistribution(
 		member.DiscordID,
@@ -456,22 +717,40 @@ func (h *APIHandlers) DistributeLink(w http.ResponseWriter, r *http.Request) {
 		"web",
 		"web-admin",
 	)
-
-	err = h.db.CreateDistribution(r.Context(), distribution)
-	if err != nil {
-		h.sendErrorResponse(w, "Failed to create distribution record", http.StatusInternalServerError)
-		return
-	}
+	distribution.Character = character
 
 	// Remove member from distribution list if provided
+	var list *models.DistributionList
 	if req.ListID != "" {
-		list, err := h.db.GetDistributionList(r.Context(), req.ListID)
+		list, err = h.db.GetDistributionList(r.Context(), req.ListID)
 		if err == nil {
 			list.RemoveMember(memberID)
-			h.db.UpdateDistributionList(r.Context(), list)
+			list.ResolvePendingDraw(memberID, models.DrawConfirmed, "", "web-admin")
+			distribution.ListID = list.ListID
+		} else {
+			list = nil
 		}
 	}
 
+	err = h.db.CompleteDistribution(r.Context(), link, distribution, list)
+	if errors.Is(err, db.ErrLinkUnavailable) {
+		h.sendErrorResponse(w, "Link is not available", http.StatusConflict)
+		return
+	}
+	if errors.Is(err, db.ErrListChanged) {
+		h.sendErrorResponse(w, "Distribution list was changed by someone else; reload and try again", http.StatusConflict)
+		return
+	}
+	if err != nil {
+		h.sendErrorResponse(w, "Failed to create distribution record", http.StatusInternalServerError)
+		return
+	}
+
+	h.notifyMember(member, models.NotifyDistributions, notify.DistributionEmbed(distribution))
+	h.announce(r, notify.EventDistributions, notify.DistributionAnnouncementEmbed(distribution))
+
+	h.checkStock(r)
+
 	h.sendSuccessResponse(w, map[string]interface{}{
 		"distribution": distribution,
 		"member":       member,
@@ -534,6 +813,20 @@ func (h *APIHandlers) HealthCheck(w http.ResponseWriter, r *http.Request) {
 
 // Helper methods
 
+// announce posts to the event's webhooks if configured; failures never fail the request
+func (h *APIHandlers) announce(r *http.Request, event string, embed *discordgo.MessageEmbed) {
+	if err := h.webhooks.Notify(r.Context(), event, embed); err != nil {
+		fmt.Printf("ERROR posting %s webhook: %v\n", event, err)
+	}
+}
+
+// notifyMember sends a DM if a messenger is configured; failures never fail the request
+func (h *APIHandlers) notifyMember(member *models.Member, kind string, embed *discordgo.MessageEmbed) {
+	if err := h.messenger.NotifyMember(member, kind, embed); err != nil {
+		fmt.Printf("ERROR sending %s notification: %v\n", kind, err)
+	}
+}
+
 func (h *APIHandlers) sendSuccessResponse(w http.ResponseWriter, data interface{}) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
@@ -558,7 +851,7 @@ func (h *APIHandlers) EnableCORS(next http.HandlerFunc) http.HandlerFunc {
 		// Set CORS headers for all responses
 		w.Header().Set("Access-Control-Allow-Origin", "*")
 		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Requested-With")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Requested-With, X-Api-Key")
 		w.Header().Set("Access-Control-Max-Age", "3600")
 
 		// Handle preflight OPTIONS request
@@ -567,10 +860,32 @@ func (h *APIHandlers) EnableCORS(next http.HandlerFunc) http.HandlerFunc {
 			return
 		}
 
+		if h.apiKey != "" && r.Header.Get("X-Api-Key") != "" && !h.validAPIKey(r) {
+			h.sendErrorResponse(w, "Invalid API key", http.StatusUnauthorized)
+			return
+		}
+
+		next(w, r)
+	}
+}
+
+// RequireAPIKey guards a Maester endpoint: once an API key is set, requests
+// without the matching X-Api-Key header are rejected
+func (h *APIHandlers) RequireAPIKey(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if h.apiKey != "" && !h.validAPIKey(r) {
+			h.sendErrorResponse(w, "Missing or invalid API key", http.StatusUnauthorized)
+			return
+		}
 		next(w, r)
 	}
 }
 
+// validAPIKey reports whether the request presents the configured API key
+func (h *APIHandlers) validAPIKey(r *http.Request) bool {
+	return subtle.ConstantTimeCompare([]byte(r.Header.Get("X-Api-Key")), []byte(h.apiKey)) == 1
+}
+
 // SetupRoutes sets up all API routes
 func (h *APIHandlers) SetupRoutes() *http.ServeMux {
 	mux := http.NewServeMux()
@@ -584,23 +899,80 @@ func (h *APIHandlers) SetupRoutes() *http.ServeMux {
 		// Member endpoints
 		mux.HandleFunc(stage+"/api/members", h.EnableCORS(h.GetMembers))
 		mux.HandleFunc(stage+"/api/member", h.EnableCORS(h.GetMember))
-		mux.HandleFunc(stage+"/api/member/create", h.EnableCORS(h.CreateMember))
-		mux.HandleFunc(stage+"/api/member/promote", h.EnableCORS(h.PromoteMember))
+		mux.HandleFunc(stage+"/api/member/create", h.EnableCORS(h.RequireAPIKey(h.CreateMember)))
+		mux.HandleFunc(stage+"/api/member/update", h.EnableCORS(h.RequireAPIKey(h.UpdateMember)))
+		mux.HandleFunc(stage+"/api/member/promote", h.EnableCORS(h.RequireAPIKey(h.PromoteMember)))
 		mux.HandleFunc(stage+"/api/member/history", h.EnableCORS(h.GetMemberHistory))
+		mux.HandleFunc(stage+"/api/member/characters", h.EnableCORS(h.RequireAPIKey(h.SetCharacters)))
+		mux.HandleFunc(stage+"/api/member/build-tags", h.EnableCORS(h.RequireAPIKey(h.SetBuildTags)))
 
 		// Inventory endpoints
 		mux.HandleFunc(stage+"/api/inventory", h.EnableCORS(h.GetInventory))
 		mux.HandleFunc(stage+"/api/inventory/summary", h.EnableCORS(h.GetInventorySummary))
-		mux.HandleFunc(stage+"/api/inventory/add", h.EnableCORS(h.AddInventory))
+		mux.HandleFunc(stage+"/api/inventory/add", h.EnableCORS(h.RequireAPIKey(h.AddInventory)))
+		mux.HandleFunc(stage+"/api/inventory/import", h.EnableCORS(h.RequireAPIKey(h.ImportInventory)))
+		mux.HandleFunc(stage+"/api/inventory/link", h.EnableCORS(h.GetInventoryLink))
+		mux.HandleFunc(stage+"/api/inventory/retired", h.EnableCORS(h.GetRetiredInventory))
+		mux.HandleFunc(stage+"/api/inventory/reserved", h.EnableCORS(h.GetReservedInventory))
+		mux.HandleFunc(stage+"/api/inventory/release", h.EnableCORS(h.RequireAPIKey(h.ReleaseReservation)))
+		mux.HandleFunc(stage+"/api/inventory/alerts", h.EnableCORS(h.GetInventoryAlerts))
+		mux.HandleFunc(stage+"/api/inventory/thresholds", h.EnableCORS(h.RequireAPIKey(h.SetStockThreshold)))
+		mux.HandleFunc(stage+"/api/inventory/notes", h.EnableCORS(h.RequireAPIKey(h.EditLinkNotes)))
+		mux.HandleFunc(stage+"/api/inventory/retire", h.EnableCORS(h.RequireAPIKey(h.RetireLinks)))
+		mux.HandleFunc(stage+"/api/inventory/restore", h.EnableCORS(h.RequireAPIKey(h.RestoreLinks)))
+		mux.HandleFunc(stage+"/api/inventory/fuse", h.EnableCORS(h.RequireAPIKey(h.FuseLinks)))
+		mux.HandleFunc(stage+"/api/inventory/ledger", h.EnableCORS(h.GetInventoryLedger))
+
+		// Link catalog endpoints
+		mux.HandleFunc(stage+"/api/catalog", h.EnableCORS(h.GetCatalog))
+		mux.HandleFunc(stage+"/api/catalog/categories", h.EnableCORS(h.GetCatalogCategories))
+		mux.HandleFunc(stage+"/api/catalog/build-tags", h.EnableCORS(h.GetBuildTags))
+		mux.HandleFunc(stage+"/api/catalog/history", h.EnableCORS(h.GetCatalogHistory))
+		mux.HandleFunc(stage+"/api/catalog/bonus", h.EnableCORS(h.GetCatalogBonus))
+		mux.HandleFunc(stage+"/api/catalog/create", h.EnableCORS(h.RequireAPIKey(h.CreateCatalogEntry)))
+		mux.HandleFunc(stage+"/api/catalog/update", h.EnableCORS(h.RequireAPIKey(h.UpdateCatalogEntry)))
+		mux.HandleFunc(stage+"/api/catalog/delete", h.EnableCORS(h.RequireAPIKey(h.DeleteCatalogEntry)))
+		mux.HandleFunc(stage+"/api/catalog/seed", h.EnableCORS(h.RequireAPIKey(h.SeedCatalog)))
 
 		// Distribution endpoints
 		mux.HandleFunc(stage+"/api/distribution/eligible", h.EnableCORS(h.GetEligibleMembers))
 		mux.HandleFunc(stage+"/api/distribution/lists", h.EnableCORS(h.GetDistributionLists))
-		mux.HandleFunc(stage+"/api/distribution/create-list", h.EnableCORS(h.CreateDistributionList))
-		mux.HandleFunc(stage+"/api/distribution/pick-winner", h.EnableCORS(h.PickWinner))
-		mux.HandleFunc(stage+"/api/distribution/distribute", h.EnableCORS(h.DistributeLink))
+		mux.HandleFunc(stage+"/api/distribution/create-list", h.EnableCORS(h.RequireAPIKey(h.CreateDistributionList)))
+		mux.HandleFunc(stage+"/api/distribution/pick-winner", h.EnableCORS(h.RequireAPIKey(h.PickWinner)))
+		mux.HandleFunc(stage+"/api/distribution/reroll", h.EnableCORS(h.RequireAPIKey(h.RerollWinner)))
+		mux.HandleFunc(stage+"/api/distribution/distribute", h.EnableCORS(h.RequireAPIKey(h.DistributeLink)))
+		mux.HandleFunc(stage+"/api/distribution/reverse", h.EnableCORS(h.RequireAPIKey(h.ReverseDistribution)))
+		mux.HandleFunc(stage+"/api/distribution/reserve", h.EnableCORS(h.RequireAPIKey(h.ReserveLink)))
+		mux.HandleFunc(stage+"/api/distribution/handover", h.EnableCORS(h.RequireAPIKey(h.HandOverReservation)))
 		mux.HandleFunc(stage+"/api/distribution/history", h.EnableCORS(h.GetAllHistory))
 
+		// Stats endpoints
+		mux.HandleFunc(stage+"/api/stats", h.EnableCORS(h.GetStats))
+		mux.HandleFunc(stage+"/api/stats/leaderboard", h.EnableCORS(h.GetLeaderboard))
+		mux.HandleFunc(stage+"/api/stats/fairness", h.EnableCORS(h.GetFairness))
+
+		// Report endpoints
+		mux.HandleFunc(stage+"/api/reports", h.EnableCORS(h.RequireAPIKey(h.GetReport)))
+
+		// Donation endpoints
+		mux.HandleFunc(stage+"/api/donations", h.EnableCORS(h.GetDonations))
+		mux.HandleFunc(stage+"/api/donations/member", h.EnableCORS(h.GetMemberDonations))
+
+		// Event endpoints
+		mux.HandleFunc(stage+"/api/events", h.EnableCORS(h.GetEvents))
+		mux.HandleFunc(stage+"/api/events/create", h.EnableCORS(h.RequireAPIKey(h.CreateEvent)))
+		mux.HandleFunc(stage+"/api/events/delete", h.EnableCORS(h.RequireAPIKey(h.DeleteEvent)))
+		mux.HandleFunc(stage+"/api/events/attendance", h.EnableCORS(h.GetAttendance))
+		mux.HandleFunc(stage+"/api/events/record", h.EnableCORS(h.RequireAPIKey(h.RecordAttendance)))
+
+		// Schedule endpoints
+		mux.HandleFunc(stage+"/api/schedules", h.EnableCORS(h.GetSchedules))
+		mux.HandleFunc(stage+"/api/schedules/create", h.EnableCORS(h.RequireAPIKey(h.CreateSchedule)))
+		mux.HandleFunc(stage+"/api/schedules/delete", h.EnableCORS(h.RequireAPIKey(h.DeleteSchedule)))
+
+		// Maintenance endpoints
+		mux.HandleFunc(stage+"/api/maintenance/migrate-bonuses", h.EnableCORS(h.RequireAPIKey(h.MigrateBonuses)))
+
 		// Health check
 		mux.HandleFunc(stage+"/api/health", h.EnableCORS(h.HealthCheck))
 	}
@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAPIKeyChecks(t *testing.T) {
+	tests := []struct {
+		name      string
+		configKey string
+		sentKey   string
+		maester   bool
+		want      int
+	}{
+		{name: "no key configured, none sent", maester: true, want: http.StatusOK},
+		{name: "no key configured, one sent", sentKey: "anything", maester: true, want: http.StatusOK},
+		{name: "maester route without key", configKey: "secret", maester: true, want: http.StatusUnauthorized},
+		{name: "maester route with wrong key", configKey: "secret", sentKey: "wrong", maester: true, want: http.StatusUnauthorized},
+		{name: "maester route with key", configKey: "secret", sentKey: "secret", maester: true, want: http.StatusOK},
+		{name: "open route without key", configKey: "secret", want: http.StatusOK},
+		{name: "open route with wrong key", configKey: "secret", sentKey: "wrong", want: http.StatusUnauthorized},
+		{name: "open route with key", configKey: "secret", sentKey: "secret", want: http.StatusOK},
+	}
+
+	ok := func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) }
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewAPIHandlers(nil)
+			h.SetAPIKey(tt.configKey)
+			handler := h.EnableCORS(ok)
+			if tt.maester {
+				handler = h.EnableCORS(h.RequireAPIKey(ok))
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/api/member/create", nil)
+			if tt.sentKey != "" {
+				req.Header.Set("X-Api-Key", tt.sentKey)
+			}
+			rec := httptest.NewRecorder()
+			handler(rec, req)
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestMaesterRoutesRequireKey(t *testing.T) {
+	h := NewAPIHandlers(nil)
+	h.SetAPIKey("secret")
+	mux := h.SetupRoutes()
+
+	for _, path := range []string{"/api/member/create", "/dev/api/inventory/add", "/api/distribution/pick-winner", "/api/reports"} {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, path, nil))
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf("%s without key: status = %d, want %d", path, rec.Code, http.StatusUnauthorized)
+		}
+	}
+
+	// Preflight requests never carry the key
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodOptions, "/api/member/create", nil))
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("preflight: status = %d, want %d", rec.Code, http.StatusNoContent)
+	}
+}
@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"flavaflav/internal/db"
+	"flavaflav/internal/models"
+)
+
+type SetBuildTagsRequest struct {
+	DiscordID string   `json:"discord_id"`
+	Tags      []string `json:"tags"` // replaces the member's tags; empty clears them
+}
+
+// BuildTag lists the built-in link types that help one build
+type BuildTag struct {
+	Tag       string   `json:"tag"`
+	LinkTypes []string `json:"link_types"`
+}
+
+// SetBuildTags replaces the build tags used to match a member with link types
+func (h *APIHandlers) SetBuildTags(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.sendErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req SetBuildTagsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendErrorResponse(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.DiscordID == "" {
+		h.sendErrorResponse(w, "discord_id is required", http.StatusBadRequest)
+		return
+	}
+
+	member, err := h.db.GetMember(r.Context(), req.DiscordID)
+	if err != nil {
+		h.sendErrorResponse(w, "Member not found", http.StatusNotFound)
+		return
+	}
+
+	if err := member.SetBuildTags(req.Tags); err != nil {
+		h.sendErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = h.db.UpdateMemberBuildTags(r.Context(), member)
+	if errors.Is(err, db.ErrMemberNotFound) {
+		h.sendErrorResponse(w, "Member not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		h.sendErrorResponse(w, "Failed to update member", http.StatusInternalServerError)
+		return
+	}
+
+	h.sendSuccessResponse(w, member)
+}
+
+// GetBuildTags returns every build tag with the link types it matches. Link
+// types not listed under any tag suit every build.
+func (h *APIHandlers) GetBuildTags(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.sendErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	tags := make([]BuildTag, len(models.AllBuildTags))
+	for i, tag := range models.AllBuildTags {
+		tags[i] = BuildTag{Tag: tag, LinkTypes: models.BuildTagLinkTypes(tag)}
+	}
+
+	h.sendSuccessResponse(w, tags)
+}
@@ -0,0 +1,344 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"flavaflav/internal/db"
+	"flavaflav/internal/models"
+)
+
+type CatalogEntryRequest struct {
+	Name          string     `json:"name"`
+	Category      *string    `json:"category"`
+	Bronze        *string    `json:"bronze"`
+	Silver        *string    `json:"silver"`
+	Gold          *string    `json:"gold"`
+	IsActive      *bool      `json:"is_active"`
+	EffectiveFrom *time.Time `json:"effective_from"` // defaults to now
+}
+
+// Catalog endpoints
+
+// GetCatalog returns the link types in force now or at ?at=<RFC3339>
+func (h *APIHandlers) GetCatalog(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.sendErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	at, err := parseTimeParam(r, "at", time.Now())
+	if err != nil {
+		h.sendErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	catalog, err := h.db.GetLinkCatalog(r.Context())
+	if err != nil {
+		h.sendErrorResponse(w, "Failed to get link catalog", http.StatusInternalServerError)
+		return
+	}
+
+	includeInactive := r.URL.Query().Get("include_inactive") == "true"
+	h.sendSuccessResponse(w, catalog.Current(at, includeInactive))
+}
+
+// GetCatalogCategories returns the link types in each category, in display order
+func (h *APIHandlers) GetCatalogCategories(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.sendErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	catalog, err := h.db.GetLinkCatalog(r.Context())
+	if err != nil {
+		h.sendErrorResponse(w, "Failed to get link catalog", http.StatusInternalServerError)
+		return
+	}
+
+	h.sendSuccessResponse(w, catalog.Categories(time.Now()))
+}
+
+// GetCatalogHistory returns every version of a link type
+func (h *APIHandlers) GetCatalogHistory(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.sendErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	name := r.URL.Query().Get("name")
+	if name == "" {
+		h.sendErrorResponse(w, "name parameter is required", http.StatusBadRequest)
+		return
+	}
+
+	catalog, err := h.db.GetLinkCatalog(r.Context())
+	if err != nil {
+		h.sendErrorResponse(w, "Failed to get link catalog", http.StatusInternalServerError)
+		return
+	}
+
+	history := catalog.History(name)
+	if len(history) == 0 {
+		h.sendErrorResponse(w, "Link type not found", http.StatusNotFound)
+		return
+	}
+
+	h.sendSuccessResponse(w, history)
+}
+
+// GetCatalogBonus resolves a bonus as it was at ?at=<RFC3339>, e.g. a link's added date
+func (h *APIHandlers) GetCatalogBonus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.sendErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	linkType := r.URL.Query().Get("link_type")
+	quality := r.URL.Query().Get("quality")
+	if linkType == "" || quality == "" {
+		h.sendErrorResponse(w, "link_type and quality parameters are required", http.StatusBadRequest)
+		return
+	}
+
+	at, err := parseTimeParam(r, "at", time.Now())
+	if err != nil {
+		h.sendErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	catalog, err := h.db.GetLinkCatalog(r.Context())
+	if err != nil {
+		h.sendErrorResponse(w, "Failed to get link catalog", http.StatusInternalServerError)
+		return
+	}
+
+	bonus, bonusValue := models.NormalizeBonus(catalog.GetLinkTypeBonus(linkType, quality, at))
+	h.sendSuccessResponse(w, map[string]interface{}{
+		"link_type":   linkType,
+		"quality":     quality,
+		"at":          at,
+		"bonus":       bonus,
+		"bonus_value": bonusValue,
+	})
+}
+
+// CreateCatalogEntry adds a new link type to the catalog (Maester only)
+func (h *APIHandlers) CreateCatalogEntry(w http.ResponseWriter, r *http.Request) {
+	h.saveCatalogEntry(w, r, true)
+}
+
+// UpdateCatalogEntry adds a new version of an existing link type, e.g. after a
+// rebalance or to deactivate it (Maester only)
+func (h *APIHandlers) UpdateCatalogEntry(w http.ResponseWriter, r *http.Request) {
+	h.saveCatalogEntry(w, r, false)
+}
+
+func (h *APIHandlers) saveCatalogEntry(w http.ResponseWriter, r *http.Request, create bool) {
+	if r.Method != http.MethodPost {
+		h.sendErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// TODO: Add authentication check for Maester role
+
+	if !h.db.CatalogEnabled() {
+		h.sendErrorResponse(w, "Link catalog table is not configured", http.StatusNotImplemented)
+		return
+	}
+
+	var req CatalogEntryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendErrorResponse(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.Name == "" {
+		h.sendErrorResponse(w, "name is required", http.StatusBadRequest)
+		return
+	}
+
+	catalog, err := h.db.GetLinkCatalog(r.Context())
+	if err != nil {
+		h.sendErrorResponse(w, "Failed to get link catalog", http.StatusInternalServerError)
+		return
+	}
+
+	effectiveFrom := time.Now()
+	if req.EffectiveFrom != nil {
+		effectiveFrom = *req.EffectiveFrom
+	}
+
+	existing := catalog.History(req.Name)
+	entry := &models.CatalogEntry{Name: req.Name, IsActive: true}
+	if create {
+		if len(existing) > 0 {
+			h.sendErrorResponse(w, "Link type already exists; use /api/catalog/update", http.StatusConflict)
+			return
+		}
+		if req.Category == nil || req.Bronze == nil || req.Silver == nil || req.Gold == nil {
+			h.sendErrorResponse(w, "category, bronze, silver and gold are required", http.StatusBadRequest)
+			return
+		}
+	} else {
+		current := catalog.Resolve(req.Name, effectiveFrom)
+		if current == nil && len(existing) > 0 {
+			current = existing[0] // new version predates all others
+		}
+		if current == nil {
+			h.sendErrorResponse(w, "Link type not found; use /api/catalog/create", http.StatusNotFound)
+			return
+		}
+		copied := *current
+		entry = &copied
+	}
+
+	if req.Category != nil {
+		entry.Category = *req.Category
+	}
+	if req.Bronze != nil {
+		entry.Bronze = *req.Bronze
+	}
+	if req.Silver != nil {
+		entry.Silver = *req.Silver
+	}
+	if req.Gold != nil {
+		entry.Gold = *req.Gold
+	}
+	if req.IsActive != nil {
+		entry.IsActive = *req.IsActive
+	}
+	for _, bonus := range []string{entry.Bronze, entry.Silver, entry.Gold} {
+		if _, err := models.ParseBonus(bonus); err != nil && bonus != "TBD" {
+			h.sendErrorResponse(w, fmt.Sprintf("%v (use e.g. \"3.75%%\" or \"4.50\" skill points)", err), http.StatusBadRequest)
+			return
+		}
+	}
+	entry.EffectiveFrom = effectiveFrom
+	entry.UpdatedBy = "web-admin"
+	entry.UpdatedAt = time.Now()
+
+	if err := h.db.PutCatalogEntry(r.Context(), entry); err != nil {
+		h.sendErrorResponse(w, "Failed to save catalog entry", http.StatusInternalServerError)
+		return
+	}
+
+	h.sendSuccessResponse(w, entry)
+}
+
+// DeleteCatalogEntry removes one version of a link type (Maester only)
+func (h *APIHandlers) DeleteCatalogEntry(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.sendErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// TODO: Add authentication check for Maester role
+
+	if !h.db.CatalogEnabled() {
+		h.sendErrorResponse(w, "Link catalog table is not configured", http.StatusNotImplemented)
+		return
+	}
+
+	name := r.URL.Query().Get("name")
+	effectiveParam := r.URL.Query().Get("effective_from")
+	if name == "" || effectiveParam == "" {
+		h.sendErrorResponse(w, "name and effective_from (RFC3339) parameters are required", http.StatusBadRequest)
+		return
+	}
+	effectiveFrom, err := time.Parse(time.RFC3339, effectiveParam)
+	if err != nil {
+		h.sendErrorResponse(w, "effective_from must be an RFC3339 timestamp", http.StatusBadRequest)
+		return
+	}
+
+	err = h.db.DeleteCatalogEntry(r.Context(), name, effectiveFrom)
+	if errors.Is(err, db.ErrCatalogEntryNotFound) {
+		h.sendErrorResponse(w, "No version of that link type is effective from that time", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		h.sendErrorResponse(w, "Failed to delete catalog entry", http.StatusInternalServerError)
+		return
+	}
+
+	h.sendSuccessResponse(w, map[string]interface{}{
+		"message": fmt.Sprintf("Deleted %s version effective %s", name, effectiveFrom.Format(time.RFC3339)),
+	})
+}
+
+// SeedCatalog stores the built-in version of each built-in link type that has
+// no version effective since the beginning of time in the catalog table (Maester only)
+func (h *APIHandlers) SeedCatalog(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.sendErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// TODO: Add authentication check for Maester role
+
+	if !h.db.CatalogEnabled() {
+		h.sendErrorResponse(w, "Link catalog table is not configured", http.StatusNotImplemented)
+		return
+	}
+
+	stored, err := h.db.GetCatalogEntries(r.Context())
+	if err != nil {
+		h.sendErrorResponse(w, "Failed to get link catalog", http.StatusInternalServerError)
+		return
+	}
+
+	hasBaseline := make(map[string]bool)
+	for _, entry := range stored {
+		if entry.EffectiveFrom.IsZero() {
+			hasBaseline[entry.Name] = true
+		}
+	}
+
+	seeded := 0
+	for _, entry := range models.DefaultCatalogEntries() {
+		if hasBaseline[entry.Name] {
+			continue
+		}
+		entry.UpdatedAt = time.Now()
+		if err := h.db.PutCatalogEntry(r.Context(), entry); err != nil {
+			h.sendErrorResponse(w, fmt.Sprintf("Failed to seed %s after %d entries", entry.Name, seeded), http.StatusInternalServerError)
+			return
+		}
+		seeded++
+	}
+
+	h.sendSuccessResponse(w, map[string]interface{}{
+		"message": fmt.Sprintf("Seeded %d link types", seeded),
+	})
+}
+
+// parseTimeParam reads an optional RFC3339 query parameter
+func parseTimeParam(r *http.Request, name string, fallback time.Time) (time.Time, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return fallback, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s must be an RFC3339 timestamp", name)
+	}
+	return t, nil
+}
+
+// categoryParam reads an optional ?category= parameter naming one of the
+// catalog's categories
+func categoryParam(r *http.Request, catalog *models.LinkCatalog) (string, error) {
+	value := r.URL.Query().Get("category")
+	if value == "" {
+		return "", nil
+	}
+	category, ok := catalog.ParseCategory(value)
+	if !ok {
+		return "", fmt.Errorf("unknown category %q", value)
+	}
+	return category, nil
+}
@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"flavaflav/internal/db"
+)
+
+type SetCharactersRequest struct {
+	DiscordID  string   `json:"discord_id"`
+	Characters []string `json:"characters"` // replaces the member's characters
+	Primary    string   `json:"primary"`    // defaults to the first character
+}
+
+// SetCharacters replaces the in-game characters a member can receive links on
+func (h *APIHandlers) SetCharacters(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.sendErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req SetCharactersRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendErrorResponse(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.DiscordID == "" {
+		h.sendErrorResponse(w, "discord_id is required", http.StatusBadRequest)
+		return
+	}
+
+	member, err := h.db.GetMember(r.Context(), req.DiscordID)
+	if err != nil {
+		h.sendErrorResponse(w, "Member not found", http.StatusNotFound)
+		return
+	}
+
+	if err := member.SetCharacters(req.Characters, req.Primary); err != nil {
+		h.sendErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = h.db.UpdateMemberCharacters(r.Context(), member)
+	if errors.Is(err, db.ErrMemberNotFound) {
+		h.sendErrorResponse(w, "Member not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		h.sendErrorResponse(w, "Failed to update member", http.StatusInternalServerError)
+		return
+	}
+
+	h.sendSuccessResponse(w, member)
+}
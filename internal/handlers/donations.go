@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"flavaflav/internal/models"
+	"flavaflav/internal/stats"
+)
+
+// Donation endpoints
+
+// GetDonations ranks members by links donated (?period=...&quality=...)
+func (h *APIHandlers) GetDonations(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.sendErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	period, err := stats.ParsePeriod(r.URL.Query().Get("period"), time.Now())
+	if err != nil {
+		h.sendErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	links, err := h.db.GetAllInventoryLinks(r.Context())
+	if err != nil {
+		h.sendErrorResponse(w, "Failed to get inventory", http.StatusInternalServerError)
+		return
+	}
+
+	h.sendSuccessResponse(w, map[string]interface{}{
+		"leaderboard": stats.DonationLeaderboard(links, period, r.URL.Query().Get("quality")),
+		"donor_bonus": h.donorBonus,
+	})
+}
+
+// GetMemberDonations returns the links a member donated and the draw entries
+// they currently earn from them (?member_id=...)
+func (h *APIHandlers) GetMemberDonations(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.sendErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	memberID := r.URL.Query().Get("member_id")
+	if memberID == "" {
+		h.sendErrorResponse(w, "member_id parameter is required", http.StatusBadRequest)
+		return
+	}
+
+	links, err := h.db.GetAllInventoryLinks(r.Context())
+	if err != nil {
+		h.sendErrorResponse(w, "Failed to get inventory", http.StatusInternalServerError)
+		return
+	}
+
+	h.sendSuccessResponse(w, map[string]interface{}{
+		"donations":   stats.DonationsBy(links, memberID),
+		"bonus_draws": h.donorBonus.Entries(links, time.Now())[memberID],
+	})
+}
+
+// findDonor looks up the member credited with donating new links; an empty
+// ID means the donor is unknown
+func (h *APIHandlers) findDonor(r *http.Request, donorID string) (*models.Member, error) {
+	if donorID == "" {
+		return nil, nil
+	}
+	donor, err := h.db.GetMember(r.Context(), donorID)
+	if err != nil {
+		return nil, fmt.Errorf("donor %s is not a registered member", donorID)
+	}
+	return donor, nil
+}
+
+// donorEntries returns the extra draw entries donors have earned, or nil when
+// the donor bonus is off. Draws go ahead without the bonus if inventory
+// can't be read.
+func (h *APIHandlers) donorEntries(r *http.Request) map[string]int {
+	if !h.donorBonus.Enabled() {
+		return nil
+	}
+	links, err := h.db.GetAllInventoryLinks(r.Context())
+	if err != nil {
+		fmt.Printf("ERROR getting donations for the draw: %v\n", err)
+		return nil
+	}
+	return h.donorBonus.Entries(links, time.Now())
+}
@@ -0,0 +1,257 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"flavaflav/internal/db"
+	"flavaflav/internal/models"
+	"flavaflav/internal/stats"
+)
+
+type CreateEventRequest struct {
+	Name     string    `json:"name"`
+	Kind     string    `json:"kind"`      // boss, siege or other
+	StartsAt time.Time `json:"starts_at"` // defaults to now
+}
+
+type AttendanceRequest struct {
+	EventID string   `json:"event_id"`
+	Add     []string `json:"add"`    // Discord IDs of members who attended
+	Remove  []string `json:"remove"` // Discord IDs recorded by mistake
+}
+
+// Event endpoints
+
+// GetEvents returns the events that started within ?period=..., newest first
+func (h *APIHandlers) GetEvents(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.sendErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if !h.db.EventsEnabled() {
+		h.sendErrorResponse(w, "Events are not configured", http.StatusNotImplemented)
+		return
+	}
+
+	period, err := stats.ParsePeriod(r.URL.Query().Get("period"), time.Now())
+	if err != nil {
+		h.sendErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	events, err := h.db.GetAllEvents(r.Context())
+	if err != nil {
+		h.sendErrorResponse(w, "Failed to get events", http.StatusInternalServerError)
+		return
+	}
+
+	h.sendSuccessResponse(w, stats.EventsIn(events, period))
+}
+
+// CreateEvent records a guild event such as a boss run or siege (Maester only)
+func (h *APIHandlers) CreateEvent(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.sendErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// TODO: Add authentication check for Maester role
+
+	if !h.db.EventsEnabled() {
+		h.sendErrorResponse(w, "Events are not configured", http.StatusNotImplemented)
+		return
+	}
+
+	var req CreateEventRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendErrorResponse(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" || !models.IsValidEventKind(req.Kind) {
+		h.sendErrorResponse(w, "name and kind ("+strings.Join(models.AllEventKinds, "/")+") are required", http.StatusBadRequest)
+		return
+	}
+	if req.StartsAt.IsZero() {
+		req.StartsAt = time.Now()
+	}
+
+	event := models.NewEvent(req.Name, req.Kind, req.StartsAt, "web-admin")
+	if err := h.db.CreateEvent(r.Context(), event); err != nil {
+		fmt.Printf("ERROR creating event: %v\n", err)
+		h.sendErrorResponse(w, "Failed to create event", http.StatusInternalServerError)
+		return
+	}
+
+	h.sendSuccessResponse(w, event)
+}
+
+// DeleteEvent removes an event and the attendance recorded for it (Maester only)
+func (h *APIHandlers) DeleteEvent(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.sendErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// TODO: Add authentication check for Maester role
+
+	if !h.db.EventsEnabled() {
+		h.sendErrorResponse(w, "Events are not configured", http.StatusNotImplemented)
+		return
+	}
+
+	eventID := r.URL.Query().Get("event_id")
+	if eventID == "" {
+		h.sendErrorResponse(w, "event_id parameter is required", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.db.DeleteEvent(r.Context(), eventID); err != nil {
+		h.sendErrorResponse(w, "Failed to delete event", http.StatusInternalServerError)
+		return
+	}
+
+	h.sendSuccessResponse(w, map[string]string{"message": "Event deleted"})
+}
+
+// RecordAttendance adds and removes attendees of an event (Maester only)
+func (h *APIHandlers) RecordAttendance(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.sendErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// TODO: Add authentication check for Maester role
+
+	if !h.db.EventsEnabled() {
+		h.sendErrorResponse(w, "Events are not configured", http.StatusNotImplemented)
+		return
+	}
+
+	var req AttendanceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendErrorResponse(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.EventID == "" || len(req.Add)+len(req.Remove) == 0 {
+		h.sendErrorResponse(w, "event_id and members to add or remove are required", http.StatusBadRequest)
+		return
+	}
+
+	for _, memberID := range req.Add {
+		if _, err := h.db.GetMember(r.Context(), memberID); err != nil {
+			h.sendErrorResponse(w, fmt.Sprintf("Member %s not found", memberID), http.StatusNotFound)
+			return
+		}
+	}
+
+	err := h.db.AddEventAttendees(r.Context(), req.EventID, req.Add)
+	if err == nil {
+		err = h.db.RemoveEventAttendees(r.Context(), req.EventID, req.Remove)
+	}
+	if errors.Is(err, db.ErrEventNotFound) {
+		h.sendErrorResponse(w, "Event not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		fmt.Printf("ERROR recording attendance: %v\n", err)
+		h.sendErrorResponse(w, "Failed to record attendance", http.StatusInternalServerError)
+		return
+	}
+
+	event, err := h.db.GetEvent(r.Context(), req.EventID)
+	if err != nil {
+		h.sendErrorResponse(w, "Failed to get event", http.StatusInternalServerError)
+		return
+	}
+
+	h.sendSuccessResponse(w, event)
+}
+
+// GetAttendance lists how many events every member attended within
+// ?period=... (default the policy's window), with the attendance policy
+func (h *APIHandlers) GetAttendance(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.sendErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if !h.db.EventsEnabled() {
+		h.sendErrorResponse(w, "Events are not configured", http.StatusNotImplemented)
+		return
+	}
+
+	periodName := r.URL.Query().Get("period")
+	if periodName == "" && h.attendance.WindowDays > 0 {
+		periodName = strconv.Itoa(h.attendance.WindowDays) + "d"
+	}
+	period, err := stats.ParsePeriod(periodName, time.Now())
+	if err != nil {
+		h.sendErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	events, err := h.db.GetAllEvents(r.Context())
+	if err != nil {
+		h.sendErrorResponse(w, "Failed to get events", http.StatusInternalServerError)
+		return
+	}
+
+	members, err := h.db.GetAllMembers(r.Context())
+	if err != nil {
+		h.sendErrorResponse(w, "Failed to get members", http.StatusInternalServerError)
+		return
+	}
+
+	h.sendSuccessResponse(w, map[string]interface{}{
+		"period":     period,
+		"attendance": stats.AttendanceBoard(events, members, period),
+		"policy":     h.attendance,
+	})
+}
+
+// attendanceCounts returns how many events each member attended within the
+// policy's window, or nil when events aren't configured or can't be read
+func (h *APIHandlers) attendanceCounts(r *http.Request) map[string]int {
+	if !h.db.EventsEnabled() {
+		return nil
+	}
+	events, err := h.db.GetAllEvents(r.Context())
+	if err != nil {
+		fmt.Printf("ERROR getting attendance: %v\n", err)
+		return nil
+	}
+	return h.attendance.Counts(events, time.Now())
+}
+
+// filterByAttendance drops members who haven't attended enough events when
+// the policy requires attendance. Without attendance data nobody is dropped.
+func (h *APIHandlers) filterByAttendance(r *http.Request, members []*models.Member) []*models.Member {
+	if !h.attendance.RequiresAttendance() {
+		return members
+	}
+	counts := h.attendanceCounts(r)
+	if counts == nil {
+		return members
+	}
+	return h.attendance.FilterByAttendance(members, counts)
+}
+
+// drawEntries returns the extra draw entries members have earned through
+// donations and attendance
+func (h *APIHandlers) drawEntries(r *http.Request) map[string]int {
+	var attendance map[string]int
+	if h.attendance.BonusEnabled() {
+		attendance = h.attendance.Entries(h.attendanceCounts(r))
+	}
+	return models.MergeEntries(h.donorEntries(r), attendance)
+}
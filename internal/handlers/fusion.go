@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"flavaflav/internal/db"
+	"flavaflav/internal/models"
+	"flavaflav/internal/notify"
+)
+
+type FuseRequest struct {
+	LinkIDs  []string `json:"link_ids"`  // available links of one quality
+	LinkType string   `json:"link_type"` // type of the resulting link
+	Note     string   `json:"note"`
+}
+
+// FuseLinks converts available links of one quality into a new link of the
+// next quality up, keeping the inputs as fused links (Maester only)
+func (h *APIHandlers) FuseLinks(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.sendErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// TODO: Add authentication check for Maester role
+
+	var req FuseRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendErrorResponse(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if len(req.LinkIDs) == 0 || req.LinkType == "" {
+		h.sendErrorResponse(w, "link_ids and link_type are required", http.StatusBadRequest)
+		return
+	}
+	if len(req.LinkIDs) > models.MaxFusionInputs {
+		h.sendErrorResponse(w, fmt.Sprintf("at most %d links can be fused at once", models.MaxFusionInputs), http.StatusBadRequest)
+		return
+	}
+
+	inputs := make([]*models.InventoryLink, len(req.LinkIDs))
+	for i, linkID := range req.LinkIDs {
+		link, err := h.db.GetInventoryLink(r.Context(), linkID)
+		if err != nil {
+			h.sendErrorResponse(w, fmt.Sprintf("Link %s not found", linkID), http.StatusNotFound)
+			return
+		}
+		inputs[i] = link
+	}
+
+	resultQuality, ok := models.NextQuality(inputs[0].Quality)
+	if !ok {
+		h.sendErrorResponse(w, fmt.Sprintf("%s links can't be fused into a higher quality", inputs[0].Quality), http.StatusBadRequest)
+		return
+	}
+
+	catalog, err := h.db.GetLinkCatalog(r.Context())
+	if err != nil {
+		h.sendErrorResponse(w, "Failed to get link catalog", http.StatusInternalServerError)
+		return
+	}
+	category, bonus, err := catalog.InventoryDetails(req.LinkType, resultQuality, time.Now())
+	if err != nil {
+		h.sendErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	result, err := models.Fuse(inputs, req.LinkType, category, bonus, req.Note, "web-admin")
+	if err != nil {
+		h.sendErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = h.db.FuseLinks(r.Context(), inputs, result)
+	if errors.Is(err, db.ErrLinkUnavailable) {
+		h.sendErrorResponse(w, "A link was distributed, reserved or fused by someone else; nothing was changed", http.StatusConflict)
+		return
+	}
+	if err != nil {
+		fmt.Printf("ERROR fusing links: %v\n", err)
+		h.sendErrorResponse(w, "Failed to fuse links", http.StatusInternalServerError)
+		return
+	}
+
+	h.announce(r, notify.EventInventory, notify.FusionEmbed(inputs, result, "web-admin"))
+
+	h.checkStock(r)
+
+	h.sendSuccessResponse(w, map[string]interface{}{
+		"link":   result,
+		"inputs": inputs,
+	})
+}
+
+// GetInventoryLedger accounts for every link per quality: added or fused in,
+// and available, reserved, distributed, retired or fused away (Maester only)
+func (h *APIHandlers) GetInventoryLedger(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.sendErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// TODO: Add authentication check for Maester role
+
+	links, err := h.db.GetAllInventoryLinks(r.Context())
+	if err != nil {
+		h.sendErrorResponse(w, "Failed to get inventory", http.StatusInternalServerError)
+		return
+	}
+
+	h.sendSuccessResponse(w, models.ReconcileInventory(links))
+}
@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"flavaflav/internal/importer"
+	"flavaflav/internal/notify"
+)
+
+type ImportInventoryRequest struct {
+	Format       string `json:"format"` // "csv" or "paste"
+	Data         string `json:"data"`
+	Commit       bool   `json:"commit"`        // false returns the preview only
+	AllowUnknown bool   `json:"allow_unknown"` // add unknown link types as custom links
+	DonorID      string `json:"donor_id"`      // optional member who donated the links
+}
+
+// ImportInventory previews or commits a bulk inventory import (Maester only)
+func (h *APIHandlers) ImportInventory(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.sendErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// TODO: Add authentication check for Maester role
+
+	var req ImportInventoryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendErrorResponse(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.Data == "" {
+		h.sendErrorResponse(w, "data is required", http.StatusBadRequest)
+		return
+	}
+
+	donor, err := h.findDonor(r, req.DonorID)
+	if err != nil {
+		h.sendErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	catalog, err := h.db.GetLinkCatalog(r.Context())
+	if err != nil {
+		h.sendErrorResponse(w, "Failed to get link catalog", http.StatusInternalServerError)
+		return
+	}
+
+	preview, err := importer.Build(req.Format, req.Data, catalog, time.Now())
+	if err != nil {
+		h.sendErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if !req.Commit {
+		h.sendSuccessResponse(w, map[string]interface{}{
+			"preview":    preview,
+			"can_commit": preview.CanCommit(req.AllowUnknown),
+		})
+		return
+	}
+
+	if !preview.CanCommit(req.AllowUnknown) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		json.NewEncoder(w).Encode(APIResponse{
+			Success: false,
+			Data:    map[string]interface{}{"preview": preview, "can_commit": false},
+			Error:   "Import has errors or unknown link types; nothing was added",
+		})
+		return
+	}
+
+	links := preview.Links("web-admin")
+	if donor != nil {
+		for _, link := range links {
+			link.SetDonor(donor.DiscordID, donor.Username)
+		}
+	}
+	if err := h.db.BatchCreateInventoryLinks(r.Context(), links); err != nil {
+		fmt.Printf("ERROR importing inventory: %v\n", err)
+		h.sendErrorResponse(w, fmt.Sprintf("Failed to import inventory: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	h.announce(r, notify.EventInventory, notify.InventoryAddedEmbed(links, "web-admin"))
+
+	h.checkStock(r)
+
+	h.sendSuccessResponse(w, map[string]interface{}{
+		"message": fmt.Sprintf("Imported %d links", len(links)),
+		"preview": preview,
+	})
+}
@@ -0,0 +1,178 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"flavaflav/internal/db"
+	"flavaflav/internal/models"
+)
+
+type EditNotesRequest struct {
+	LinkID string `json:"link_id"`
+	Notes  string `json:"notes"`
+}
+
+type LinkStatusRequest struct {
+	LinkIDs []string `json:"link_ids"`
+	Reason  string   `json:"reason"` // retire only: lost, sold, consumed or mistake
+	Note    string   `json:"note"`
+}
+
+// GetInventoryLink returns one link with its history
+func (h *APIHandlers) GetInventoryLink(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.sendErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	linkID := r.URL.Query().Get("link_id")
+	if linkID == "" {
+		h.sendErrorResponse(w, "link_id parameter is required", http.StatusBadRequest)
+		return
+	}
+
+	link, err := h.db.GetInventoryLink(r.Context(), linkID)
+	if err != nil {
+		h.sendErrorResponse(w, "Link not found", http.StatusNotFound)
+		return
+	}
+
+	h.sendSuccessResponse(w, link)
+}
+
+// GetRetiredInventory returns links taken out of stock (Maester only)
+func (h *APIHandlers) GetRetiredInventory(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.sendErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// TODO: Add authentication check for Maester role
+
+	links, err := h.db.GetRetiredInventoryLinks(r.Context())
+	if err != nil {
+		h.sendErrorResponse(w, "Failed to get retired inventory", http.StatusInternalServerError)
+		return
+	}
+
+	h.sendSuccessResponse(w, links)
+}
+
+// EditLinkNotes replaces a link's notes (Maester only)
+func (h *APIHandlers) EditLinkNotes(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.sendErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// TODO: Add authentication check for Maester role
+
+	var req EditNotesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendErrorResponse(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.LinkID == "" {
+		h.sendErrorResponse(w, "link_id is required", http.StatusBadRequest)
+		return
+	}
+
+	link, err := h.db.GetInventoryLink(r.Context(), req.LinkID)
+	if err != nil {
+		h.sendErrorResponse(w, "Link not found", http.StatusNotFound)
+		return
+	}
+
+	status := link.Status
+	link.EditNotes(req.Notes, "web-admin")
+	if err := h.db.SaveInventoryLinkChange(r.Context(), link, status); err != nil {
+		h.sendLinkChangeError(w, err)
+		return
+	}
+
+	h.sendSuccessResponse(w, link)
+}
+
+// RetireLinks takes available links out of stock with a reason (Maester only)
+func (h *APIHandlers) RetireLinks(w http.ResponseWriter, r *http.Request) {
+	h.changeLinkStatus(w, r, func(link *models.InventoryLink, req LinkStatusRequest) error {
+		return link.Retire(req.Reason, req.Note, "web-admin")
+	})
+}
+
+// RestoreLinks returns retired links to available stock (Maester only)
+func (h *APIHandlers) RestoreLinks(w http.ResponseWriter, r *http.Request) {
+	h.changeLinkStatus(w, r, func(link *models.InventoryLink, req LinkStatusRequest) error {
+		return link.Restore(req.Note, "web-admin")
+	})
+}
+
+// changeLinkStatus applies a status change to every requested link. All links
+// are checked before any is saved.
+func (h *APIHandlers) changeLinkStatus(w http.ResponseWriter, r *http.Request, change func(*models.InventoryLink, LinkStatusRequest) error) {
+	if r.Method != http.MethodPost {
+		h.sendErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// TODO: Add authentication check for Maester role
+
+	var req LinkStatusRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendErrorResponse(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if len(req.LinkIDs) == 0 {
+		h.sendErrorResponse(w, "link_ids is required", http.StatusBadRequest)
+		return
+	}
+
+	links := make([]*models.InventoryLink, len(req.LinkIDs))
+	previous := make([]string, len(req.LinkIDs))
+	for i, linkID := range req.LinkIDs {
+		link, err := h.db.GetInventoryLink(r.Context(), linkID)
+		if err != nil {
+			h.sendErrorResponse(w, fmt.Sprintf("Link %s not found", linkID), http.StatusNotFound)
+			return
+		}
+		previous[i] = link.Status
+		if err := change(link, req); err != nil {
+			h.sendErrorResponse(w, fmt.Sprintf("Link %s: %v", linkID, err), http.StatusBadRequest)
+			return
+		}
+		links[i] = link
+	}
+
+	for i, link := range links {
+		if err := h.db.SaveInventoryLinkChange(r.Context(), link, previous[i]); err != nil {
+			fmt.Printf("ERROR updating link %s: %v\n", link.LinkID, err)
+			if i == 0 {
+				h.sendLinkChangeError(w, err)
+				return
+			}
+			h.sendErrorResponse(w, fmt.Sprintf("Updated %d of %d links; link %s failed: %v", i, len(links), link.LinkID, err), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	h.checkStock(r)
+
+	h.sendSuccessResponse(w, links)
+}
+
+func (h *APIHandlers) sendLinkChangeError(w http.ResponseWriter, err error) {
+	if errors.Is(err, db.ErrLinkChanged) {
+		h.sendErrorResponse(w, "Link was changed by someone else; reload and try again", http.StatusConflict)
+		return
+	}
+	if errors.Is(err, db.ErrListChanged) {
+		h.sendErrorResponse(w, "Distribution list was changed by someone else; reload and try again", http.StatusConflict)
+		return
+	}
+	h.sendErrorResponse(w, "Failed to update link", http.StatusInternalServerError)
+}
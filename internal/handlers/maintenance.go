@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// Maintenance endpoints
+
+// MigrateBonuses backfills numeric bonus values on stored links and
+// distributions (Maester only). It is safe to run more than once.
+func (h *APIHandlers) MigrateBonuses(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.sendErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// TODO: Add authentication check for Maester role
+
+	links, distributions, err := h.db.MigrateBonusValues(r.Context())
+	if err != nil {
+		fmt.Printf("ERROR migrating bonus values: %v\n", err)
+		h.sendErrorResponse(w, fmt.Sprintf("Migration stopped after updating %d links and %d distributions", links, distributions), http.StatusInternalServerError)
+		return
+	}
+
+	h.sendSuccessResponse(w, map[string]interface{}{
+		"message":               fmt.Sprintf("Updated %d links and %d distributions", links, distributions),
+		"links_updated":         links,
+		"distributions_updated": distributions,
+	})
+}
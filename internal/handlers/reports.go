@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"flavaflav/internal/report"
+)
+
+// Report endpoints
+
+// GetReport builds the distribution report for ?month=YYYY-MM or
+// ?from=YYYY-MM-DD&to=YYYY-MM-DD (default: last month) in the guild time
+// zone, as JSON or, with ?format=csv|markdown, as a download (Maester only)
+func (h *APIHandlers) GetReport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.sendErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// TODO: Add authentication check for Maester role
+
+	query := r.URL.Query()
+	format := query.Get("format")
+	switch format {
+	case "":
+		format = report.FormatJSON
+	case "md":
+		format = report.FormatMarkdown
+	case report.FormatJSON, report.FormatCSV, report.FormatMarkdown:
+	default:
+		h.sendErrorResponse(w, "format must be json, csv or markdown", http.StatusBadRequest)
+		return
+	}
+
+	loc, err := time.LoadLocation(os.Getenv("GUILD_TIMEZONE"))
+	if err != nil {
+		loc = time.UTC
+	}
+	now := time.Now()
+	rng, err := report.ParseRange(query.Get("month"), query.Get("from"), query.Get("to"), loc, now)
+	if err != nil {
+		h.sendErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	distributions, err := h.db.GetAllDistributions(r.Context())
+	if err != nil {
+		h.sendErrorResponse(w, "Failed to get distributions", http.StatusInternalServerError)
+		return
+	}
+
+	links, err := h.db.GetAllInventoryLinks(r.Context())
+	if err != nil {
+		h.sendErrorResponse(w, "Failed to get inventory", http.StatusInternalServerError)
+		return
+	}
+
+	lists, err := h.db.GetAllDistributionLists(r.Context())
+	if err != nil {
+		h.sendErrorResponse(w, "Failed to get distribution lists", http.StatusInternalServerError)
+		return
+	}
+
+	result := report.Build(distributions, links, lists, rng, now)
+
+	switch format {
+	case report.FormatCSV:
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", result.Filename(format)))
+		if err := result.WriteCSV(w); err != nil {
+			fmt.Printf("ERROR writing report: %v\n", err)
+		}
+	case report.FormatMarkdown:
+		w.Header().Set("Content-Type", "text/markdown; charset=utf-8")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", result.Filename(format)))
+		fmt.Fprint(w, result.Markdown())
+	default:
+		h.sendSuccessResponse(w, result)
+	}
+}
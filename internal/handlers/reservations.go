@@ -0,0 +1,253 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"flavaflav/internal/db"
+	"flavaflav/internal/models"
+	"flavaflav/internal/notify"
+)
+
+type ReserveRequest struct {
+	ListID string `json:"list_id"`
+	LinkID string `json:"link_id"`
+	Hours  int    `json:"hours"` // defaults to models.DefaultReservationDuration
+}
+
+type ReservationRequest struct {
+	LinkID    string `json:"link_id"`
+	Reason    string `json:"reason"`    // release only
+	Character string `json:"character"` // hand-over only; defaults to the winner's primary character
+}
+
+// ReserveLink confirms the pending draw of a list by holding a link for the
+// winner until they can receive it (Maester only)
+func (h *APIHandlers) ReserveLink(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.sendErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// TODO: Add authentication check for Maester role
+
+	var req ReserveRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendErrorResponse(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.ListID == "" || req.LinkID == "" {
+		h.sendErrorResponse(w, "list_id and link_id are required", http.StatusBadRequest)
+		return
+	}
+	if req.Hours < 0 {
+		h.sendErrorResponse(w, "hours must be positive", http.StatusBadRequest)
+		return
+	}
+
+	list, err := h.db.GetDistributionList(r.Context(), req.ListID)
+	if err != nil {
+		h.sendErrorResponse(w, "Distribution list not found", http.StatusNotFound)
+		return
+	}
+
+	pending := list.PendingDraw()
+	if pending == nil {
+		h.sendErrorResponse(w, "No pending draw to reserve a link for", http.StatusBadRequest)
+		return
+	}
+
+	link, err := h.db.GetInventoryLink(r.Context(), req.LinkID)
+	if err != nil {
+		h.sendErrorResponse(w, "Link not found", http.StatusNotFound)
+		return
+	}
+
+	duration := models.DefaultReservationDuration
+	if req.Hours > 0 {
+		duration = time.Duration(req.Hours) * time.Hour
+	}
+	if err := link.Reserve(pending.MemberID, pending.MemberUsername, list.ListID, time.Now().Add(duration), "web-admin"); err != nil {
+		h.sendErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	list.RemoveMember(pending.MemberID)
+	list.ResolvePendingDraw(pending.MemberID, models.DrawReserved, "", "web-admin")
+
+	err = h.db.ReserveLink(r.Context(), link, list)
+	if errors.Is(err, db.ErrLinkUnavailable) {
+		h.sendErrorResponse(w, "Link is not available", http.StatusConflict)
+		return
+	}
+	if errors.Is(err, db.ErrListChanged) {
+		h.sendErrorResponse(w, "Distribution list was changed by someone else; reload and try again", http.StatusConflict)
+		return
+	}
+	if err != nil {
+		fmt.Printf("ERROR reserving link %s: %v\n", link.LinkID, err)
+		h.sendErrorResponse(w, "Failed to reserve link", http.StatusInternalServerError)
+		return
+	}
+
+	h.checkStock(r)
+
+	h.sendSuccessResponse(w, map[string]interface{}{
+		"link": link,
+		"list": list,
+	})
+}
+
+// GetReservedInventory returns links held for draw winners (Maester only)
+func (h *APIHandlers) GetReservedInventory(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.sendErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// TODO: Add authentication check for Maester role
+
+	links, err := h.db.GetReservedInventoryLinks(r.Context())
+	if err != nil {
+		h.sendErrorResponse(w, "Failed to get reserved inventory", http.StatusInternalServerError)
+		return
+	}
+
+	h.sendSuccessResponse(w, links)
+}
+
+// HandOverReservation records that a reserved link was given to its winner (Maester only)
+func (h *APIHandlers) HandOverReservation(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.sendErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// TODO: Add authentication check for Maester role
+
+	var req ReservationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendErrorResponse(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.LinkID == "" {
+		h.sendErrorResponse(w, "link_id is required", http.StatusBadRequest)
+		return
+	}
+
+	link, err := h.db.GetInventoryLink(r.Context(), req.LinkID)
+	if err != nil {
+		h.sendErrorResponse(w, "Link not found", http.StatusNotFound)
+		return
+	}
+	listID := link.ReservedListID
+
+	distribution, err := link.HandOver("web", "web-admin")
+	if err != nil {
+		h.sendErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	member, memberErr := h.db.GetMember(r.Context(), distribution.MemberID)
+	if memberErr == nil {
+		distribution.Character, err = member.TargetCharacter(req.Character)
+		if err != nil {
+			h.sendErrorResponse(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	var list *models.DistributionList
+	if listID != "" {
+		list, err = h.db.GetDistributionList(r.Context(), listID)
+		if err == nil {
+			list.CompleteReservedDraw(distribution.MemberID, "web-admin")
+		} else {
+			list = nil
+		}
+	}
+
+	err = h.db.CompleteReservation(r.Context(), link, distribution, list)
+	if errors.Is(err, db.ErrLinkUnavailable) {
+		h.sendErrorResponse(w, "Reservation was already handed over or released", http.StatusConflict)
+		return
+	}
+	if errors.Is(err, db.ErrListChanged) {
+		h.sendErrorResponse(w, "Distribution list was changed by someone else; reload and try again", http.StatusConflict)
+		return
+	}
+	if err != nil {
+		h.sendErrorResponse(w, "Failed to create distribution record", http.StatusInternalServerError)
+		return
+	}
+
+	if memberErr == nil {
+		h.notifyMember(member, models.NotifyDistributions, notify.DistributionEmbed(distribution))
+	}
+	h.announce(r, notify.EventDistributions, notify.DistributionAnnouncementEmbed(distribution))
+
+	h.sendSuccessResponse(w, map[string]interface{}{
+		"distribution": distribution,
+		"link":         link,
+	})
+}
+
+// ReleaseReservation returns a reserved link to stock and puts the winner back
+// on their list (Maester only)
+func (h *APIHandlers) ReleaseReservation(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.sendErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// TODO: Add authentication check for Maester role
+
+	var req ReservationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendErrorResponse(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.LinkID == "" {
+		h.sendErrorResponse(w, "link_id is required", http.StatusBadRequest)
+		return
+	}
+	if req.Reason == "" {
+		req.Reason = "released by an officer"
+	}
+
+	link, err := h.db.GetInventoryLink(r.Context(), req.LinkID)
+	if err != nil {
+		h.sendErrorResponse(w, "Link not found", http.StatusNotFound)
+		return
+	}
+	memberID, listID := link.ReservedFor, link.ReservedListID
+
+	if err := link.ReleaseReservation(req.Reason, "web-admin"); err != nil {
+		h.sendErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var list *models.DistributionList
+	if listID != "" {
+		list, err = h.db.GetDistributionList(r.Context(), listID)
+		if err == nil {
+			list.ReleaseReservedDraw(memberID, req.Reason, "web-admin")
+		} else {
+			list = nil
+		}
+	}
+
+	if err := h.db.ReleaseReservation(r.Context(), link, memberID, list); err != nil {
+		h.sendLinkChangeError(w, err)
+		return
+	}
+
+	h.checkStock(r)
+
+	h.sendSuccessResponse(w, link)
+}
@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"flavaflav/internal/db"
+	"flavaflav/internal/models"
+	"flavaflav/internal/notify"
+)
+
+type ReverseDistributionRequest struct {
+	DistributionID string `json:"distribution_id"`
+	Reason         string `json:"reason"`
+	ReassignTo     string `json:"reassign_to"` // Discord ID of the right member; empty returns the link to stock
+}
+
+// ReverseDistribution undoes a distribution given to the wrong member (Maester only)
+func (h *APIHandlers) ReverseDistribution(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.sendErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// TODO: Add authentication check for Maester role
+
+	var req ReverseDistributionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendErrorResponse(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.DistributionID == "" || req.Reason == "" {
+		h.sendErrorResponse(w, "distribution_id and reason are required", http.StatusBadRequest)
+		return
+	}
+
+	original, err := h.db.GetDistribution(r.Context(), req.DistributionID)
+	if err != nil {
+		h.sendErrorResponse(w, "Distribution not found", http.StatusNotFound)
+		return
+	}
+
+	link, err := h.db.GetInventoryLink(r.Context(), original.LinkID)
+	if err != nil {
+		h.sendErrorResponse(w, "Link not found", http.StatusNotFound)
+		return
+	}
+
+	var newMember *models.Member
+	if req.ReassignTo != "" {
+		newMember, err = h.db.GetMember(r.Context(), req.ReassignTo)
+		if err != nil {
+			h.sendErrorResponse(w, "Member to reassign to not found", http.StatusNotFound)
+			return
+		}
+	}
+
+	var list *models.DistributionList
+	if original.ListID != "" {
+		list, err = h.db.GetDistributionList(r.Context(), original.ListID)
+		if err != nil {
+			list = nil // the list was removed; nothing to restore
+		}
+	}
+
+	replacement, err := models.ReverseDistribution(original, link, list, newMember, req.Reason, "web", "web-admin")
+	if err != nil {
+		h.sendErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = h.db.ReverseDistribution(r.Context(), original, link, replacement, list)
+	if errors.Is(err, db.ErrAlreadyReversed) || errors.Is(err, db.ErrLinkChanged) || errors.Is(err, db.ErrListChanged) {
+		h.sendErrorResponse(w, "Distribution, link or list was changed by someone else; reload and try again", http.StatusConflict)
+		return
+	}
+	if err != nil {
+		fmt.Printf("ERROR reversing distribution %s: %v\n", original.DistributionID, err)
+		h.sendErrorResponse(w, "Failed to reverse distribution", http.StatusInternalServerError)
+		return
+	}
+
+	if replacement != nil {
+		h.notifyMember(newMember, models.NotifyDistributions, notify.DistributionEmbed(replacement))
+	}
+
+	h.checkStock(r)
+
+	h.sendSuccessResponse(w, map[string]interface{}{
+		"reversed":    original,
+		"replacement": replacement,
+		"link":        link,
+	})
+}
@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"time"
+
+	"flavaflav/internal/models"
+	"flavaflav/internal/schedule"
+)
+
+type CreateScheduleRequest struct {
+	Name        string `json:"name"`
+	Quality     string `json:"quality"`
+	Cron        string `json:"cron"`
+	TimeZone    string `json:"time_zone"`
+	ChannelID   string `json:"channel_id"`
+	DrawAfter   int    `json:"draw_after_minutes"`
+	RemindAfter int    `json:"remind_after_hours"`
+}
+
+// Schedule endpoints
+
+// GetSchedules returns all recurring distribution schedules
+func (h *APIHandlers) GetSchedules(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.sendErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if !h.db.SchedulesEnabled() {
+		h.sendErrorResponse(w, "Schedules are not configured", http.StatusNotImplemented)
+		return
+	}
+
+	schedules, err := h.db.GetAllSchedules(r.Context())
+	if err != nil {
+		h.sendErrorResponse(w, "Failed to get schedules", http.StatusInternalServerError)
+		return
+	}
+
+	h.sendSuccessResponse(w, schedules)
+}
+
+// CreateSchedule creates a recurring distribution schedule (Maester only)
+func (h *APIHandlers) CreateSchedule(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.sendErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// TODO: Add authentication check for Maester role
+
+	if !h.db.SchedulesEnabled() {
+		h.sendErrorResponse(w, "Schedules are not configured", http.StatusNotImplemented)
+		return
+	}
+
+	var req CreateScheduleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendErrorResponse(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.Name == "" || req.ChannelID == "" || (req.Quality != "silver" && req.Quality != "gold") {
+		h.sendErrorResponse(w, "name, channel_id and quality (silver/gold) are required", http.StatusBadRequest)
+		return
+	}
+
+	if req.DrawAfter < 0 || req.RemindAfter < 0 {
+		h.sendErrorResponse(w, "draw_after_minutes and remind_after_hours cannot be negative", http.StatusBadRequest)
+		return
+	}
+
+	cron, err := schedule.ParseCron(req.Cron)
+	if err != nil {
+		h.sendErrorResponse(w, "Invalid cron expression: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if req.TimeZone == "" {
+		req.TimeZone = os.Getenv("GUILD_TIMEZONE")
+	}
+	loc, err := time.LoadLocation(req.TimeZone)
+	if err != nil {
+		h.sendErrorResponse(w, "Unknown time_zone", http.StatusBadRequest)
+		return
+	}
+
+	sched := models.NewSchedule(req.Name, req.Quality, req.Cron, loc.String(), req.ChannelID, req.DrawAfter, req.RemindAfter, "web-admin")
+	sched.NextRunAt = cron.Next(time.Now().In(loc))
+	if sched.NextRunAt.IsZero() {
+		h.sendErrorResponse(w, "Cron expression never matches", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.db.CreateSchedule(r.Context(), sched); err != nil {
+		h.sendErrorResponse(w, "Failed to create schedule", http.StatusInternalServerError)
+		return
+	}
+
+	h.sendSuccessResponse(w, sched)
+}
+
+// DeleteSchedule removes a recurring distribution schedule (Maester only)
+func (h *APIHandlers) DeleteSchedule(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.sendErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// TODO: Add authentication check for Maester role
+
+	if !h.db.SchedulesEnabled() {
+		h.sendErrorResponse(w, "Schedules are not configured", http.StatusNotImplemented)
+		return
+	}
+
+	scheduleID := r.URL.Query().Get("schedule_id")
+	if scheduleID == "" {
+		h.sendErrorResponse(w, "schedule_id parameter is required", http.StatusBadRequest)
+		return
+	}
+
+	if _, err := h.db.GetSchedule(r.Context(), scheduleID); err != nil {
+		h.sendErrorResponse(w, "Schedule not found", http.StatusNotFound)
+		return
+	}
+
+	if err := h.db.DeleteSchedule(r.Context(), scheduleID); err != nil {
+		h.sendErrorResponse(w, "Failed to delete schedule", http.StatusInternalServerError)
+		return
+	}
+
+	h.sendSuccessResponse(w, map[string]interface{}{
+		"message": "Schedule deleted",
+	})
+}
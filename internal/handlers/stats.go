@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"flavaflav/internal/models"
+	"flavaflav/internal/stats"
+)
+
+// Stats endpoints
+
+// GetStats returns award counts, wait times, inventory added per officer and
+// the distribution rate for a period (?period=week|month|quarter|year|all|30d)
+func (h *APIHandlers) GetStats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.sendErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	now := time.Now()
+	period, err := stats.ParsePeriod(r.URL.Query().Get("period"), now)
+	if err != nil {
+		h.sendErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	distributions, err := h.db.GetAllDistributions(r.Context())
+	if err != nil {
+		h.sendErrorResponse(w, "Failed to get distributions", http.StatusInternalServerError)
+		return
+	}
+
+	links, err := h.db.GetAllInventoryLinks(r.Context())
+	if err != nil {
+		h.sendErrorResponse(w, "Failed to get inventory", http.StatusInternalServerError)
+		return
+	}
+
+	h.sendSuccessResponse(w, stats.Compute(distributions, links, period, now))
+}
+
+// GetLeaderboard ranks members by links received (?period=...&quality=...)
+func (h *APIHandlers) GetLeaderboard(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.sendErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	now := time.Now()
+	period, err := stats.ParsePeriod(r.URL.Query().Get("period"), now)
+	if err != nil {
+		h.sendErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	distributions, err := h.db.GetAllDistributions(r.Context())
+	if err != nil {
+		h.sendErrorResponse(w, "Failed to get distributions", http.StatusInternalServerError)
+		return
+	}
+
+	h.sendSuccessResponse(w, stats.Leaderboard(distributions, period, r.URL.Query().Get("quality"), now))
+}
+
+// GetFairness compares each eligible member's silver and gold awards with
+// their share of the eligible days in a period, with the Gini coefficient of
+// awards and the longest waits (?period=...&quality=silver|gold)
+func (h *APIHandlers) GetFairness(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.sendErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	now := time.Now()
+	period, err := stats.ParsePeriod(r.URL.Query().Get("period"), now)
+	if err != nil {
+		h.sendErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	quality := r.URL.Query().Get("quality")
+	if quality != "" && quality != models.QualitySilver && quality != models.QualityGold {
+		h.sendErrorResponse(w, "quality must be silver or gold", http.StatusBadRequest)
+		return
+	}
+
+	distributions, err := h.db.GetAllDistributions(r.Context())
+	if err != nil {
+		h.sendErrorResponse(w, "Failed to get distributions", http.StatusInternalServerError)
+		return
+	}
+
+	members, err := h.db.GetAllMembers(r.Context())
+	if err != nil {
+		h.sendErrorResponse(w, "Failed to get members", http.StatusInternalServerError)
+		return
+	}
+
+	h.sendSuccessResponse(w, stats.Fairness(distributions, members, period, quality, now))
+}
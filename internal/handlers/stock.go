@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"flavaflav/internal/models"
+	"flavaflav/internal/notify"
+)
+
+type StockThresholdRequest struct {
+	Quality  string `json:"quality"`
+	LinkType string `json:"link_type"` // empty covers every link type of the quality
+	Minimum  int    `json:"minimum"`   // 0 removes the threshold
+}
+
+// Stock alert endpoints
+
+// GetInventoryAlerts returns the thresholds that available stock is currently below
+func (h *APIHandlers) GetInventoryAlerts(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.sendErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if !h.db.ThresholdsEnabled() {
+		h.sendErrorResponse(w, "Stock thresholds are not configured", http.StatusNotImplemented)
+		return
+	}
+
+	thresholds, err := h.db.GetStockThresholds(r.Context())
+	if err != nil {
+		h.sendErrorResponse(w, "Failed to get stock thresholds", http.StatusInternalServerError)
+		return
+	}
+
+	links, err := h.db.GetAvailableInventoryLinks(r.Context())
+	if err != nil {
+		h.sendErrorResponse(w, "Failed to get inventory", http.StatusInternalServerError)
+		return
+	}
+
+	alerts := models.CheckStock(thresholds, models.SummarizeInventory(links, models.GroupByLinkType))
+	if alerts == nil {
+		alerts = []models.StockAlert{}
+	}
+
+	h.sendSuccessResponse(w, map[string]interface{}{
+		"alerts":     alerts,
+		"thresholds": thresholds,
+	})
+}
+
+// SetStockThreshold creates, updates or removes a minimum stock level (Maester only)
+func (h *APIHandlers) SetStockThreshold(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.sendErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// TODO: Add authentication check for Maester role
+
+	if !h.db.ThresholdsEnabled() {
+		h.sendErrorResponse(w, "Stock thresholds are not configured", http.StatusNotImplemented)
+		return
+	}
+
+	var req StockThresholdRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendErrorResponse(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.Minimum < 0 {
+		h.sendErrorResponse(w, "minimum cannot be negative", http.StatusBadRequest)
+		return
+	}
+
+	if req.Minimum == 0 {
+		if err := h.db.DeleteStockThreshold(r.Context(), models.StockThresholdID(req.Quality, req.LinkType)); err != nil {
+			h.sendErrorResponse(w, "Failed to remove stock threshold", http.StatusInternalServerError)
+			return
+		}
+		h.sendSuccessResponse(w, map[string]string{"message": "Stock threshold removed"})
+		return
+	}
+
+	threshold, err := models.NewStockThreshold(req.Quality, req.LinkType, req.Minimum, "web-admin")
+	if err != nil {
+		h.sendErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.db.PutStockThreshold(r.Context(), threshold); err != nil {
+		h.sendErrorResponse(w, "Failed to save stock threshold", http.StatusInternalServerError)
+		return
+	}
+
+	h.checkStock(r)
+
+	h.sendSuccessResponse(w, threshold)
+}
+
+// checkStock announces thresholds that stock has newly fallen below. It runs
+// after every inventory change; failures are logged and never fail the request.
+func (h *APIHandlers) checkStock(r *http.Request) {
+	if !h.db.ThresholdsEnabled() {
+		return
+	}
+
+	thresholds, err := h.db.GetStockThresholds(r.Context())
+	if err != nil || len(thresholds) == 0 {
+		if err != nil {
+			fmt.Printf("ERROR checking stock: %v\n", err)
+		}
+		return
+	}
+
+	links, err := h.db.GetAvailableInventoryLinks(r.Context())
+	if err != nil {
+		fmt.Printf("ERROR checking stock: %v\n", err)
+		return
+	}
+
+	alerts := models.CheckStock(thresholds, models.SummarizeInventory(links, models.GroupByLinkType))
+	fresh, changed := models.UpdateAlertState(thresholds, alerts)
+	for _, threshold := range changed {
+		if err := h.db.PutStockThreshold(r.Context(), threshold); err != nil {
+			fmt.Printf("ERROR saving stock threshold %s: %v\n", threshold.ThresholdID, err)
+		}
+	}
+
+	if len(fresh) > 0 {
+		h.announce(r, notify.EventStock, notify.StockAlertEmbed(fresh))
+	}
+}
@@ -0,0 +1,327 @@
+package importer
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"flavaflav/internal/models"
+)
+
+// Import formats
+const (
+	FormatCSV   = "csv"   // link type, quality, count, notes
+	FormatPaste = "paste" // text copied from the game's item list
+)
+
+// MaxLinks caps the number of links a single import may create
+const MaxLinks = 500
+
+// Row is one parsed import line
+type Row struct {
+	Line     int    `json:"line"`
+	LinkType string `json:"link_type"`
+	Quality  string `json:"quality"`
+	Count    int    `json:"count"`
+	Notes    string `json:"notes,omitempty"`
+}
+
+// LineError reports a line that couldn't be parsed
+type LineError struct {
+	Line  int    `json:"line"`
+	Text  string `json:"text"`
+	Error string `json:"error"`
+}
+
+// Item is one link type and quality to be added, with the category and bonus
+// the catalog gives it
+type Item struct {
+	LinkType   string             `json:"link_type"`
+	Quality    string             `json:"quality"`
+	Count      int                `json:"count"`
+	Notes      string             `json:"notes,omitempty"`
+	Category   string             `json:"category"`
+	Bonus      string             `json:"bonus"`
+	BonusValue *models.BonusValue `json:"bonus_value,omitempty"`
+	Known      bool               `json:"known"` // false for custom link types not in the catalog
+	Lines      []int              `json:"lines"`
+	Error      string             `json:"error,omitempty"`
+}
+
+// Preview is the validated result of parsing an import
+type Preview struct {
+	Items        []*Item     `json:"items"`
+	Errors       []LineError `json:"errors"`
+	UnknownTypes []string    `json:"unknown_types"`
+	TotalLinks   int         `json:"total_links"`
+}
+
+// Build parses data in the given format and validates it against the catalog
+// as it is at a time
+func Build(format, data string, catalog *models.LinkCatalog, at time.Time) (*Preview, error) {
+	var rows []Row
+	var errs []LineError
+	switch format {
+	case FormatCSV:
+		rows, errs = ParseCSV(strings.NewReader(data))
+	case FormatPaste:
+		rows, errs = ParseGamePaste(data, linkTypeNames(catalog, at))
+	default:
+		return nil, fmt.Errorf("unknown import format %q (use %s or %s)", format, FormatCSV, FormatPaste)
+	}
+	preview := BuildPreview(rows, catalog, at)
+	preview.Errors = append(errs, preview.Errors...)
+	return preview, nil
+}
+
+// ParseCSV reads rows of link type, quality, count and notes. A header row is
+// skipped; count defaults to 1 and notes are optional.
+func ParseCSV(r io.Reader) ([]Row, []LineError) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var rows []Row
+	var errs []LineError
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			if parseErr, ok := err.(*csv.ParseError); ok {
+				errs = append(errs, LineError{Line: parseErr.Line, Error: parseErr.Err.Error()})
+				continue
+			}
+			errs = append(errs, LineError{Error: err.Error()})
+			break
+		}
+		line, _ := reader.FieldPos(0)
+
+		text := strings.Join(record, ",")
+		if strings.TrimSpace(text) == "" || strings.HasPrefix(strings.TrimSpace(record[0]), "#") {
+			continue
+		}
+		if len(rows) == 0 && len(errs) == 0 && isHeader(record) {
+			continue
+		}
+
+		row := Row{Line: line, LinkType: strings.TrimSpace(record[0]), Count: 1}
+		if len(record) < 2 {
+			errs = append(errs, LineError{Line: line, Text: text, Error: "expected link type, quality, count, notes"})
+			continue
+		}
+		quality, ok := parseQuality(record[1])
+		if !ok {
+			errs = append(errs, LineError{Line: line, Text: text, Error: fmt.Sprintf("unknown quality %q", strings.TrimSpace(record[1]))})
+			continue
+		}
+		row.Quality = quality
+		if len(record) > 2 && strings.TrimSpace(record[2]) != "" {
+			count, err := strconv.Atoi(strings.TrimSpace(record[2]))
+			if err != nil || count <= 0 {
+				errs = append(errs, LineError{Line: line, Text: text, Error: fmt.Sprintf("invalid count %q", strings.TrimSpace(record[2]))})
+				continue
+			}
+			row.Count = count
+		}
+		if len(record) > 3 {
+			row.Notes = strings.TrimSpace(strings.Join(record[3:], ","))
+		}
+		if row.LinkType == "" {
+			errs = append(errs, LineError{Line: line, Text: text, Error: "link type is required"})
+			continue
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, errs
+}
+
+var (
+	pasteCountPrefix = regexp.MustCompile(`^\s*(\d+)\s*[xX×]?\s+`)
+	pasteCountSuffix = regexp.MustCompile(`(?:\s[xX×]\s*(\d+)|\((\d+)\))\s*$`)
+	pasteQuality     = regexp.MustCompile(`(?i)\b(bronze|silver|gold)\b`)
+	pasteNoise       = regexp.MustCompile(`(?i)\b(mastery|chain|links?|quality)\b|[+]?\d+(\.\d+)?\s*%?|[:()\[\],–-]`)
+	nonWord          = regexp.MustCompile(`[^a-z0-9%]+`)
+)
+
+// ParseGamePaste reads text copied from the game's item list, one item per
+// line, such as "3x Gold Mastery Chain Link: Melee Damage +4.50%" or
+// "Melee Damage (Silver) x2". It is tolerant of ordering, punctuation and
+// bonus values; link types are matched against the known names, and lines
+// that match none keep their remaining text as a custom link type.
+func ParseGamePaste(text string, linkTypes []string) ([]Row, []LineError) {
+	// Prefer the longest name so "Melee Damage/Ignore Armor Chance" beats "Melee Damage"
+	names := append([]string(nil), linkTypes...)
+	sort.Slice(names, func(i, j int) bool { return len(names[i]) > len(names[j]) })
+
+	var rows []Row
+	var errs []LineError
+	for n, raw := range strings.Split(text, "\n") {
+		line := strings.TrimSpace(raw)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		row := Row{Line: n + 1, Count: 1}
+		rest := line
+		if m := pasteCountPrefix.FindStringSubmatch(rest); m != nil {
+			row.Count, _ = strconv.Atoi(m[1])
+			rest = rest[len(m[0]):]
+		} else if m := pasteCountSuffix.FindStringSubmatch(rest); m != nil {
+			row.Count, _ = strconv.Atoi(m[1] + m[2])
+			rest = rest[:len(rest)-len(m[0])]
+		}
+		if row.Count <= 0 {
+			errs = append(errs, LineError{Line: row.Line, Text: line, Error: "invalid count"})
+			continue
+		}
+
+		normalized := " " + normalize(rest) + " "
+		for _, name := range names {
+			if strings.Contains(normalized, " "+normalize(name)+" ") {
+				row.LinkType = name
+				break
+			}
+		}
+
+		// Skip quality words that belong to the link type, e.g. "Gold/Doubloon Drop Increase"
+		qualities := pasteQuality.FindAllString(strings.ToLower(rest), -1)
+		for _, word := range pasteQuality.FindAllString(strings.ToLower(row.LinkType), -1) {
+			for j, q := range qualities {
+				if q == word {
+					qualities = append(qualities[:j], qualities[j+1:]...)
+					break
+				}
+			}
+		}
+		if len(qualities) == 0 {
+			errs = append(errs, LineError{Line: row.Line, Text: line, Error: "no quality (bronze, silver or gold) found"})
+			continue
+		}
+		row.Quality = qualities[0]
+
+		if row.LinkType == "" {
+			leftover := pasteQuality.ReplaceAllString(rest, "")
+			leftover = strings.Join(strings.Fields(pasteNoise.ReplaceAllString(leftover, " ")), " ")
+			if leftover == "" {
+				errs = append(errs, LineError{Line: row.Line, Text: line, Error: "no link type found"})
+				continue
+			}
+			row.LinkType = leftover
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, errs
+}
+
+// BuildPreview merges rows of the same link type, quality and notes and
+// resolves their category and bonus from the catalog. Link type names are
+// matched case-insensitively; inactive link types are errors.
+func BuildPreview(rows []Row, catalog *models.LinkCatalog, at time.Time) *Preview {
+	canonical := make(map[string]string)
+	for _, entry := range catalog.Current(at, true) {
+		canonical[normalize(entry.Name)] = entry.Name
+	}
+
+	preview := &Preview{Items: []*Item{}, Errors: []LineError{}, UnknownTypes: []string{}}
+	byKey := make(map[string]*Item)
+	unknown := make(map[string]bool)
+	for _, row := range rows {
+		linkType, known := canonical[normalize(row.LinkType)]
+		if !known {
+			linkType = row.LinkType
+		}
+
+		key := linkType + "|" + row.Quality + "|" + row.Notes
+		item, ok := byKey[key]
+		if !ok {
+			item = &Item{LinkType: linkType, Quality: row.Quality, Notes: row.Notes, Known: known}
+			category, bonus, err := catalog.InventoryDetails(linkType, row.Quality, at)
+			if err != nil {
+				item.Error = err.Error()
+			}
+			item.Category = category
+			item.Bonus, item.BonusValue = models.NormalizeBonus(bonus)
+			byKey[key] = item
+			preview.Items = append(preview.Items, item)
+		}
+		item.Count += row.Count
+		item.Lines = append(item.Lines, row.Line)
+		preview.TotalLinks += row.Count
+
+		if !known && !unknown[linkType] {
+			unknown[linkType] = true
+			preview.UnknownTypes = append(preview.UnknownTypes, linkType)
+		}
+	}
+
+	if preview.TotalLinks > MaxLinks {
+		preview.Errors = append(preview.Errors, LineError{Error: fmt.Sprintf("import adds %d links; the limit is %d per import", preview.TotalLinks, MaxLinks)})
+	}
+
+	return preview
+}
+
+// CanCommit returns true if every line parsed and every item is valid.
+// Unknown link types are added as custom links with a "TBD" bonus only when
+// allowUnknown is set.
+func (p *Preview) CanCommit(allowUnknown bool) bool {
+	if len(p.Errors) > 0 || p.TotalLinks == 0 {
+		return false
+	}
+	for _, item := range p.Items {
+		if item.Error != "" {
+			return false
+		}
+	}
+	return allowUnknown || len(p.UnknownTypes) == 0
+}
+
+// Links builds the inventory links the preview describes
+func (p *Preview) Links(addedBy string) []*models.InventoryLink {
+	var links []*models.InventoryLink
+	for _, item := range p.Items {
+		for j := 0; j < item.Count; j++ {
+			link := models.NewInventoryLink(item.LinkType, item.Quality, item.Category, item.Bonus, addedBy)
+			link.Notes = item.Notes
+			links = append(links, link)
+		}
+	}
+	return links
+}
+
+func linkTypeNames(catalog *models.LinkCatalog, at time.Time) []string {
+	var names []string
+	for _, entry := range catalog.Current(at, true) {
+		names = append(names, entry.Name)
+	}
+	return names
+}
+
+func parseQuality(value string) (string, bool) {
+	switch quality := strings.ToLower(strings.TrimSpace(value)); quality {
+	case models.QualityBronze, models.QualitySilver, models.QualityGold:
+		return quality, true
+	}
+	return "", false
+}
+
+func isHeader(record []string) bool {
+	first := strings.ToLower(strings.TrimSpace(record[0]))
+	return strings.Contains(first, "type") || first == "link"
+}
+
+// normalize lowercases a name and collapses punctuation so "Melee Damage /
+// Ignore Armor Chance" matches "melee damage/ignore armor chance"
+func normalize(name string) string {
+	return strings.TrimSpace(nonWord.ReplaceAllString(strings.ToLower(name), " "))
+}
@@ -0,0 +1,298 @@
+package importer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"flavaflav/internal/models"
+)
+
+func TestParseGamePaste(t *testing.T) {
+	names := models.GetAllLinkTypeNames()
+
+	tests := []struct {
+		name    string
+		line    string
+		want    Row    // Line is always 1
+		wantErr string // part of the error if the line should be rejected
+	}{
+		{
+			name: "count prefix",
+			line: "3x Gold Mastery Chain Link: Melee Damage +4.50%",
+			want: Row{LinkType: "Melee Damage", Quality: models.QualityGold, Count: 3},
+		},
+		{
+			name: "count suffix",
+			line: "Melee Damage (Silver) x2",
+			want: Row{LinkType: "Melee Damage", Quality: models.QualitySilver, Count: 2},
+		},
+		{
+			name: "spaced count prefix",
+			line: "2 × Bronze Melee Swing Speed",
+			want: Row{LinkType: "Melee Swing Speed", Quality: models.QualityBronze, Count: 2},
+		},
+		{
+			name: "bracketed count suffix",
+			line: "Gold Melee Defense (4)",
+			want: Row{LinkType: "Melee Defense", Quality: models.QualityGold, Count: 4},
+		},
+		{
+			name: "no count",
+			line: "silver melee damage",
+			want: Row{LinkType: "Melee Damage", Quality: models.QualitySilver, Count: 1},
+		},
+		{
+			name: "longest name wins",
+			line: "Melee Damage/Ignore Armor Chance - Bronze",
+			want: Row{LinkType: "Melee Damage/Ignore Armor Chance", Quality: models.QualityBronze, Count: 1},
+		},
+		{
+			name: "quality word in the link type",
+			line: "Gold/Doubloon Drop Increase (Silver)",
+			want: Row{LinkType: "Gold/Doubloon Drop Increase", Quality: models.QualitySilver, Count: 1},
+		},
+		{
+			name: "quality word in the link type and as the quality",
+			line: "Gold Gold/Doubloon Drop Increase +2.00%",
+			want: Row{LinkType: "Gold/Doubloon Drop Increase", Quality: models.QualityGold, Count: 1},
+		},
+		{
+			name: "custom link type keeps the leftover text",
+			line: "2 x Silver Boss Damage +2%",
+			want: Row{LinkType: "Boss Damage", Quality: models.QualitySilver, Count: 2},
+		},
+		{
+			name:    "quality only from the link type",
+			line:    "Gold/Doubloon Drop Increase",
+			wantErr: "no quality",
+		},
+		{
+			name:    "no quality",
+			line:    "Melee Damage x2",
+			wantErr: "no quality",
+		},
+		{
+			name:    "no link type",
+			line:    "Gold Mastery Chain Link +4.50%",
+			wantErr: "no link type",
+		},
+		{
+			name:    "zero count",
+			line:    "0x Gold Melee Damage",
+			wantErr: "invalid count",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, errs := ParseGamePaste(tt.line, names)
+			if tt.wantErr != "" {
+				if len(rows) != 0 || len(errs) != 1 || !strings.Contains(errs[0].Error, tt.wantErr) {
+					t.Fatalf("ParseGamePaste = %+v, %+v, want one error mentioning %q", rows, errs, tt.wantErr)
+				}
+				if errs[0].Line != 1 || errs[0].Text != tt.line {
+					t.Errorf("error is for line %d %q, want line 1 %q", errs[0].Line, errs[0].Text, tt.line)
+				}
+				return
+			}
+			tt.want.Line = 1
+			if len(errs) != 0 || len(rows) != 1 || rows[0] != tt.want {
+				t.Errorf("ParseGamePaste = %+v, %+v, want %+v", rows, errs, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseGamePasteLines(t *testing.T) {
+	text := "# stash dump\n\n  Gold Melee Damage  \r\nnonsense\nSilver Melee Defense x2\n"
+	rows, errs := ParseGamePaste(text, models.GetAllLinkTypeNames())
+
+	want := []Row{
+		{Line: 3, LinkType: "Melee Damage", Quality: models.QualityGold, Count: 1},
+		{Line: 5, LinkType: "Melee Defense", Quality: models.QualitySilver, Count: 2},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("rows = %+v, want %+v", rows, want)
+	}
+	if len(errs) != 1 || errs[0].Line != 4 {
+		t.Errorf("errors = %+v, want one for line 4", errs)
+	}
+}
+
+func TestParseCSV(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        string
+		want        []Row
+		wantErrLine []int
+	}{
+		{
+			name: "header is skipped",
+			data: "Link Type,Quality,Count,Notes\nMelee Damage,Gold,2,raid drop\n",
+			want: []Row{{Line: 2, LinkType: "Melee Damage", Quality: models.QualityGold, Count: 2, Notes: "raid drop"}},
+		},
+		{
+			name: "short header is skipped",
+			data: "link,quality\nMelee Damage,silver\n",
+			want: []Row{{Line: 2, LinkType: "Melee Damage", Quality: models.QualitySilver, Count: 1}},
+		},
+		{
+			name:        "header only on the first row",
+			data:        "Melee Damage,gold\nlink type,quality\n",
+			want:        []Row{{Line: 1, LinkType: "Melee Damage", Quality: models.QualityGold, Count: 1}},
+			wantErrLine: []int{2},
+		},
+		{
+			name: "count defaults to 1",
+			data: "Melee Damage, BRONZE,\n",
+			want: []Row{{Line: 1, LinkType: "Melee Damage", Quality: models.QualityBronze, Count: 1}},
+		},
+		{
+			name: "notes keep their commas",
+			data: "Melee Damage,gold,1,raid drop, second night\n",
+			want: []Row{{Line: 1, LinkType: "Melee Damage", Quality: models.QualityGold, Count: 1, Notes: "raid drop,second night"}},
+		},
+		{
+			name: "comments and blank lines are skipped",
+			data: "# from the bank\n\nMelee Damage,gold\n",
+			want: []Row{{Line: 3, LinkType: "Melee Damage", Quality: models.QualityGold, Count: 1}},
+		},
+		{
+			name:        "malformed lines",
+			data:        "Melee Damage\nMelee Damage,platinum\nMelee Damage,gold,0\nMelee Damage,gold,two\n,gold,1\nMelee Damage,silver,3\n",
+			want:        []Row{{Line: 6, LinkType: "Melee Damage", Quality: models.QualitySilver, Count: 3}},
+			wantErrLine: []int{1, 2, 3, 4, 5},
+		},
+		{
+			name:        "unterminated quote",
+			data:        "Melee Damage,gold\n\"Melee Damage,gold\n",
+			want:        []Row{{Line: 1, LinkType: "Melee Damage", Quality: models.QualityGold, Count: 1}},
+			wantErrLine: []int{2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, errs := ParseCSV(strings.NewReader(tt.data))
+			if !reflect.DeepEqual(rows, tt.want) {
+				t.Errorf("rows = %+v, want %+v", rows, tt.want)
+			}
+			var errLines []int
+			for _, e := range errs {
+				errLines = append(errLines, e.Line)
+			}
+			if !reflect.DeepEqual(errLines, tt.wantErrLine) {
+				t.Errorf("errors = %+v, want errors for lines %v", errs, tt.wantErrLine)
+			}
+		})
+	}
+}
+
+func TestBuildPreview(t *testing.T) {
+	now := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	catalog := models.NewLinkCatalog(models.WithBuiltinBaselines([]*models.CatalogEntry{
+		{Name: "Old Link", Category: models.CategoryOther, Gold: "1.00%"},
+	}))
+
+	preview := BuildPreview([]Row{
+		{Line: 1, LinkType: "Melee Damage", Quality: models.QualityGold, Count: 2},
+		{Line: 2, LinkType: "melee  damage", Quality: models.QualityGold, Count: 1},
+		{Line: 3, LinkType: "Melee Damage", Quality: models.QualityGold, Count: 1, Notes: "raid"},
+		{Line: 4, LinkType: "Melee Damage", Quality: models.QualitySilver, Count: 1},
+		{Line: 5, LinkType: "Boss Damage", Quality: models.QualitySilver, Count: 1},
+		{Line: 6, LinkType: "Boss Damage", Quality: models.QualityGold, Count: 1},
+		{Line: 7, LinkType: "Old Link", Quality: models.QualityGold, Count: 1},
+	}, catalog, now)
+
+	tests := []struct {
+		linkType string
+		quality  string
+		notes    string
+		count    int
+		lines    []int
+		bonus    string
+		known    bool
+		hasError bool
+	}{
+		{"Melee Damage", models.QualityGold, "", 3, []int{1, 2}, "4.50%", true, false},
+		{"Melee Damage", models.QualityGold, "raid", 1, []int{3}, "4.50%", true, false},
+		{"Melee Damage", models.QualitySilver, "", 1, []int{4}, "3.75%", true, false},
+		{"Boss Damage", models.QualitySilver, "", 1, []int{5}, "TBD", false, false},
+		{"Boss Damage", models.QualityGold, "", 1, []int{6}, "TBD", false, false},
+		{"Old Link", models.QualityGold, "", 1, []int{7}, "", true, true},
+	}
+	if len(preview.Items) != len(tests) {
+		t.Fatalf("got %d items, want %d: %+v", len(preview.Items), len(tests), preview.Items)
+	}
+	for i, tt := range tests {
+		item := preview.Items[i]
+		t.Run(tt.linkType+"/"+tt.quality+"/"+tt.notes, func(t *testing.T) {
+			if item.LinkType != tt.linkType || item.Quality != tt.quality || item.Notes != tt.notes {
+				t.Fatalf("item %d = %s %s %q, want %s %s %q", i, item.LinkType, item.Quality, item.Notes, tt.linkType, tt.quality, tt.notes)
+			}
+			if item.Count != tt.count || !reflect.DeepEqual(item.Lines, tt.lines) || item.Bonus != tt.bonus || item.Known != tt.known || (item.Error != "") != tt.hasError {
+				t.Errorf("item = %+v, want count %d, lines %v, bonus %q, known %v, error %v", item, tt.count, tt.lines, tt.bonus, tt.known, tt.hasError)
+			}
+		})
+	}
+
+	if preview.TotalLinks != 8 || len(preview.Errors) != 0 {
+		t.Errorf("total = %d, errors = %+v, want 8 links and no errors", preview.TotalLinks, preview.Errors)
+	}
+	if !reflect.DeepEqual(preview.UnknownTypes, []string{"Boss Damage"}) {
+		t.Errorf("UnknownTypes = %v, want [Boss Damage]", preview.UnknownTypes)
+	}
+	if preview.CanCommit(true) {
+		t.Error("a preview with an inactive link type can be committed")
+	}
+}
+
+func TestBuildPreviewLimit(t *testing.T) {
+	catalog := models.DefaultLinkCatalog()
+	now := time.Now()
+
+	tests := []struct {
+		name       string
+		counts     []int
+		wantCommit bool
+	}{
+		{"at the limit", []int{MaxLinks - 1, 1}, true},
+		{"over the limit", []int{MaxLinks, 1}, false},
+		{"nothing to add", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var rows []Row
+			for i, count := range tt.counts {
+				rows = append(rows, Row{Line: i + 1, LinkType: "Melee Damage", Quality: models.QualityBronze, Count: count})
+			}
+			preview := BuildPreview(rows, catalog, now)
+			if got := preview.CanCommit(false); got != tt.wantCommit {
+				t.Errorf("CanCommit = %v with %d links, errors %+v; want %v", got, preview.TotalLinks, preview.Errors, tt.wantCommit)
+			}
+		})
+	}
+}
+
+func TestBuild(t *testing.T) {
+	catalog := models.DefaultLinkCatalog()
+	now := time.Now()
+
+	preview, err := Build(FormatPaste, "3x Gold Mastery Chain Link: Melee Damage +4.50%\nMelee Damage (Gold) x2\nGold Chain Link", catalog, now)
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	if len(preview.Items) != 1 || preview.Items[0].Count != 5 || len(preview.Errors) != 1 || preview.Errors[0].Line != 3 {
+		t.Errorf("preview = %+v items, %+v errors; want 5 gold Melee Damage and an error for line 3", preview.Items, preview.Errors)
+	}
+	if links := preview.Links("officer"); len(links) != 5 || links[0].Bonus != "4.50%" || links[0].Category != models.CategoryMelee {
+		t.Errorf("Links = %d links, want 5 Melee links at 4.50%%", len(links))
+	}
+
+	if _, err := Build("xlsx", "", catalog, now); err == nil {
+		t.Error("Build accepted an unknown format")
+	}
+}
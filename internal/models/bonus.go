@@ -0,0 +1,112 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Bonus units
+const (
+	BonusUnitPercent = "percent" // e.g. "3.75%"
+	BonusUnitSkill   = "skill"   // effective skill points, e.g. "4.50"
+)
+
+// BonusValue is a link bonus as a number and unit
+type BonusValue struct {
+	Amount float64 `json:"amount" dynamodbav:"amount"`
+	Unit   string  `json:"unit" dynamodbav:"unit"`
+}
+
+// ParseBonus parses bonus strings such as "3.75%", "2.1875%", "4.5 %", "+4.50"
+// and "4.50 skill". Values without a percent sign are skill points. "TBD" and
+// other non-numeric values return an error.
+func ParseBonus(s string) (BonusValue, error) {
+	value := strings.TrimSpace(s)
+	value = strings.TrimPrefix(value, "+")
+
+	unit := BonusUnitSkill
+	if strings.HasSuffix(value, "%") {
+		unit = BonusUnitPercent
+		value = strings.TrimSuffix(value, "%")
+	} else {
+		value = strings.TrimSuffix(value, "skill")
+	}
+
+	amount, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil || amount < 0 || math.IsNaN(amount) || math.IsInf(amount, 0) {
+		return BonusValue{}, fmt.Errorf("invalid bonus %q", s)
+	}
+
+	return BonusValue{Amount: amount, Unit: unit}, nil
+}
+
+// String formats the bonus with at least two decimals and without trailing
+// zeros beyond that, e.g. "4.50%", "2.1875%" or "3.75 skill"
+func (b BonusValue) String() string {
+	amount := strconv.FormatFloat(b.Amount, 'f', -1, 64)
+	if dot := strings.IndexByte(amount, '.'); dot < 0 {
+		amount += ".00"
+	} else if decimals := len(amount) - dot - 1; decimals < 2 {
+		amount += strings.Repeat("0", 2-decimals)
+	}
+
+	if b.Unit == BonusUnitPercent {
+		return amount + "%"
+	}
+	return amount + " skill"
+}
+
+// Compare orders bonuses by unit (skill points before percent), then amount:
+// it returns -1, 0 or 1
+func (b BonusValue) Compare(other BonusValue) int {
+	if b.Unit != other.Unit {
+		if b.Unit == BonusUnitPercent {
+			return 1
+		}
+		return -1
+	}
+	switch {
+	case b.Amount < other.Amount:
+		return -1
+	case b.Amount > other.Amount:
+		return 1
+	}
+	return 0
+}
+
+// NormalizeBonus parses a bonus string and returns its normalized display form
+// and value; unparseable bonuses such as "TBD" are returned unchanged with a
+// nil value
+func NormalizeBonus(bonus string) (string, *BonusValue) {
+	value, err := ParseBonus(bonus)
+	if err != nil {
+		return bonus, nil
+	}
+	return value.String(), &value
+}
+
+// TotalBonus sums bonus amounts per unit
+func TotalBonus(values []*BonusValue) map[string]float64 {
+	totals := make(map[string]float64)
+	for _, value := range values {
+		if value != nil {
+			totals[value.Unit] += value.Amount
+		}
+	}
+	return totals
+}
+
+// SortLinksByBonus sorts links by bonus, highest first; links without a
+// numeric bonus sort last
+func SortLinksByBonus(links []*InventoryLink) {
+	sort.SliceStable(links, func(i, j int) bool {
+		a, b := links[i].BonusValue, links[j].BonusValue
+		if a == nil || b == nil {
+			return a != nil
+		}
+		return a.Compare(*b) > 0
+	})
+}
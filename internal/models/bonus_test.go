@@ -0,0 +1,66 @@
+package models
+
+import "testing"
+
+func TestParseBonus(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    BonusValue
+		wantStr string
+		wantErr bool
+	}{
+		{in: "3.75%", want: BonusValue{3.75, BonusUnitPercent}, wantStr: "3.75%"},
+		{in: "2.1875%", want: BonusValue{2.1875, BonusUnitPercent}, wantStr: "2.1875%"},
+		{in: " 4.5 % ", want: BonusValue{4.5, BonusUnitPercent}, wantStr: "4.50%"},
+		{in: "3%", want: BonusValue{3, BonusUnitPercent}, wantStr: "3.00%"},
+		{in: "+4.50", want: BonusValue{4.5, BonusUnitSkill}, wantStr: "4.50 skill"},
+		{in: "4.50 skill", want: BonusValue{4.5, BonusUnitSkill}, wantStr: "4.50 skill"},
+		{in: "0", want: BonusValue{0, BonusUnitSkill}, wantStr: "0.00 skill"},
+		{in: "TBD", wantErr: true},
+		{in: "", wantErr: true},
+		{in: "%", wantErr: true},
+		{in: "skill", wantErr: true},
+		{in: "-1%", wantErr: true},
+		{in: "NaN", wantErr: true},
+		{in: "Inf%", wantErr: true},
+		{in: "4.5%%", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseBonus(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseBonus(%q) error = %v, want error %v", tt.in, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ParseBonus(%q) = %+v, want %+v", tt.in, got, tt.want)
+			}
+			if s := got.String(); s != tt.wantStr {
+				t.Errorf("String() = %q, want %q", s, tt.wantStr)
+			}
+			if again, err := ParseBonus(got.String()); err != nil || again != got {
+				t.Errorf("ParseBonus(String()) = %+v, %v, want %+v", again, err, got)
+			}
+		})
+	}
+}
+
+func TestSortLinksByBonus(t *testing.T) {
+	link := func(id, bonus string) *InventoryLink {
+		l := &InventoryLink{LinkID: id, Bonus: bonus}
+		l.NormalizeBonus()
+		return l
+	}
+	links := []*InventoryLink{link("tbd", "TBD"), link("skill", "4.50"), link("low", "1.00%"), link("high", "3.75%")}
+	SortLinksByBonus(links)
+
+	want := []string{"high", "low", "skill", "tbd"}
+	for i, id := range want {
+		if links[i].LinkID != id {
+			t.Fatalf("order = %s, %s, %s, %s, want %v", links[0].LinkID, links[1].LinkID, links[2].LinkID, links[3].LinkID, want)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Build tags members use to describe how they play
+const (
+	BuildMelee    = "melee"    // melee and archery
+	BuildMage     = "mage"     // magery, necromancy, spirit speak
+	BuildTamer    = "tamer"    // followers and summons
+	BuildBard     = "bard"     // provocation, peacemaking, discordance
+	BuildSailor   = "sailor"   // ship combat
+	BuildPoisoner = "poisoner" // poisoning
+	BuildThief    = "thief"    // stealth, backstab, traps and chests
+	BuildCrafter  = "crafter"  // crafting and harvesting
+)
+
+// AllBuildTags lists every build tag in display order
+var AllBuildTags = []string{
+	BuildMelee, BuildMage, BuildTamer, BuildBard, BuildSailor, BuildPoisoner, BuildThief, BuildCrafter,
+}
+
+// categoryBuildTags maps link categories to the builds they help. Categories
+// that are not listed, such as slayers and resistances, suit any build.
+var categoryBuildTags = map[string][]string{
+	CategoryBarding:  {BuildBard},
+	CategoryBoating:  {BuildSailor},
+	CategoryFollower: {BuildTamer},
+	CategoryMelee:    {BuildMelee},
+	CategorySpell:    {BuildMage},
+	CategoryPoison:   {BuildPoisoner},
+}
+
+// linkTypeBuildTags overrides the category mapping for link types whose
+// category says little about who can use them
+var linkTypeBuildTags = map[string][]string{
+	"Backstab Damage":                     {BuildThief},
+	"Trap Damage":                         {BuildThief},
+	"Chance on Stealth for 5 Extra Steps": {BuildThief},
+	"Chest Success Chances/Progress":      {BuildThief},
+	"Effective Skill on Chests":           {BuildThief},
+	"Effective Alchemy Skill":             {BuildCrafter},
+	"Alchemy/Healing/Veterinary":          {BuildCrafter, BuildMelee, BuildTamer},
+	"Effective Arms Lore":                 {BuildMelee},
+	"Chivalry Skill":                      {BuildMelee},
+	"Effective Parrying Skill":            {BuildMelee},
+	"Effective Harvest Skill":             {BuildCrafter},
+	"Exceptional Quality Chance":          {BuildCrafter},
+	"Necromancy Skill":                    {BuildMage},
+	"Spirit Speak/Inscription":            {BuildMage},
+	"Summon Duration and Dispel Resist":   {BuildMage, BuildTamer},
+}
+
+// LinkBuildTags returns the builds a link type helps, or nil if it suits any build
+func LinkBuildTags(linkType string) []string {
+	if tags, ok := linkTypeBuildTags[linkType]; ok {
+		return tags
+	}
+	return categoryBuildTags[GetLinkCategory(linkType)]
+}
+
+// BuildTagLinkTypes returns the built-in link types that help a build, in
+// AllLinkTypes order. Link types that suit any build are not included.
+func BuildTagLinkTypes(tag string) []string {
+	var names []string
+	for _, lt := range AllLinkTypes {
+		if containsString(LinkBuildTags(lt.Name), tag) {
+			names = append(names, lt.Name)
+		}
+	}
+	return names
+}
+
+// NormalizeBuildTags validates tags, ignoring case and duplicates, and returns
+// them in AllBuildTags order
+func NormalizeBuildTags(tags []string) ([]string, error) {
+	seen := make(map[string]bool)
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" {
+			continue
+		}
+		if !containsString(AllBuildTags, tag) {
+			return nil, fmt.Errorf("unknown build tag %q (valid tags: %s)", tag, strings.Join(AllBuildTags, ", "))
+		}
+		seen[tag] = true
+	}
+
+	var normalized []string
+	for _, tag := range AllBuildTags {
+		if seen[tag] {
+			normalized = append(normalized, tag)
+		}
+	}
+	return normalized, nil
+}
+
+// SetBuildTags replaces the member's build tags; an empty list clears them
+func (m *Member) SetBuildTags(tags []string) error {
+	normalized, err := NormalizeBuildTags(tags)
+	if err != nil {
+		return err
+	}
+	m.BuildTags = normalized
+	return nil
+}
+
+// SuitsLinkType returns true if a link type suits any build, or shares a tag
+// with the member. Members without tags only match links that suit any build.
+func (m *Member) SuitsLinkType(linkType string) bool {
+	linkTags := LinkBuildTags(linkType)
+	if len(linkTags) == 0 {
+		return true
+	}
+	for _, tag := range m.BuildTags {
+		if containsString(linkTags, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// FilterMembersForLinkType returns the members a link type suits
+func FilterMembersForLinkType(members []*Member, linkType string) []*Member {
+	var matched []*Member
+	for _, member := range members {
+		if member.SuitsLinkType(linkType) {
+			matched = append(matched, member)
+		}
+	}
+	return matched
+}
+
+// UnsuitedMembers returns the IDs of members a link type does not suit, for
+// excluding them from a draw
+func UnsuitedMembers(members []*Member, linkType string) []string {
+	var ids []string
+	for _, member := range members {
+		if !member.SuitsLinkType(linkType) {
+			ids = append(ids, member.DiscordID)
+		}
+	}
+	return ids
+}
+
+// DescribeBuildTags lists the member's build tags, e.g. "melee, tamer"
+func (m *Member) DescribeBuildTags() string {
+	return strings.Join(m.BuildTags, ", ")
+}
@@ -0,0 +1,136 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MaxCharacters limits the in-game characters a member can register
+const MaxCharacters = 10
+
+// Character is an in-game character a member plays
+type Character struct {
+	Name    string `json:"name" dynamodbav:"name"`
+	Primary bool   `json:"primary" dynamodbav:"primary"` // links go here unless another character is chosen
+}
+
+// AddCharacter registers an in-game character. The first character, or one
+// added as primary, becomes the primary character.
+func (m *Member) AddCharacter(name string, primary bool) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return fmt.Errorf("character name is required")
+	}
+	if _, ok := m.findCharacter(name); ok {
+		return fmt.Errorf("%s is already registered", name)
+	}
+	if len(m.Characters) >= MaxCharacters {
+		return fmt.Errorf("at most %d characters can be registered", MaxCharacters)
+	}
+
+	m.Characters = append(m.Characters, Character{Name: name})
+	if primary || len(m.Characters) == 1 {
+		m.setPrimary(len(m.Characters) - 1)
+	}
+	return nil
+}
+
+// RemoveCharacter unregisters a character; if it was the primary, the next
+// remaining character becomes primary
+func (m *Member) RemoveCharacter(name string) error {
+	index, ok := m.findCharacter(name)
+	if !ok {
+		return fmt.Errorf("%s is not one of your characters", name)
+	}
+
+	wasPrimary := m.Characters[index].Primary
+	m.Characters = append(m.Characters[:index], m.Characters[index+1:]...)
+	if wasPrimary && len(m.Characters) > 0 {
+		m.setPrimary(0)
+	}
+	return nil
+}
+
+// SetPrimaryCharacter marks a registered character as primary
+func (m *Member) SetPrimaryCharacter(name string) error {
+	index, ok := m.findCharacter(name)
+	if !ok {
+		return fmt.Errorf("%s is not one of your characters", name)
+	}
+	m.setPrimary(index)
+	return nil
+}
+
+// SetCharacters replaces the member's characters, marking primary (or the
+// first character if primary is empty) as the primary
+func (m *Member) SetCharacters(names []string, primary string) error {
+	previous := m.Characters
+	m.Characters = nil
+	for _, name := range names {
+		if err := m.AddCharacter(name, false); err != nil {
+			m.Characters = previous
+			return err
+		}
+	}
+	if primary != "" {
+		if err := m.SetPrimaryCharacter(primary); err != nil {
+			m.Characters = previous
+			return err
+		}
+	}
+	return nil
+}
+
+// PrimaryCharacter returns the name of the primary character, or "" if the
+// member has registered none
+func (m *Member) PrimaryCharacter() string {
+	for _, character := range m.Characters {
+		if character.Primary {
+			return character.Name
+		}
+	}
+	return ""
+}
+
+// TargetCharacter returns the character a link should be sent to: the named
+// one if given, otherwise the primary. It returns "" if the member has no
+// characters.
+func (m *Member) TargetCharacter(name string) (string, error) {
+	if strings.TrimSpace(name) == "" {
+		return m.PrimaryCharacter(), nil
+	}
+	index, ok := m.findCharacter(name)
+	if !ok {
+		return "", fmt.Errorf("%s has no character named %s", m.Username, name)
+	}
+	return m.Characters[index].Name, nil
+}
+
+// DescribeCharacters lists the characters with the primary first, e.g.
+// "Aldric (primary), Brin"
+func (m *Member) DescribeCharacters() string {
+	var names []string
+	for _, character := range m.Characters {
+		if character.Primary {
+			names = append([]string{character.Name + " (primary)"}, names...)
+		} else {
+			names = append(names, character.Name)
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+func (m *Member) findCharacter(name string) (int, bool) {
+	for i, character := range m.Characters {
+		if strings.EqualFold(character.Name, strings.TrimSpace(name)) {
+			return i, true
+		}
+	}
+	return -1, false
+}
+
+func (m *Member) setPrimary(index int) {
+	for i := range m.Characters {
+		m.Characters[i].Primary = i == index
+	}
+}
@@ -1,39 +1,98 @@
 package models
 
 import (
+	"fmt"
+	"math/rand"
 	"time"
 )
 
+// Distribution statuses; distributions stored before reversals existed have no status
+const (
+	DistributionCompleted = "completed"
+	DistributionReversed  = "reversed" // given to the wrong member and undone
+)
+
 // Distribution represents a link distributed to a member
 type Distribution struct {
-	DistributionID string    `json:"distribution_id" dynamodbav:"distribution_id"` // unique ID for this distribution
-	MemberID       string    `json:"member_id" dynamodbav:"member_id"`             // Discord ID of member who received link
-	MemberUsername string    `json:"member_username" dynamodbav:"member_username"` // Discord username for display
-	LinkID         string    `json:"link_id" dynamodbav:"link_id"`                 // ID of the specific link distributed
-	LinkType       string    `json:"link_type" dynamodbav:"link_type"`             // e.g., "Melee Damage"
-	Quality        string    `json:"quality" dynamodbav:"quality"`                 // bronze, silver, gold
-	Bonus          string    `json:"bonus" dynamodbav:"bonus"`                     // e.g., "3.75%"
-	Method         string    `json:"method" dynamodbav:"method"`                   // "web" or "discord"
-	DistributedBy  string    `json:"distributed_by" dynamodbav:"distributed_by"`   // who gave the link
-	DistributedAt  time.Time `json:"distributed_at" dynamodbav:"distributed_at"`
-	Notes          string    `json:"notes" dynamodbav:"notes"` // optional notes about this distribution
+	DistributionID string      `json:"distribution_id" dynamodbav:"distribution_id"`             // unique ID for this distribution
+	MemberID       string      `json:"member_id" dynamodbav:"member_id"`                         // Discord ID of member who received link
+	MemberUsername string      `json:"member_username" dynamodbav:"member_username"`             // Discord username for display
+	LinkID         string      `json:"link_id" dynamodbav:"link_id"`                             // ID of the specific link distributed
+	LinkType       string      `json:"link_type" dynamodbav:"link_type"`                         // e.g., "Melee Damage"
+	Quality        string      `json:"quality" dynamodbav:"quality"`                             // bronze, silver, gold
+	Bonus          string      `json:"bonus" dynamodbav:"bonus"`                                 // e.g., "3.75%"
+	BonusValue     *BonusValue `json:"bonus_value,omitempty" dynamodbav:"bonus_value,omitempty"` // nil for custom "TBD" bonuses
+	Method         string      `json:"method" dynamodbav:"method"`                               // "web" or "discord"
+	DistributedBy  string      `json:"distributed_by" dynamodbav:"distributed_by"`               // who gave the link
+	DistributedAt  time.Time   `json:"distributed_at" dynamodbav:"distributed_at"`
+	Notes          string      `json:"notes" dynamodbav:"notes"`                             // optional notes about this distribution
+	ListID         string      `json:"list_id,omitempty" dynamodbav:"list_id,omitempty"`     // distribution list the winner was drawn from
+	Character      string      `json:"character,omitempty" dynamodbav:"character,omitempty"` // in-game character the link is traded to
+
+	Status         string    `json:"status,omitempty" dynamodbav:"status,omitempty"` // completed or reversed
+	ReversalReason string    `json:"reversal_reason,omitempty" dynamodbav:"reversal_reason,omitempty"`
+	ReversedBy     string    `json:"reversed_by,omitempty" dynamodbav:"reversed_by,omitempty"`
+	ReversedAt     time.Time `json:"reversed_at,omitempty" dynamodbav:"reversed_at,omitempty"`
+	ReassignedTo   string    `json:"reassigned_to,omitempty" dynamodbav:"reassigned_to,omitempty"` // distribution that replaced this one
 }
 
 // NewDistribution creates a new distribution record
 func NewDistribution(memberID, memberUsername, linkID, linkType, quality, bonus, method, distributedBy string) *Distribution {
+	bonus, bonusValue := NormalizeBonus(bonus)
 	return &Distribution{
-		DistributionID: generateDistributionID(),
+		DistributionID: NewID(IDPrefixDistribution),
 		MemberID:       memberID,
 		MemberUsername: memberUsername,
 		LinkID:         linkID,
 		LinkType:       linkType,
 		Quality:        quality,
 		Bonus:          bonus,
+		BonusValue:     bonusValue,
 		Method:         method,
 		DistributedBy:  distributedBy,
 		DistributedAt:  time.Now(),
 		Notes:          "",
+		Status:         DistributionCompleted,
+	}
+}
+
+// IsReversed returns true if the distribution was undone
+func (d *Distribution) IsReversed() bool {
+	return d.Status == DistributionReversed
+}
+
+// Reverse marks the distribution as undone. reassignedTo is the ID of the
+// distribution that gave the link to the right member, if any.
+func (d *Distribution) Reverse(reason, reassignedTo, by string) error {
+	if d.IsReversed() {
+		return fmt.Errorf("distribution was already reversed")
+	}
+	if reason == "" {
+		return fmt.Errorf("a reason is required to reverse a distribution")
 	}
+	d.Status = DistributionReversed
+	d.ReversalReason = reason
+	d.ReassignedTo = reassignedTo
+	d.ReversedBy = by
+	d.ReversedAt = time.Now()
+	return nil
+}
+
+// ActiveDistributions drops reversed distributions
+func ActiveDistributions(distributions []*Distribution) []*Distribution {
+	active := make([]*Distribution, 0, len(distributions))
+	for _, d := range distributions {
+		if !d.IsReversed() {
+			active = append(active, d)
+		}
+	}
+	return active
+}
+
+// NormalizeBonus fills in the numeric bonus of distributions stored before
+// bonus values were tracked and normalizes the display form
+func (d *Distribution) NormalizeBonus() {
+	d.Bonus, d.BonusValue = NormalizeBonus(d.Bonus)
 }
 
 // GetDisplayName returns a formatted display name for the distribution
@@ -41,26 +100,48 @@ func (d *Distribution) GetDisplayName() string {
 	return d.Quality + " " + d.LinkType + " (" + d.Bonus + ")"
 }
 
-// generateDistributionID creates a unique ID for a distribution
-func generateDistributionID() string {
-	return "dist_" + time.Now().Format("20060102150405") + "_" + string(rune(time.Now().UnixNano()%1000))
-}
-
 // DistributionList represents a list of eligible members for distribution
 type DistributionList struct {
-	ListID          string    `json:"list_id" dynamodbav:"list_id"`
-	ListName        string    `json:"list_name" dynamodbav:"list_name"`               // e.g., "Silver Links - January 2024"
-	Quality         string    `json:"quality" dynamodbav:"quality"`                   // silver or gold
-	EligibleMembers []string  `json:"eligible_members" dynamodbav:"eligible_members"` // Discord IDs
-	CreatedBy       string    `json:"created_by" dynamodbav:"created_by"`
-	CreatedAt       time.Time `json:"created_at" dynamodbav:"created_at"`
-	IsActive        bool      `json:"is_active" dynamodbav:"is_active"`
+	ListID          string       `json:"list_id" dynamodbav:"list_id"`
+	ListName        string       `json:"list_name" dynamodbav:"list_name"`               // e.g., "Silver Links - January 2024"
+	Quality         string       `json:"quality" dynamodbav:"quality"`                   // silver or gold
+	EligibleMembers []string     `json:"eligible_members" dynamodbav:"eligible_members"` // Discord IDs
+	CreatedBy       string       `json:"created_by" dynamodbav:"created_by"`
+	CreatedAt       time.Time    `json:"created_at" dynamodbav:"created_at"`
+	IsActive        bool         `json:"is_active" dynamodbav:"is_active"`
+	Draws           []DrawRecord `json:"draws" dynamodbav:"draws"`     // history of winners drawn from this list
+	Version         int          `json:"version" dynamodbav:"version"` // bumped on every save so concurrent edits are detected
+}
+
+// Draw outcome constants
+const (
+	DrawPending   = "pending"   // winner drawn, waiting for an officer decision
+	DrawConfirmed = "confirmed" // winner received a link
+	DrawRerolled  = "rerolled"  // winner was absent and another was drawn
+	DrawCancelled = "cancelled" // draw was abandoned
+	DrawReversed  = "reversed"  // winner's distribution was undone
+	DrawReserved  = "reserved"  // a link is held for the winner until they can receive it
+	DrawExpired   = "expired"   // the winner's reservation ran out before hand-over
+)
+
+// DrawRecord represents a single winner drawn from a distribution list
+type DrawRecord struct {
+	MemberID       string    `json:"member_id" dynamodbav:"member_id"`
+	MemberUsername string    `json:"member_username" dynamodbav:"member_username"`
+	LinkType       string    `json:"link_type,omitempty" dynamodbav:"link_type,omitempty"`     // requested link type, if any
+	MatchBuild     bool      `json:"match_build,omitempty" dynamodbav:"match_build,omitempty"` // only members whose build tags suit the link type were drawn
+	Outcome        string    `json:"outcome" dynamodbav:"outcome"`                             // pending, confirmed, rerolled, cancelled, reversed, reserved, expired
+	Reason         string    `json:"reason,omitempty" dynamodbav:"reason,omitempty"`           // required for re-rolls
+	DrawnBy        string    `json:"drawn_by" dynamodbav:"drawn_by"`
+	DrawnAt        time.Time `json:"drawn_at" dynamodbav:"drawn_at"`
+	ResolvedBy     string    `json:"resolved_by,omitempty" dynamodbav:"resolved_by,omitempty"`
+	ResolvedAt     time.Time `json:"resolved_at,omitempty" dynamodbav:"resolved_at,omitempty"`
 }
 
 // NewDistributionList creates a new distribution list
 func NewDistributionList(listName, quality string, eligibleMembers []string, createdBy string) *DistributionList {
 	return &DistributionList{
-		ListID:          generateListID(),
+		ListID:          NewID(IDPrefixList),
 		ListName:        listName,
 		Quality:         quality,
 		EligibleMembers: eligibleMembers,
@@ -80,6 +161,48 @@ func (dl *DistributionList) RemoveMember(memberID string) {
 	}
 }
 
+// RestoreMember puts a member back on the eligible list after their
+// distribution was reversed, and marks their confirmed draw as reversed
+func (dl *DistributionList) RestoreMember(memberID, reason, by string) {
+	if !dl.HasMember(memberID) {
+		dl.EligibleMembers = append(dl.EligibleMembers, memberID)
+	}
+	for i := len(dl.Draws) - 1; i >= 0; i-- {
+		draw := &dl.Draws[i]
+		if draw.MemberID == memberID && draw.Outcome == DrawConfirmed {
+			draw.resolve(DrawReversed, reason, by)
+			break
+		}
+	}
+}
+
+// CompleteReservedDraw confirms a member's reserved draw once the link is handed over
+func (dl *DistributionList) CompleteReservedDraw(memberID, by string) {
+	if draw := dl.reservedDraw(memberID); draw != nil {
+		draw.resolve(DrawConfirmed, "", by)
+	}
+}
+
+// ReleaseReservedDraw puts a member back on the eligible list when the link
+// held for them is released, and marks their reserved draw as expired
+func (dl *DistributionList) ReleaseReservedDraw(memberID, reason, by string) {
+	if !dl.HasMember(memberID) {
+		dl.EligibleMembers = append(dl.EligibleMembers, memberID)
+	}
+	if draw := dl.reservedDraw(memberID); draw != nil {
+		draw.resolve(DrawExpired, reason, by)
+	}
+}
+
+func (dl *DistributionList) reservedDraw(memberID string) *DrawRecord {
+	for i := len(dl.Draws) - 1; i >= 0; i-- {
+		if dl.Draws[i].MemberID == memberID && dl.Draws[i].Outcome == DrawReserved {
+			return &dl.Draws[i]
+		}
+	}
+	return nil
+}
+
 // HasMember checks if a member is in the eligible list
 func (dl *DistributionList) HasMember(memberID string) bool {
 	for _, id := range dl.EligibleMembers {
@@ -95,7 +218,160 @@ func (dl *DistributionList) GetMemberCount() int {
 	return len(dl.EligibleMembers)
 }
 
-// generateListID creates a unique ID for a distribution list
-func generateListID() string {
-	return "list_" + time.Now().Format("20060102150405")
+// PickRandomMember returns a random eligible member ID, skipping excluded IDs
+func (dl *DistributionList) PickRandomMember(exclude []string) (string, bool) {
+	return dl.PickWeightedMember(exclude, nil)
+}
+
+// PickWeightedMember is PickRandomMember where each member has one entry plus
+// any extra entries given, e.g. the donor bonus
+func (dl *DistributionList) PickWeightedMember(exclude []string, extraEntries map[string]int) (string, bool) {
+	var candidates []string
+	for _, id := range dl.EligibleMembers {
+		if containsString(exclude, id) {
+			continue
+		}
+		for n := 0; n <= extraEntries[id]; n++ {
+			candidates = append(candidates, id)
+		}
+	}
+	if len(candidates) == 0 {
+		return "", false
+	}
+	return candidates[rand.Intn(len(candidates))], true
+}
+
+// MergeEntries adds up extra draw entries from several sources, e.g. the
+// donor and attendance bonuses; nil maps are skipped
+func MergeEntries(sources ...map[string]int) map[string]int {
+	var merged map[string]int
+	for _, source := range sources {
+		for id, n := range source {
+			if merged == nil {
+				merged = make(map[string]int)
+			}
+			merged[id] += n
+		}
+	}
+	return merged
+}
+
+// RecordDraw appends a pending draw for a member, cancelling any draw still pending
+func (dl *DistributionList) RecordDraw(memberID, memberUsername, linkType, drawnBy string) *DrawRecord {
+	if pending := dl.PendingDraw(); pending != nil {
+		pending.resolve(DrawCancelled, "superseded by a new draw", drawnBy)
+	}
+	dl.Draws = append(dl.Draws, DrawRecord{
+		MemberID:       memberID,
+		MemberUsername: memberUsername,
+		LinkType:       linkType,
+		Outcome:        DrawPending,
+		DrawnBy:        drawnBy,
+		DrawnAt:        time.Now(),
+	})
+	return &dl.Draws[len(dl.Draws)-1]
+}
+
+// PendingDraw returns the draw awaiting a decision, or nil if there is none
+func (dl *DistributionList) PendingDraw() *DrawRecord {
+	if len(dl.Draws) == 0 {
+		return nil
+	}
+	last := &dl.Draws[len(dl.Draws)-1]
+	if last.Outcome != DrawPending {
+		return nil
+	}
+	return last
+}
+
+// ResolvePendingDraw sets the outcome of the pending draw for a member
+func (dl *DistributionList) ResolvePendingDraw(memberID, outcome, reason, resolvedBy string) bool {
+	pending := dl.PendingDraw()
+	if pending == nil || pending.MemberID != memberID {
+		return false
+	}
+	pending.resolve(outcome, reason, resolvedBy)
+	return true
+}
+
+// AbsentMembers returns members re-rolled since the last confirmed or cancelled draw
+func (dl *DistributionList) AbsentMembers() []string {
+	var absent []string
+	for i := len(dl.Draws) - 1; i >= 0; i-- {
+		draw := dl.Draws[i]
+		if draw.Outcome == DrawConfirmed || draw.Outcome == DrawReserved || draw.Outcome == DrawCancelled {
+			break
+		}
+		if draw.Outcome == DrawRerolled {
+			absent = append(absent, draw.MemberID)
+		}
+	}
+	return absent
+}
+
+func (d *DrawRecord) resolve(outcome, reason, resolvedBy string) {
+	d.Outcome = outcome
+	d.Reason = reason
+	d.ResolvedBy = resolvedBy
+	d.ResolvedAt = time.Now()
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// ReverseDistribution undoes a distribution given to the wrong member. The
+// link goes back to stock, or to newMember when one is given, in which case
+// the returned distribution records the new hand-out. The original member is
+// restored to list, if the distribution came from one. Nothing is changed if
+// an error is returned.
+func ReverseDistribution(original *Distribution, link *InventoryLink, list *DistributionList, newMember *Member, reason, method, by string) (*Distribution, error) {
+	if original.IsReversed() {
+		return nil, fmt.Errorf("distribution was already reversed")
+	}
+	if reason == "" {
+		return nil, fmt.Errorf("a reason is required to reverse a distribution")
+	}
+	if link.LinkID != original.LinkID {
+		return nil, fmt.Errorf("link %s does not belong to this distribution", link.LinkID)
+	}
+	if link.Status != LinkStatusDistributed {
+		return nil, fmt.Errorf("link is %s, not distributed", link.Status)
+	}
+
+	var replacement *Distribution
+	if newMember != nil {
+		if newMember.DiscordID == original.MemberID {
+			return nil, fmt.Errorf("link is already assigned to %s", newMember.Username)
+		}
+		replacement = NewDistribution(newMember.DiscordID, newMember.Username, link.LinkID, link.LinkType, link.Quality, link.Bonus, method, by)
+		replacement.Notes = fmt.Sprintf("Reassigned from %s: %s", original.MemberUsername, reason)
+		replacement.Character = newMember.PrimaryCharacter()
+		if err := link.Reassign(reason, newMember.Username, by); err != nil {
+			return nil, err
+		}
+	} else if err := link.ReturnToStock(reason, by); err != nil {
+		return nil, err
+	}
+
+	var reassignedTo string
+	if replacement != nil {
+		reassignedTo = replacement.DistributionID
+	}
+	original.Reverse(reason, reassignedTo, by)
+
+	if list != nil {
+		list.RestoreMember(original.MemberID, reason, by)
+		if replacement != nil && list.HasMember(replacement.MemberID) {
+			list.RemoveMember(replacement.MemberID)
+			replacement.ListID = list.ListID
+		}
+	}
+
+	return replacement, nil
 }
@@ -0,0 +1,155 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDrawTransitions(t *testing.T) {
+	tests := []struct {
+		name        string
+		steps       func(list *DistributionList)
+		wantOutcome []string
+		wantMembers []string
+		wantAbsent  []string
+		wantPending string // member of the pending draw, "" for none
+	}{
+		{
+			name:        "drawn",
+			steps:       func(list *DistributionList) { list.RecordDraw("1", "alice", "", "officer") },
+			wantOutcome: []string{DrawPending},
+			wantMembers: []string{"1", "2", "3"},
+			wantPending: "1",
+		},
+		{
+			name: "confirmed",
+			steps: func(list *DistributionList) {
+				list.RecordDraw("1", "alice", "", "officer")
+				list.RemoveMember("1")
+				list.ResolvePendingDraw("1", DrawConfirmed, "", "officer")
+			},
+			wantOutcome: []string{DrawConfirmed},
+			wantMembers: []string{"2", "3"},
+		},
+		{
+			name: "re-rolled twice",
+			steps: func(list *DistributionList) {
+				list.RecordDraw("1", "alice", "", "officer")
+				list.ResolvePendingDraw("1", DrawRerolled, "offline", "officer")
+				list.RecordDraw("2", "bob", "", "officer")
+				list.ResolvePendingDraw("2", DrawRerolled, "offline", "officer")
+				list.RecordDraw("3", "carol", "", "officer")
+			},
+			wantOutcome: []string{DrawRerolled, DrawRerolled, DrawPending},
+			wantMembers: []string{"1", "2", "3"},
+			wantAbsent:  []string{"2", "1"},
+			wantPending: "3",
+		},
+		{
+			name: "new draw supersedes the pending one",
+			steps: func(list *DistributionList) {
+				list.RecordDraw("1", "alice", "", "officer")
+				list.RecordDraw("2", "bob", "", "officer")
+			},
+			wantOutcome: []string{DrawCancelled, DrawPending},
+			wantMembers: []string{"1", "2", "3"},
+			wantPending: "2",
+		},
+		{
+			name: "resolving another member's draw is ignored",
+			steps: func(list *DistributionList) {
+				list.RecordDraw("1", "alice", "", "officer")
+				list.ResolvePendingDraw("2", DrawConfirmed, "", "officer")
+			},
+			wantOutcome: []string{DrawPending},
+			wantMembers: []string{"1", "2", "3"},
+			wantPending: "1",
+		},
+		{
+			name: "reserved then handed over",
+			steps: func(list *DistributionList) {
+				list.RecordDraw("2", "bob", "", "officer")
+				list.RemoveMember("2")
+				list.ResolvePendingDraw("2", DrawReserved, "", "officer")
+				list.CompleteReservedDraw("2", "officer")
+			},
+			wantOutcome: []string{DrawConfirmed},
+			wantMembers: []string{"1", "3"},
+		},
+		{
+			name: "reservation released",
+			steps: func(list *DistributionList) {
+				list.RecordDraw("2", "bob", "", "officer")
+				list.RemoveMember("2")
+				list.ResolvePendingDraw("2", DrawReserved, "", "officer")
+				list.ReleaseReservedDraw("2", "expired", "scheduler")
+			},
+			wantOutcome: []string{DrawExpired},
+			wantMembers: []string{"1", "3", "2"},
+		},
+		{
+			name: "confirmed then reversed",
+			steps: func(list *DistributionList) {
+				list.RecordDraw("1", "alice", "", "officer")
+				list.RemoveMember("1")
+				list.ResolvePendingDraw("1", DrawConfirmed, "", "officer")
+				list.RestoreMember("1", "wrong member", "officer")
+			},
+			wantOutcome: []string{DrawReversed},
+			wantMembers: []string{"2", "3", "1"},
+		},
+		{
+			name: "cancelled draw ends the absent run",
+			steps: func(list *DistributionList) {
+				list.RecordDraw("1", "alice", "", "officer")
+				list.ResolvePendingDraw("1", DrawRerolled, "offline", "officer")
+				list.RecordDraw("2", "bob", "", "officer")
+				list.ResolvePendingDraw("2", DrawCancelled, "", "officer")
+			},
+			wantOutcome: []string{DrawRerolled, DrawCancelled},
+			wantMembers: []string{"1", "2", "3"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list := NewDistributionList("Gold Links", QualityGold, []string{"1", "2", "3"}, "officer")
+			tt.steps(list)
+
+			var outcomes []string
+			for _, draw := range list.Draws {
+				outcomes = append(outcomes, draw.Outcome)
+			}
+			if !reflect.DeepEqual(outcomes, tt.wantOutcome) {
+				t.Errorf("outcomes = %v, want %v", outcomes, tt.wantOutcome)
+			}
+			if !reflect.DeepEqual(list.EligibleMembers, tt.wantMembers) {
+				t.Errorf("EligibleMembers = %v, want %v", list.EligibleMembers, tt.wantMembers)
+			}
+			if absent := list.AbsentMembers(); !reflect.DeepEqual(absent, tt.wantAbsent) {
+				t.Errorf("AbsentMembers = %v, want %v", absent, tt.wantAbsent)
+			}
+			pending := ""
+			if draw := list.PendingDraw(); draw != nil {
+				pending = draw.MemberID
+			}
+			if pending != tt.wantPending {
+				t.Errorf("PendingDraw = %q, want %q", pending, tt.wantPending)
+			}
+		})
+	}
+}
+
+func TestPickWeightedMember(t *testing.T) {
+	list := NewDistributionList("Gold Links", QualityGold, []string{"1", "2", "3"}, "officer")
+
+	if _, ok := list.PickWeightedMember([]string{"1", "2", "3"}, nil); ok {
+		t.Error("picked a member with everyone excluded")
+	}
+	for n := 0; n < 50; n++ {
+		id, ok := list.PickWeightedMember([]string{"1", "3"}, map[string]int{"1": 5, "2": 1})
+		if !ok || id != "2" {
+			t.Fatalf("PickWeightedMember = %q, %v, want the only candidate 2", id, ok)
+		}
+	}
+}
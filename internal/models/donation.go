@@ -0,0 +1,80 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SetDonor credits a link to the member who donated it
+func (l *InventoryLink) SetDonor(memberID, memberUsername string) {
+	l.DonatedBy = memberID
+	l.DonorName = memberUsername
+}
+
+// CountsAsDonation returns true if the link is credited to a donor. Links
+// retired as added by mistake never existed, so they don't count.
+func (l *InventoryLink) CountsAsDonation() bool {
+	return l.DonatedBy != "" && l.RetireReason != RetireMistake
+}
+
+// DonorBonus gives members who donated links extra entries in draws
+type DonorBonus struct {
+	EntriesPerLink int `json:"entries_per_link"` // extra entries per donated link; 0 disables the bonus
+	MaxEntries     int `json:"max_entries"`      // cap on extra entries per member; 0 means no cap
+	WindowDays     int `json:"window_days"`      // only donations this recent count; 0 counts all
+}
+
+// ParseDonorBonus reads the bonus settings from their environment variable
+// values; empty values keep the defaults (disabled, at most 5 extra entries,
+// donations of the last 90 days)
+func ParseDonorBonus(entriesPerLink, maxEntries, windowDays string) (DonorBonus, error) {
+	bonus := DonorBonus{MaxEntries: 5, WindowDays: 90}
+	for _, setting := range []struct {
+		name  string
+		value string
+		dest  *int
+	}{
+		{"entries per link", entriesPerLink, &bonus.EntriesPerLink},
+		{"max entries", maxEntries, &bonus.MaxEntries},
+		{"window days", windowDays, &bonus.WindowDays},
+	} {
+		if strings.TrimSpace(setting.value) == "" {
+			continue
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(setting.value))
+		if err != nil || n < 0 {
+			return DonorBonus{}, fmt.Errorf("invalid donor bonus %s %q", setting.name, setting.value)
+		}
+		*setting.dest = n
+	}
+	return bonus, nil
+}
+
+// Enabled returns true if donors get extra draw entries
+func (b DonorBonus) Enabled() bool {
+	return b.EntriesPerLink > 0
+}
+
+// Entries returns the extra draw entries each donor has earned
+func (b DonorBonus) Entries(links []*InventoryLink, now time.Time) map[string]int {
+	if !b.Enabled() {
+		return nil
+	}
+
+	entries := make(map[string]int)
+	for _, link := range links {
+		if !link.CountsAsDonation() {
+			continue
+		}
+		if b.WindowDays > 0 && link.AddedDate.Before(now.AddDate(0, 0, -b.WindowDays)) {
+			continue
+		}
+		entries[link.DonatedBy] += b.EntriesPerLink
+		if b.MaxEntries > 0 && entries[link.DonatedBy] > b.MaxEntries {
+			entries[link.DonatedBy] = b.MaxEntries
+		}
+	}
+	return entries
+}
@@ -0,0 +1,205 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Guild event kinds
+const (
+	EventKindBoss  = "boss"  // boss runs
+	EventKindSiege = "siege" // sieges and other PvP
+	EventKindOther = "other"
+)
+
+// AllEventKinds lists every event kind in display order
+var AllEventKinds = []string{EventKindBoss, EventKindSiege, EventKindOther}
+
+// Event is a guild event whose attendance officers record
+type Event struct {
+	EventID   string    `json:"event_id" dynamodbav:"event_id"`
+	Name      string    `json:"name" dynamodbav:"name"` // e.g., "Friday Inferno run"
+	Kind      string    `json:"kind" dynamodbav:"kind"` // boss, siege or other
+	StartsAt  time.Time `json:"starts_at" dynamodbav:"starts_at"`
+	Attendees []string  `json:"attendees" dynamodbav:"attendees,stringset,omitempty"` // Discord IDs of members who attended
+	CreatedBy string    `json:"created_by" dynamodbav:"created_by"`
+	CreatedAt time.Time `json:"created_at" dynamodbav:"created_at"`
+}
+
+// NewEvent creates an event with no attendees
+func NewEvent(name, kind string, startsAt time.Time, createdBy string) *Event {
+	return &Event{
+		EventID:   NewID(IDPrefixEvent),
+		Name:      name,
+		Kind:      kind,
+		StartsAt:  startsAt,
+		CreatedBy: createdBy,
+		CreatedAt: time.Now(),
+	}
+}
+
+// IsValidEventKind returns true for a known event kind
+func IsValidEventKind(kind string) bool {
+	return containsString(AllEventKinds, kind)
+}
+
+// Attended returns true if a member attended the event
+func (e *Event) Attended(memberID string) bool {
+	return containsString(e.Attendees, memberID)
+}
+
+// AddAttendees records members as attending and returns the IDs that weren't
+// recorded already
+func (e *Event) AddAttendees(memberIDs []string) []string {
+	var added []string
+	for _, id := range memberIDs {
+		if id == "" || e.Attended(id) || containsString(added, id) {
+			continue
+		}
+		added = append(added, id)
+	}
+	e.Attendees = append(e.Attendees, added...)
+	return added
+}
+
+// RemoveAttendee removes a member from the attendees, returning false if they
+// weren't recorded
+func (e *Event) RemoveAttendee(memberID string) bool {
+	for i, id := range e.Attendees {
+		if id == memberID {
+			e.Attendees = append(e.Attendees[:i], e.Attendees[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// AttendancePolicy makes event attendance count towards eligibility and draws
+type AttendancePolicy struct {
+	WindowDays      int `json:"window_days"`       // only events this recent count; 0 counts all
+	MinEvents       int `json:"min_events"`        // events a member must attend to be eligible; 0 disables the requirement
+	EntriesPerEvent int `json:"entries_per_event"` // extra draw entries per event attended; 0 disables the bonus
+	MaxEntries      int `json:"max_entries"`       // cap on extra entries per member; 0 means no cap
+}
+
+// ParseAttendancePolicy reads the policy from its environment variable
+// values; empty values keep the defaults (events of the last 30 days, no
+// requirement, no bonus, at most 5 extra entries)
+func ParseAttendancePolicy(windowDays, minEvents, entriesPerEvent, maxEntries string) (AttendancePolicy, error) {
+	policy := AttendancePolicy{WindowDays: 30, MaxEntries: 5}
+	for _, setting := range []struct {
+		name  string
+		value string
+		dest  *int
+	}{
+		{"window days", windowDays, &policy.WindowDays},
+		{"min events", minEvents, &policy.MinEvents},
+		{"entries per event", entriesPerEvent, &policy.EntriesPerEvent},
+		{"max entries", maxEntries, &policy.MaxEntries},
+	} {
+		if strings.TrimSpace(setting.value) == "" {
+			continue
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(setting.value))
+		if err != nil || n < 0 {
+			return AttendancePolicy{}, fmt.Errorf("invalid attendance %s %q", setting.name, setting.value)
+		}
+		*setting.dest = n
+	}
+	return policy, nil
+}
+
+// RequiresAttendance returns true if members must attend events to be eligible
+func (p AttendancePolicy) RequiresAttendance() bool {
+	return p.MinEvents > 0
+}
+
+// BonusEnabled returns true if attendees get extra draw entries
+func (p AttendancePolicy) BonusEnabled() bool {
+	return p.EntriesPerEvent > 0
+}
+
+// Since returns the start of the attendance window, or the zero time if all
+// events count
+func (p AttendancePolicy) Since(now time.Time) time.Time {
+	if p.WindowDays <= 0 {
+		return time.Time{}
+	}
+	return now.AddDate(0, 0, -p.WindowDays)
+}
+
+// Counts returns how many events in the window each member attended. Events
+// that haven't started yet don't count.
+func (p AttendancePolicy) Counts(events []*Event, now time.Time) map[string]int {
+	return AttendanceCounts(events, p.Since(now), now)
+}
+
+// AttendanceCounts returns how many events starting in [from, to] each member attended
+func AttendanceCounts(events []*Event, from, to time.Time) map[string]int {
+	counts := make(map[string]int)
+	for _, event := range events {
+		if event.StartsAt.Before(from) || event.StartsAt.After(to) {
+			continue
+		}
+		for _, id := range event.Attendees {
+			counts[id]++
+		}
+	}
+	return counts
+}
+
+// FilterByAttendance returns the members who attended at least MinEvents
+func (p AttendancePolicy) FilterByAttendance(members []*Member, counts map[string]int) []*Member {
+	if !p.RequiresAttendance() {
+		return members
+	}
+	var attended []*Member
+	for _, member := range members {
+		if counts[member.DiscordID] >= p.MinEvents {
+			attended = append(attended, member)
+		}
+	}
+	return attended
+}
+
+// Entries returns the extra draw entries each member has earned by attending
+func (p AttendancePolicy) Entries(counts map[string]int) map[string]int {
+	if !p.BonusEnabled() {
+		return nil
+	}
+	entries := make(map[string]int)
+	for id, count := range counts {
+		entries[id] = count * p.EntriesPerEvent
+		if p.MaxEntries > 0 && entries[id] > p.MaxEntries {
+			entries[id] = p.MaxEntries
+		}
+	}
+	return entries
+}
+
+// Describe summarises the policy, e.g. "at least 2 events in the last 30 days
+// to be eligible; 1 extra draw entry per event (up to 5)"
+func (p AttendancePolicy) Describe() string {
+	window := "ever"
+	if p.WindowDays > 0 {
+		window = fmt.Sprintf("in the last %d days", p.WindowDays)
+	}
+
+	var parts []string
+	if p.RequiresAttendance() {
+		parts = append(parts, fmt.Sprintf("at least %d events %s to be eligible", p.MinEvents, window))
+	}
+	if p.BonusEnabled() {
+		bonus := fmt.Sprintf("%d extra draw entries per event attended %s", p.EntriesPerEvent, window)
+		if p.MaxEntries > 0 {
+			bonus += fmt.Sprintf(" (up to %d)", p.MaxEntries)
+		}
+		parts = append(parts, bonus)
+	}
+	if len(parts) == 0 {
+		return "attendance doesn't affect eligibility or draws"
+	}
+	return strings.Join(parts, "; ")
+}
@@ -0,0 +1,157 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MaxFusionInputs limits the links consumed by one fusion so the whole
+// conversion fits in a single transaction
+const MaxFusionInputs = 20
+
+// NextQuality returns the quality links are fused into, or false for gold
+func NextQuality(quality string) (string, bool) {
+	switch quality {
+	case QualityBronze:
+		return QualitySilver, true
+	case QualitySilver:
+		return QualityGold, true
+	}
+	return "", false
+}
+
+// Fuse consumes available links of one quality and returns the new link of
+// the next quality up. The inputs are marked fused and point at the result,
+// which lists its inputs, so the conversion can be traced both ways. The
+// category and bonus are those of linkType at the result quality.
+func Fuse(inputs []*InventoryLink, linkType, category, bonus, note, by string) (*InventoryLink, error) {
+	if len(inputs) < 2 {
+		return nil, fmt.Errorf("fusing needs at least 2 links")
+	}
+	if len(inputs) > MaxFusionInputs {
+		return nil, fmt.Errorf("at most %d links can be fused at once", MaxFusionInputs)
+	}
+
+	quality := inputs[0].Quality
+	seen := make(map[string]bool)
+	for _, link := range inputs {
+		if seen[link.LinkID] {
+			return nil, fmt.Errorf("link %s is listed twice", link.LinkID)
+		}
+		seen[link.LinkID] = true
+		if link.Status != LinkStatusAvailable {
+			return nil, fmt.Errorf("only available links can be fused (link %s is %s)", link.LinkID, link.Status)
+		}
+		if link.Quality != quality {
+			return nil, fmt.Errorf("all fused links must be the same quality (found %s and %s)", quality, link.Quality)
+		}
+	}
+
+	resultQuality, ok := NextQuality(quality)
+	if !ok {
+		return nil, fmt.Errorf("%s links can't be fused into a higher quality", quality)
+	}
+
+	result := NewInventoryLink(linkType, resultQuality, category, bonus, by)
+	result.Notes = note
+	for _, link := range inputs {
+		result.FusedFrom = append(result.FusedFrom, link.LinkID)
+	}
+	result.record(LinkEvent{Action: LinkEventFusionResult, Note: fmt.Sprintf("from %d %s links", len(inputs), quality), By: by})
+
+	for _, link := range inputs {
+		link.IsAvailable = "false"
+		link.Status = LinkStatusFused
+		link.FusedInto = result.LinkID
+		link.record(LinkEvent{Action: LinkEventFused, Note: "into " + result.LinkID, By: by})
+	}
+
+	return result, nil
+}
+
+// IsFusionResult returns true if the link was made by fusing other links
+func (l *InventoryLink) IsFusionResult() bool {
+	return len(l.FusedFrom) > 0
+}
+
+// QualityLedger accounts for every link of one quality: links come in by
+// being added or fused from lower links, and are in exactly one status
+type QualityLedger struct {
+	Added       int `json:"added"`    // entered into inventory
+	FusedIn     int `json:"fused_in"` // created by fusing lower-quality links
+	Available   int `json:"available"`
+	Reserved    int `json:"reserved"`
+	Distributed int `json:"distributed"`
+	Retired     int `json:"retired"`
+	FusedAway   int `json:"fused_away"` // consumed to make a higher-quality link
+}
+
+// Balanced returns true if every link that came in is accounted for
+func (q QualityLedger) Balanced() bool {
+	return q.Added+q.FusedIn == q.Available+q.Reserved+q.Distributed+q.Retired+q.FusedAway
+}
+
+// InventoryLedger reconciles inventory per quality
+type InventoryLedger map[string]*QualityLedger
+
+// ReconcileInventory builds the ledger of all links, including fusion inputs
+// and results, so reports can show where every link went
+func ReconcileInventory(links []*InventoryLink) InventoryLedger {
+	ledger := make(InventoryLedger)
+	for _, link := range links {
+		entry := ledger[link.Quality]
+		if entry == nil {
+			entry = &QualityLedger{}
+			ledger[link.Quality] = entry
+		}
+
+		if link.IsFusionResult() {
+			entry.FusedIn++
+		} else {
+			entry.Added++
+		}
+
+		switch link.Status {
+		case LinkStatusAvailable:
+			entry.Available++
+		case LinkStatusReserved:
+			entry.Reserved++
+		case LinkStatusDistributed:
+			entry.Distributed++
+		case LinkStatusRetired:
+			entry.Retired++
+		case LinkStatusFused:
+			entry.FusedAway++
+		}
+	}
+	return ledger
+}
+
+// Describe renders a ledger line such as "12 in (2 fused) → 5 available,
+// 4 distributed, 3 fused away"
+func (q QualityLedger) Describe() string {
+	in := fmt.Sprintf("%d in", q.Added+q.FusedIn)
+	if q.FusedIn > 0 {
+		in += fmt.Sprintf(" (%d fused)", q.FusedIn)
+	}
+
+	var out []string
+	for _, part := range []struct {
+		count int
+		label string
+	}{
+		{q.Available, "available"},
+		{q.Reserved, "reserved"},
+		{q.Distributed, "distributed"},
+		{q.Retired, "retired"},
+		{q.FusedAway, "fused away"},
+	} {
+		if part.count > 0 {
+			out = append(out, fmt.Sprintf("%d %s", part.count, part.label))
+		}
+	}
+	if len(out) == 0 {
+		return in
+	}
+	return in + " → " + strings.Join(out, ", ")
+}
@@ -0,0 +1,127 @@
+package models
+
+import (
+	"strings"
+	"testing"
+)
+
+func fusionInput(id, quality, status string) *InventoryLink {
+	return &InventoryLink{LinkID: id, LinkType: "Melee Damage", Quality: quality, Status: status, IsAvailable: "true"}
+}
+
+func TestFuse(t *testing.T) {
+	tests := []struct {
+		name        string
+		inputs      []*InventoryLink
+		wantQuality string
+		wantErr     string
+	}{
+		{
+			name:        "bronze into silver",
+			inputs:      []*InventoryLink{fusionInput("a", QualityBronze, LinkStatusAvailable), fusionInput("b", QualityBronze, LinkStatusAvailable)},
+			wantQuality: QualitySilver,
+		},
+		{
+			name:        "silver into gold",
+			inputs:      []*InventoryLink{fusionInput("a", QualitySilver, LinkStatusAvailable), fusionInput("b", QualitySilver, LinkStatusAvailable), fusionInput("c", QualitySilver, LinkStatusAvailable)},
+			wantQuality: QualityGold,
+		},
+		{
+			name:    "one link",
+			inputs:  []*InventoryLink{fusionInput("a", QualityBronze, LinkStatusAvailable)},
+			wantErr: "at least 2",
+		},
+		{
+			name:    "gold",
+			inputs:  []*InventoryLink{fusionInput("a", QualityGold, LinkStatusAvailable), fusionInput("b", QualityGold, LinkStatusAvailable)},
+			wantErr: "can't be fused",
+		},
+		{
+			name:    "mixed qualities",
+			inputs:  []*InventoryLink{fusionInput("a", QualityBronze, LinkStatusAvailable), fusionInput("b", QualitySilver, LinkStatusAvailable)},
+			wantErr: "same quality",
+		},
+		{
+			name:    "reserved input",
+			inputs:  []*InventoryLink{fusionInput("a", QualityBronze, LinkStatusAvailable), fusionInput("b", QualityBronze, LinkStatusReserved)},
+			wantErr: "only available",
+		},
+		{
+			name:    "same link twice",
+			inputs:  []*InventoryLink{fusionInput("a", QualityBronze, LinkStatusAvailable), fusionInput("a", QualityBronze, LinkStatusAvailable)},
+			wantErr: "listed twice",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Fuse(tt.inputs, "Melee Damage", CategoryMelee, "3.75%", "", "officer")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Fuse error = %v, want one mentioning %q", err, tt.wantErr)
+				}
+				for _, input := range tt.inputs {
+					if input.Status == LinkStatusFused {
+						t.Errorf("input %s was fused by a failed fusion", input.LinkID)
+					}
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Fuse: %v", err)
+			}
+
+			if result.Quality != tt.wantQuality || result.Status != LinkStatusAvailable || len(result.FusedFrom) != len(tt.inputs) {
+				t.Errorf("result = %s %s from %v, want available %s from %d links", result.Status, result.Quality, result.FusedFrom, tt.wantQuality, len(tt.inputs))
+			}
+			for _, input := range tt.inputs {
+				if input.Status != LinkStatusFused || input.IsAvailable != "false" || input.FusedInto != result.LinkID {
+					t.Errorf("input %s = %s into %q, want fused into %s", input.LinkID, input.Status, input.FusedInto, result.LinkID)
+				}
+			}
+		})
+	}
+}
+
+func TestReconcileInventory(t *testing.T) {
+	inputs := []*InventoryLink{fusionInput("a", QualityBronze, LinkStatusAvailable), fusionInput("b", QualityBronze, LinkStatusAvailable)}
+	result, err := Fuse(inputs, "Melee Damage", CategoryMelee, "3.75%", "", "officer")
+	if err != nil {
+		t.Fatalf("Fuse: %v", err)
+	}
+	links := append(inputs, result,
+		fusionInput("c", QualityBronze, LinkStatusAvailable),
+		fusionInput("d", QualitySilver, LinkStatusDistributed),
+		fusionInput("e", QualitySilver, LinkStatusReserved),
+		fusionInput("f", QualityGold, LinkStatusRetired),
+	)
+	ledger := ReconcileInventory(links)
+
+	tests := []struct {
+		quality  string
+		want     QualityLedger
+		describe string
+	}{
+		{QualityBronze, QualityLedger{Added: 3, Available: 1, FusedAway: 2}, "3 in → 1 available, 2 fused away"},
+		{QualitySilver, QualityLedger{Added: 2, FusedIn: 1, Available: 1, Reserved: 1, Distributed: 1}, "3 in (1 fused) → 1 available, 1 reserved, 1 distributed"},
+		{QualityGold, QualityLedger{Added: 1, Retired: 1}, "1 in → 1 retired"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.quality, func(t *testing.T) {
+			got := ledger[tt.quality]
+			if got == nil || *got != tt.want {
+				t.Fatalf("ledger = %+v, want %+v", got, tt.want)
+			}
+			if !got.Balanced() {
+				t.Error("ledger is not balanced")
+			}
+			if d := got.Describe(); d != tt.describe {
+				t.Errorf("Describe = %q, want %q", d, tt.describe)
+			}
+		})
+	}
+
+	if (QualityLedger{Added: 2, Available: 1}).Balanced() {
+		t.Error("a ledger with a missing link is balanced")
+	}
+}
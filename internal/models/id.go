@@ -0,0 +1,109 @@
+package models
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"sync"
+	"time"
+)
+
+// ID prefixes for each kind of record
+const (
+	IDPrefixLink         = "link_"
+	IDPrefixDistribution = "dist_"
+	IDPrefixList         = "list_"
+	IDPrefixSchedule     = "sched_"
+	IDPrefixEvent        = "event_"
+)
+
+// crockford is the Crockford base32 alphabet used by ULIDs
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+var (
+	idMu      sync.Mutex
+	idLastMs  uint64
+	idLastHi  uint16 // high 16 bits of the 80-bit random part
+	idLastLow uint64 // low 64 bits of the 80-bit random part
+)
+
+// NewID returns a prefix followed by a ULID: a 48-bit millisecond timestamp and
+// 80 random bits in Crockford base32. IDs sort by creation time, and IDs made
+// in the same millisecond by this process increment the random part so they
+// stay unique and ordered.
+func NewID(prefix string) string {
+	return prefix + newULID(time.Now())
+}
+
+// DerivedID returns a prefix followed by a ULID whose time part is at and whose
+// 80 other bits are a hash of seed, so the same seed always gives the same ID.
+// Imports use it to give a record a new ID that a later run can recognise.
+func DerivedID(prefix string, at time.Time, seed string) string {
+	var ms uint64
+	if at.UnixMilli() > 0 {
+		ms = uint64(at.UnixMilli()) & (1<<48 - 1)
+	}
+	sum := sha256.Sum256([]byte(seed))
+
+	var b [16]byte
+	b[0] = byte(ms >> 40)
+	b[1] = byte(ms >> 32)
+	b[2] = byte(ms >> 24)
+	b[3] = byte(ms >> 16)
+	b[4] = byte(ms >> 8)
+	b[5] = byte(ms)
+	copy(b[6:], sum[:10])
+
+	return prefix + encodeCrockford(b)
+}
+
+func newULID(now time.Time) string {
+	ms := uint64(now.UnixMilli())
+
+	idMu.Lock()
+	if ms <= idLastMs {
+		// Same millisecond (or the clock went back): increment the last value
+		ms = idLastMs
+		idLastLow++
+		if idLastLow == 0 {
+			idLastHi++
+		}
+	} else {
+		var random [10]byte
+		if _, err := rand.Read(random[:]); err != nil {
+			panic("models: cannot read random bytes: " + err.Error())
+		}
+		idLastMs = ms
+		idLastHi = binary.BigEndian.Uint16(random[:2])
+		idLastLow = binary.BigEndian.Uint64(random[2:])
+	}
+	hi, low := idLastHi, idLastLow
+	idMu.Unlock()
+
+	// 128 bits: 48-bit time, then 80 bits of randomness
+	var b [16]byte
+	b[0] = byte(ms >> 40)
+	b[1] = byte(ms >> 32)
+	b[2] = byte(ms >> 24)
+	b[3] = byte(ms >> 16)
+	b[4] = byte(ms >> 8)
+	b[5] = byte(ms)
+	binary.BigEndian.PutUint16(b[6:8], hi)
+	binary.BigEndian.PutUint64(b[8:], low)
+
+	return encodeCrockford(b)
+}
+
+// encodeCrockford encodes 128 bits as 26 base32 characters, most significant first
+func encodeCrockford(b [16]byte) string {
+	hi := binary.BigEndian.Uint64(b[:8])
+	low := binary.BigEndian.Uint64(b[8:])
+
+	var out [26]byte
+	for i := 25; i >= 0; i-- {
+		out[i] = crockford[low&31]
+		low = low>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(out[:])
+}
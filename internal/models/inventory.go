@@ -13,28 +13,90 @@ const (
 	QualityGold   = "gold"
 )
 
+// Link statuses
+const (
+	LinkStatusAvailable   = "available"
+	LinkStatusDistributed = "distributed"
+	LinkStatusRetired     = "retired"  // lost, sold, consumed or added by mistake
+	LinkStatusReserved    = "reserved" // held for a draw winner until handed over or expired
+	LinkStatusFused       = "fused"    // consumed to make a higher-quality link
+)
+
+// DefaultReservationDuration is how long a link is held for an offline winner
+const DefaultReservationDuration = 48 * time.Hour
+
+// Reasons a link can be retired
+const (
+	RetireLost     = "lost"
+	RetireSold     = "sold"
+	RetireConsumed = "consumed"
+	RetireMistake  = "mistake" // added to inventory by mistake
+)
+
+// AllRetireReasons lists the valid retire reasons
+var AllRetireReasons = []string{RetireLost, RetireSold, RetireConsumed, RetireMistake}
+
+// Link event actions
+const (
+	LinkEventNotes        = "notes_edited"
+	LinkEventRetired      = "retired"
+	LinkEventRestored     = "restored"
+	LinkEventReversed     = "distribution_reversed"
+	LinkEventReassigned   = "reassigned"
+	LinkEventReserved     = "reserved"
+	LinkEventReleased     = "reservation_released"
+	LinkEventFused        = "fused"         // consumed by a fusion
+	LinkEventFusionResult = "fusion_result" // created by a fusion
+)
+
+// LinkEvent records a change made to a link after it was added
+type LinkEvent struct {
+	Action string    `json:"action" dynamodbav:"action"`
+	Reason string    `json:"reason,omitempty" dynamodbav:"reason,omitempty"`
+	Note   string    `json:"note,omitempty" dynamodbav:"note,omitempty"`
+	By     string    `json:"by" dynamodbav:"by"`
+	At     time.Time `json:"at" dynamodbav:"at"`
+}
+
 // InventoryLink represents a single mastery link in inventory
 type InventoryLink struct {
-	LinkID      string    `json:"link_id" dynamodbav:"link_id"`           // unique ID for this specific link
-	LinkType    string    `json:"link_type" dynamodbav:"link_type"`       // e.g., "Melee Damage"
-	Quality     string    `json:"quality" dynamodbav:"quality"`           // bronze, silver, gold
-	Category    string    `json:"category" dynamodbav:"category"`         // e.g., "Melee Type Links"
-	Bonus       string    `json:"bonus" dynamodbav:"bonus"`               // e.g., "3.75%"
-	IsAvailable string    `json:"is_available" dynamodbav:"is_available"` // "true" if not distributed yet, "false" otherwise
-	AddedBy     string    `json:"added_by" dynamodbav:"added_by"`
-	AddedDate   time.Time `json:"added_date" dynamodbav:"added_date"`
-	Notes       string    `json:"notes" dynamodbav:"notes"` // optional notes about this specific link
+	LinkID       string      `json:"link_id" dynamodbav:"link_id"`                             // unique ID for this specific link
+	LinkType     string      `json:"link_type" dynamodbav:"link_type"`                         // e.g., "Melee Damage"
+	Quality      string      `json:"quality" dynamodbav:"quality"`                             // bronze, silver, gold
+	Category     string      `json:"category" dynamodbav:"category"`                           // e.g., "Melee"
+	Bonus        string      `json:"bonus" dynamodbav:"bonus"`                                 // e.g., "3.75%"
+	BonusValue   *BonusValue `json:"bonus_value,omitempty" dynamodbav:"bonus_value,omitempty"` // nil for custom "TBD" bonuses
+	IsAvailable  string      `json:"is_available" dynamodbav:"is_available"`                   // "true" if not distributed yet, "false" otherwise
+	AddedBy      string      `json:"added_by" dynamodbav:"added_by"`                           // officer who entered the link
+	DonatedBy    string      `json:"donated_by,omitempty" dynamodbav:"donated_by,omitempty"`   // Discord ID of the member who donated it, if known
+	DonorName    string      `json:"donor_name,omitempty" dynamodbav:"donor_name,omitempty"`
+	AddedDate    time.Time   `json:"added_date" dynamodbav:"added_date"`
+	Notes        string      `json:"notes" dynamodbav:"notes"`   // optional notes about this specific link
+	Status       string      `json:"status" dynamodbav:"status"` // available, reserved, distributed, retired or fused
+	RetireReason string      `json:"retire_reason,omitempty" dynamodbav:"retire_reason,omitempty"`
+	History      []LinkEvent `json:"history,omitempty" dynamodbav:"history,omitempty"`
+
+	FusedFrom []string `json:"fused_from,omitempty" dynamodbav:"fused_from,omitempty"` // links consumed to make this one
+	FusedInto string   `json:"fused_into,omitempty" dynamodbav:"fused_into,omitempty"` // link this one was fused into
+
+	ReservedFor         string    `json:"reserved_for,omitempty" dynamodbav:"reserved_for,omitempty"` // Discord ID of the winner the link is held for
+	ReservedForUsername string    `json:"reserved_for_username,omitempty" dynamodbav:"reserved_for_username,omitempty"`
+	ReservedUntil       time.Time `json:"reserved_until,omitempty" dynamodbav:"reserved_until,omitempty"`
+	ReservedListID      string    `json:"reserved_list_id,omitempty" dynamodbav:"reserved_list_id,omitempty"` // list the winner was drawn from
 }
 
 // NewInventoryLink creates a new individual mastery link
 func NewInventoryLink(linkType, quality, category, bonus, addedBy string) *InventoryLink {
+	bonus, bonusValue := NormalizeBonus(bonus)
 	return &InventoryLink{
-		LinkID:      generateLinkID(linkType, quality),
+		LinkID:      NewID(IDPrefixLink),
 		LinkType:    linkType,
 		Quality:     quality,
 		Category:    category,
 		Bonus:       bonus,
+		BonusValue:  bonusValue,
 		IsAvailable: "true",
+		Status:      LinkStatusAvailable,
 		AddedBy:     addedBy,
 		AddedDate:   time.Now(),
 		Notes:       "",
@@ -44,28 +106,129 @@ func NewInventoryLink(linkType, quality, category, bonus, addedBy string) *Inven
 // MarkDistributed marks this link as distributed (no longer available)
 func (l *InventoryLink) MarkDistributed() {
 	l.IsAvailable = "false"
+	l.Status = LinkStatusDistributed
+	l.clearReservation()
 }
 
 // MarkAvailable marks this link as available again
 func (l *InventoryLink) MarkAvailable() {
 	l.IsAvailable = "true"
+	l.Status = LinkStatusAvailable
+	l.RetireReason = ""
+	l.clearReservation()
 }
 
-// GetDisplayName returns a formatted display name for the link
-func (l *InventoryLink) GetDisplayName() string {
-	return fmt.Sprintf("%s %s (%s)", l.Quality, l.LinkType, l.Bonus)
+// Reserve holds an available link for a draw winner who can't receive it yet
+func (l *InventoryLink) Reserve(memberID, memberUsername, listID string, until time.Time, by string) error {
+	if l.Status != LinkStatusAvailable {
+		return fmt.Errorf("only available links can be reserved (this link is %s)", l.Status)
+	}
+	l.IsAvailable = "false"
+	l.Status = LinkStatusReserved
+	l.ReservedFor = memberID
+	l.ReservedForUsername = memberUsername
+	l.ReservedUntil = until
+	l.ReservedListID = listID
+	l.record(LinkEvent{Action: LinkEventReserved, Note: fmt.Sprintf("for %s until %s", memberUsername, until.UTC().Format(time.RFC1123)), By: by})
+	return nil
+}
+
+// ReservationExpired returns true if the link is reserved past its deadline
+func (l *InventoryLink) ReservationExpired(now time.Time) bool {
+	return l.Status == LinkStatusReserved && now.After(l.ReservedUntil)
+}
+
+// HandOver converts a reservation into a distribution to the reserved member.
+// The returned distribution keeps the list the winner was drawn from.
+func (l *InventoryLink) HandOver(method, by string) (*Distribution, error) {
+	if l.Status != LinkStatusReserved {
+		return nil, fmt.Errorf("only reserved links can be handed over (this link is %s)", l.Status)
+	}
+	distribution := NewDistribution(l.ReservedFor, l.ReservedForUsername, l.LinkID, l.LinkType, l.Quality, l.Bonus, method, by)
+	distribution.ListID = l.ReservedListID
+	l.MarkDistributed()
+	return distribution, nil
 }
 
-// generateLinkID creates a unique ID for a link (sanitized for DynamoDB)
-func generateLinkID(linkType, quality string) string {
-	// Sanitize linkType by replacing spaces and special characters with underscores
-	sanitizedLinkType := strings.ReplaceAll(linkType, " ", "_")
-	sanitizedLinkType = strings.ReplaceAll(sanitizedLinkType, "/", "_")
-	sanitizedLinkType = strings.ReplaceAll(sanitizedLinkType, "-", "_")
-	sanitizedLinkType = strings.ReplaceAll(sanitizedLinkType, "%", "pct")
+// ReleaseReservation returns a reserved link to available stock
+func (l *InventoryLink) ReleaseReservation(reason, by string) error {
+	if l.Status != LinkStatusReserved {
+		return fmt.Errorf("only reserved links can be released (this link is %s)", l.Status)
+	}
+	note := "held for " + l.ReservedForUsername
+	l.MarkAvailable()
+	l.record(LinkEvent{Action: LinkEventReleased, Reason: reason, Note: note, By: by})
+	return nil
+}
+
+func (l *InventoryLink) clearReservation() {
+	l.ReservedFor = ""
+	l.ReservedForUsername = ""
+	l.ReservedUntil = time.Time{}
+	l.ReservedListID = ""
+}
 
-	timestamp := time.Now().UnixNano()
-	return fmt.Sprintf("%s_%s_%d", quality, sanitizedLinkType, timestamp)
+// EditNotes replaces the link's notes
+func (l *InventoryLink) EditNotes(notes, by string) {
+	l.Notes = notes
+	l.record(LinkEvent{Action: LinkEventNotes, Note: notes, By: by})
+}
+
+// Retire takes an available link out of stock for good, e.g. because it was
+// lost, sold or consumed
+func (l *InventoryLink) Retire(reason, note, by string) error {
+	if l.Status != LinkStatusAvailable {
+		return fmt.Errorf("only available links can be retired (this link is %s)", l.Status)
+	}
+	if !containsString(AllRetireReasons, reason) {
+		return fmt.Errorf("invalid retire reason %q (use %s)", reason, strings.Join(AllRetireReasons, ", "))
+	}
+	l.IsAvailable = "false"
+	l.Status = LinkStatusRetired
+	l.RetireReason = reason
+	l.record(LinkEvent{Action: LinkEventRetired, Reason: reason, Note: note, By: by})
+	return nil
+}
+
+// Restore returns a retired link to available stock
+func (l *InventoryLink) Restore(note, by string) error {
+	if l.Status != LinkStatusRetired {
+		return fmt.Errorf("only retired links can be restored (this link is %s)", l.Status)
+	}
+	l.MarkAvailable()
+	l.record(LinkEvent{Action: LinkEventRestored, Note: note, By: by})
+	return nil
+}
+
+// ReturnToStock makes a distributed link available again after its
+// distribution was reversed
+func (l *InventoryLink) ReturnToStock(reason, by string) error {
+	if l.Status != LinkStatusDistributed {
+		return fmt.Errorf("only distributed links can be returned to stock (this link is %s)", l.Status)
+	}
+	l.MarkAvailable()
+	l.record(LinkEvent{Action: LinkEventReversed, Reason: reason, By: by})
+	return nil
+}
+
+// Reassign records that a distributed link was handed to a different member
+// after its distribution was reversed
+func (l *InventoryLink) Reassign(reason, memberUsername, by string) error {
+	if l.Status != LinkStatusDistributed {
+		return fmt.Errorf("only distributed links can be reassigned (this link is %s)", l.Status)
+	}
+	l.record(LinkEvent{Action: LinkEventReassigned, Reason: reason, Note: "to " + memberUsername, By: by})
+	return nil
+}
+
+func (l *InventoryLink) record(event LinkEvent) {
+	event.At = time.Now()
+	l.History = append(l.History, event)
+}
+
+// GetDisplayName returns a formatted display name for the link
+func (l *InventoryLink) GetDisplayName() string {
+	return fmt.Sprintf("%s %s (%s)", l.Quality, l.LinkType, l.Bonus)
 }
 
 // GetLinkBonus returns the standard bonus for a link type and quality
@@ -74,9 +237,58 @@ func GetLinkBonus(linkType, quality string) string {
 	return GetLinkTypeBonus(linkType, quality)
 }
 
-// GetLinkCategory returns the category for a link type
+// GetLinkCategory returns the category for a link type, or CategoryCustom for
+// link types that aren't in AllLinkTypes
 func GetLinkCategory(linkType string) string {
-	// Simplified category mapping - can be expanded if needed
-	// For now, just return a generic category since categories aren't important
-	return "Mastery Links"
+	for _, lt := range AllLinkTypes {
+		if lt.Name == linkType {
+			return lt.Category
+		}
+	}
+	return CategoryCustom
+}
+
+// NormalizeCategory fills in the category of links stored before categories
+// were tracked, and of custom links whose type the catalog has since defined.
+// A nil catalog only knows the built-in link types.
+func (l *InventoryLink) NormalizeCategory(catalog *LinkCatalog) {
+	if l.Category == "" || l.Category == LegacyCategory || l.Category == CategoryCustom {
+		l.Category = catalog.LinkCategory(l.LinkType)
+	}
+}
+
+// NormalizeBonus fills in the numeric bonus of links stored before bonus
+// values were tracked and normalizes the display form
+func (l *InventoryLink) NormalizeBonus() {
+	l.Bonus, l.BonusValue = NormalizeBonus(l.Bonus)
+}
+
+// Normalize brings links stored by older versions up to date
+func (l *InventoryLink) Normalize() {
+	l.NormalizeCategory(nil)
+	l.NormalizeBonus()
+	if l.Status == "" {
+		l.Status = LinkStatusDistributed
+		if l.IsAvailable == "true" {
+			l.Status = LinkStatusAvailable
+		}
+	}
+}
+
+// NormalizeCategories brings the categories of links up to date with the catalog
+func NormalizeCategories(links []*InventoryLink, catalog *LinkCatalog) {
+	for _, link := range links {
+		link.NormalizeCategory(catalog)
+	}
+}
+
+// FilterLinksByCategory returns the links in a category
+func FilterLinksByCategory(links []*InventoryLink, category string) []*InventoryLink {
+	filtered := make([]*InventoryLink, 0, len(links))
+	for _, link := range links {
+		if link.Category == category {
+			filtered = append(filtered, link)
+		}
+	}
+	return filtered
 }
@@ -1,133 +1,168 @@
 package models
 
-// LinkType represents a mastery link type with its bonus values
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Link categories, following the groupings on the Outlands wiki
+const (
+	CategoryBarding        = "Barding"
+	CategoryBoating        = "Boating"
+	CategoryFollower       = "Follower"
+	CategoryMelee          = "Melee"
+	CategorySpell          = "Spell"
+	CategoryMonsterSlayer  = "Monster Slayer"
+	CategoryDungeonSlayer  = "Dungeon Slayer"
+	CategoryOtherDamage    = "Other Damage"
+	CategoryPoison         = "Poison"
+	CategoryResistance     = "Resistance"
+	CategoryEffectiveSkill = "Effective Skill"
+	CategoryOther          = "Other"
+	CategoryCustom         = "Custom" // link types not in AllLinkTypes
+
+	// LegacyCategory was stored on every link before categories were tracked
+	LegacyCategory = "Mastery Links"
+)
+
+// AllLinkCategories lists the categories in display order
+var AllLinkCategories = []string{
+	CategoryBarding, CategoryBoating, CategoryFollower, CategoryMelee, CategorySpell,
+	CategoryMonsterSlayer, CategoryDungeonSlayer, CategoryOtherDamage, CategoryPoison,
+	CategoryResistance, CategoryEffectiveSkill, CategoryOther, CategoryCustom,
+}
+
+// LinkType represents a mastery link type with its category and bonus values
 type LinkType struct {
-	Name   string
-	Bronze string
-	Silver string
-	Gold   string
+	Name     string
+	Category string
+	Bronze   string
+	Silver   string
+	Gold     string
 }
 
 // AllLinkTypes contains all available mastery link types from Outlands wiki
 var AllLinkTypes = []LinkType{
 	// Barding Type Links
-	{Name: "Bard Reset/Break Ignore Chance", Bronze: "2.50%", Silver: "3.13%", Gold: "3.75%"},
-	{Name: "Barding Effect Durations", Bronze: "3.00%", Silver: "3.75%", Gold: "4.50%"},
-	{Name: "Damage to Barded Creatures", Bronze: "1.75%", Silver: "2.19%", Gold: "2.63%"},
-	{Name: "Effective Barding Skill", Bronze: "3.00", Silver: "3.75", Gold: "4.50"},
+	{Name: "Bard Reset/Break Ignore Chance", Category: CategoryBarding, Bronze: "2.50%", Silver: "3.13%", Gold: "3.75%"},
+	{Name: "Barding Effect Durations", Category: CategoryBarding, Bronze: "3.00%", Silver: "3.75%", Gold: "4.50%"},
+	{Name: "Damage to Barded Creatures", Category: CategoryBarding, Bronze: "1.75%", Silver: "2.19%", Gold: "2.63%"},
+	{Name: "Effective Barding Skill", Category: CategoryBarding, Bronze: "3.00", Silver: "3.75", Gold: "4.50"},
 
 	// Boating Type Links
-	{Name: "Damage on Ships", Bronze: "3.00%", Silver: "3.75%", Gold: "4.50%"},
-	{Name: "Damage Resistance on Ships", Bronze: "3.00%", Silver: "3.75%", Gold: "4.50%"},
-	{Name: "Ship Cannon Damage", Bronze: "1.50%", Silver: "1.88%", Gold: "2.25%"},
-	{Name: "Crewmember Damage", Bronze: "1.50%", Silver: "1.88%", Gold: "2.25%"},
-	{Name: "Crewmember Damage Resistance", Bronze: "1.50%", Silver: "1.88%", Gold: "2.25%"},
+	{Name: "Damage on Ships", Category: CategoryBoating, Bronze: "3.00%", Silver: "3.75%", Gold: "4.50%"},
+	{Name: "Damage Resistance on Ships", Category: CategoryBoating, Bronze: "3.00%", Silver: "3.75%", Gold: "4.50%"},
+	{Name: "Ship Cannon Damage", Category: CategoryBoating, Bronze: "1.50%", Silver: "1.88%", Gold: "2.25%"},
+	{Name: "Crewmember Damage", Category: CategoryBoating, Bronze: "1.50%", Silver: "1.88%", Gold: "2.25%"},
+	{Name: "Crewmember Damage Resistance", Category: CategoryBoating, Bronze: "1.50%", Silver: "1.88%", Gold: "2.25%"},
 
 	// Follower Type Links
-	{Name: "Follower Accuracy/Defense", Bronze: "1.50%", Silver: "1.88%", Gold: "2.25%"},
-	{Name: "Follower Attack Speed", Bronze: "1.00%", Silver: "1.25%", Gold: "1.50%"},
-	{Name: "Follower Damage", Bronze: "2.00%", Silver: "2.50%", Gold: "3.00%"},
-	{Name: "Follower Damage Resistance", Bronze: "2.00%", Silver: "2.50%", Gold: "3.00%"},
-	{Name: "Follower Healing Received", Bronze: "3.00%", Silver: "3.75%", Gold: "4.50%"},
+	{Name: "Follower Accuracy/Defense", Category: CategoryFollower, Bronze: "1.50%", Silver: "1.88%", Gold: "2.25%"},
+	{Name: "Follower Attack Speed", Category: CategoryFollower, Bronze: "1.00%", Silver: "1.25%", Gold: "1.50%"},
+	{Name: "Follower Damage", Category: CategoryFollower, Bronze: "2.00%", Silver: "2.50%", Gold: "3.00%"},
+	{Name: "Follower Damage Resistance", Category: CategoryFollower, Bronze: "2.00%", Silver: "2.50%", Gold: "3.00%"},
+	{Name: "Follower Healing Received", Category: CategoryFollower, Bronze: "3.00%", Silver: "3.75%", Gold: "4.50%"},
 
 	// Melee Type Links
-	{Name: "Melee Aspect Effect Chance", Bronze: "4.50%", Silver: "5.63%", Gold: "6.75%"},
-	{Name: "Melee Aspect Effect Modifier", Bronze: "5.00%", Silver: "6.25%", Gold: "7.50%"},
-	{Name: "Melee Accuracy", Bronze: "1.75%", Silver: "2.19%", Gold: "2.62%"},
-	{Name: "Melee Defense", Bronze: "2.50%", Silver: "3.13%", Gold: "3.75%"},
-	{Name: "Melee Accuracy/Defense", Bronze: "1.50%", Silver: "1.88%", Gold: "2.25%"},
-	{Name: "Melee Special Chance", Bronze: "2.00%", Silver: "2.50%", Gold: "3.00%"},
-	{Name: "Melee Special Chance/Special Damage", Bronze: "1.75%", Silver: "2.19%", Gold: "2.63%"},
-	{Name: "Melee Damage", Bronze: "3.00%", Silver: "3.75%", Gold: "4.50%"},
-	{Name: "Melee Ignore Armor Chance", Bronze: "4.00%", Silver: "5.00%", Gold: "6.00%"},
-	{Name: "Melee Damage/Ignore Armor Chance", Bronze: "2.50%", Silver: "3.13%", Gold: "3.75%"},
-	{Name: "Melee Swing Speed", Bronze: "0.80%", Silver: "1.00%", Gold: "1.20%"},
+	{Name: "Melee Aspect Effect Chance", Category: CategoryMelee, Bronze: "4.50%", Silver: "5.63%", Gold: "6.75%"},
+	{Name: "Melee Aspect Effect Modifier", Category: CategoryMelee, Bronze: "5.00%", Silver: "6.25%", Gold: "7.50%"},
+	{Name: "Melee Accuracy", Category: CategoryMelee, Bronze: "1.75%", Silver: "2.19%", Gold: "2.62%"},
+	{Name: "Melee Defense", Category: CategoryMelee, Bronze: "2.50%", Silver: "3.13%", Gold: "3.75%"},
+	{Name: "Melee Accuracy/Defense", Category: CategoryMelee, Bronze: "1.50%", Silver: "1.88%", Gold: "2.25%"},
+	{Name: "Melee Special Chance", Category: CategoryMelee, Bronze: "2.00%", Silver: "2.50%", Gold: "3.00%"},
+	{Name: "Melee Special Chance/Special Damage", Category: CategoryMelee, Bronze: "1.75%", Silver: "2.19%", Gold: "2.63%"},
+	{Name: "Melee Damage", Category: CategoryMelee, Bronze: "3.00%", Silver: "3.75%", Gold: "4.50%"},
+	{Name: "Melee Ignore Armor Chance", Category: CategoryMelee, Bronze: "4.00%", Silver: "5.00%", Gold: "6.00%"},
+	{Name: "Melee Damage/Ignore Armor Chance", Category: CategoryMelee, Bronze: "2.50%", Silver: "3.13%", Gold: "3.75%"},
+	{Name: "Melee Swing Speed", Category: CategoryMelee, Bronze: "0.80%", Silver: "1.00%", Gold: "1.20%"},
 
 	// Spell Type Links
-	{Name: "Meditation Rate", Bronze: "2.00%", Silver: "2.50%", Gold: "3.00%"},
-	{Name: "Spell Disrupt Avoid Chance", Bronze: "6.00%", Silver: "7.50%", Gold: "9.00%"},
-	{Name: "Meditation Rate/Disrupt Avoid Chance", Bronze: "2.00%", Silver: "2.50%", Gold: "3.00%"},
-	{Name: "Spell Aspect Effect Modifier", Bronze: "5.00%", Silver: "6.25%", Gold: "7.50%"},
-	{Name: "Spell Aspect Special Chance", Bronze: "4.50%", Silver: "5.63%", Gold: "6.75%"},
-	{Name: "Spell Charged Chance", Bronze: "4.00%", Silver: "5.00%", Gold: "6.00%"},
-	{Name: "Spell Charged Damage", Bronze: "6.00%", Silver: "7.50%", Gold: "9.00%"},
-	{Name: "Spell Charged Chance/Charged Damage", Bronze: "3.50%", Silver: "4.38%", Gold: "5.25%"},
-	{Name: "Spell Damage", Bronze: "3.00%", Silver: "3.75%", Gold: "4.50%"},
-	{Name: "Spell Ignore Resist Chance", Bronze: "4.00%", Silver: "5.00%", Gold: "6.00%"},
-	{Name: "Spell Damage/Ignore Resist Chance", Bronze: "2.50%", Silver: "3.13%", Gold: "3.75%"},
-	{Name: "Spell Damage When No Followers", Bronze: "4.00%", Silver: "5.00%", Gold: "6.00%"},
+	{Name: "Meditation Rate", Category: CategorySpell, Bronze: "2.00%", Silver: "2.50%", Gold: "3.00%"},
+	{Name: "Spell Disrupt Avoid Chance", Category: CategorySpell, Bronze: "6.00%", Silver: "7.50%", Gold: "9.00%"},
+	{Name: "Meditation Rate/Disrupt Avoid Chance", Category: CategorySpell, Bronze: "2.00%", Silver: "2.50%", Gold: "3.00%"},
+	{Name: "Spell Aspect Effect Modifier", Category: CategorySpell, Bronze: "5.00%", Silver: "6.25%", Gold: "7.50%"},
+	{Name: "Spell Aspect Special Chance", Category: CategorySpell, Bronze: "4.50%", Silver: "5.63%", Gold: "6.75%"},
+	{Name: "Spell Charged Chance", Category: CategorySpell, Bronze: "4.00%", Silver: "5.00%", Gold: "6.00%"},
+	{Name: "Spell Charged Damage", Category: CategorySpell, Bronze: "6.00%", Silver: "7.50%", Gold: "9.00%"},
+	{Name: "Spell Charged Chance/Charged Damage", Category: CategorySpell, Bronze: "3.50%", Silver: "4.38%", Gold: "5.25%"},
+	{Name: "Spell Damage", Category: CategorySpell, Bronze: "3.00%", Silver: "3.75%", Gold: "4.50%"},
+	{Name: "Spell Ignore Resist Chance", Category: CategorySpell, Bronze: "4.00%", Silver: "5.00%", Gold: "6.00%"},
+	{Name: "Spell Damage/Ignore Resist Chance", Category: CategorySpell, Bronze: "2.50%", Silver: "3.13%", Gold: "3.75%"},
+	{Name: "Spell Damage When No Followers", Category: CategorySpell, Bronze: "4.00%", Silver: "5.00%", Gold: "6.00%"},
 
 	// Monster Slayer Links
-	{Name: "Damage to Bestial Creatures", Bronze: "2.50%", Silver: "3.13%", Gold: "3.75%"},
-	{Name: "Damage to Construct Creatures", Bronze: "2.50%", Silver: "3.13%", Gold: "3.75%"},
-	{Name: "Damage to Daemonic Creatures", Bronze: "2.50%", Silver: "3.13%", Gold: "3.75%"},
-	{Name: "Damage to Elemental Creatures", Bronze: "2.50%", Silver: "3.13%", Gold: "3.75%"},
-	{Name: "Damage to Humanoid Creatures", Bronze: "2.50%", Silver: "3.13%", Gold: "3.75%"},
-	{Name: "Damage to Monstrous Creatures", Bronze: "2.50%", Silver: "3.13%", Gold: "3.75%"},
-	{Name: "Damage to Nature Creatures", Bronze: "2.50%", Silver: "3.13%", Gold: "3.75%"},
-	{Name: "Damage to Undead Creatures", Bronze: "2.50%", Silver: "3.13%", Gold: "3.75%"},
+	{Name: "Damage to Bestial Creatures", Category: CategoryMonsterSlayer, Bronze: "2.50%", Silver: "3.13%", Gold: "3.75%"},
+	{Name: "Damage to Construct Creatures", Category: CategoryMonsterSlayer, Bronze: "2.50%", Silver: "3.13%", Gold: "3.75%"},
+	{Name: "Damage to Daemonic Creatures", Category: CategoryMonsterSlayer, Bronze: "2.50%", Silver: "3.13%", Gold: "3.75%"},
+	{Name: "Damage to Elemental Creatures", Category: CategoryMonsterSlayer, Bronze: "2.50%", Silver: "3.13%", Gold: "3.75%"},
+	{Name: "Damage to Humanoid Creatures", Category: CategoryMonsterSlayer, Bronze: "2.50%", Silver: "3.13%", Gold: "3.75%"},
+	{Name: "Damage to Monstrous Creatures", Category: CategoryMonsterSlayer, Bronze: "2.50%", Silver: "3.13%", Gold: "3.75%"},
+	{Name: "Damage to Nature Creatures", Category: CategoryMonsterSlayer, Bronze: "2.50%", Silver: "3.13%", Gold: "3.75%"},
+	{Name: "Damage to Undead Creatures", Category: CategoryMonsterSlayer, Bronze: "2.50%", Silver: "3.13%", Gold: "3.75%"},
 
 	// Dungeon Slayer Links
-	{Name: "Aegis Keep Damage", Bronze: "2.00%", Silver: "2.50%", Gold: "3.00%"},
-	{Name: "Cavernam Damage", Bronze: "2.00%", Silver: "2.50%", Gold: "3.00%"},
-	{Name: "Darkmire Temple Damage", Bronze: "2.00%", Silver: "2.50%", Gold: "3.00%"},
-	{Name: "Inferno Damage", Bronze: "2.00%", Silver: "2.50%", Gold: "3.00%"},
-	{Name: "Kraul Hive Damage", Bronze: "2.00%", Silver: "2.50%", Gold: "3.00%"},
-	{Name: "Mausoleum Damage", Bronze: "2.00%", Silver: "2.50%", Gold: "3.00%"},
-	{Name: "Mount Petram Damage", Bronze: "2.00%", Silver: "2.50%", Gold: "3.00%"},
-	{Name: "Netherzone Damage", Bronze: "2.00%", Silver: "2.50%", Gold: "3.00%"},
-	{Name: "Nusero Damage", Bronze: "2.00%", Silver: "2.50%", Gold: "3.00%"},
-	{Name: "Ossuary Damage", Bronze: "2.00%", Silver: "2.50%", Gold: "3.00%"},
-	{Name: "Pulma Damage", Bronze: "2.00%", Silver: "2.50%", Gold: "3.00%"},
-	{Name: "Shadowspire Cathedral Damage", Bronze: "2.00%", Silver: "2.50%", Gold: "3.00%"},
-	{Name: "Time Dungeon Damage", Bronze: "2.00%", Silver: "2.50%", Gold: "3.00%"},
-	{Name: "Wilderness Damage", Bronze: "2.00%", Silver: "2.50%", Gold: "3.00%"},
+	{Name: "Aegis Keep Damage", Category: CategoryDungeonSlayer, Bronze: "2.00%", Silver: "2.50%", Gold: "3.00%"},
+	{Name: "Cavernam Damage", Category: CategoryDungeonSlayer, Bronze: "2.00%", Silver: "2.50%", Gold: "3.00%"},
+	{Name: "Darkmire Temple Damage", Category: CategoryDungeonSlayer, Bronze: "2.00%", Silver: "2.50%", Gold: "3.00%"},
+	{Name: "Inferno Damage", Category: CategoryDungeonSlayer, Bronze: "2.00%", Silver: "2.50%", Gold: "3.00%"},
+	{Name: "Kraul Hive Damage", Category: CategoryDungeonSlayer, Bronze: "2.00%", Silver: "2.50%", Gold: "3.00%"},
+	{Name: "Mausoleum Damage", Category: CategoryDungeonSlayer, Bronze: "2.00%", Silver: "2.50%", Gold: "3.00%"},
+	{Name: "Mount Petram Damage", Category: CategoryDungeonSlayer, Bronze: "2.00%", Silver: "2.50%", Gold: "3.00%"},
+	{Name: "Netherzone Damage", Category: CategoryDungeonSlayer, Bronze: "2.00%", Silver: "2.50%", Gold: "3.00%"},
+	{Name: "Nusero Damage", Category: CategoryDungeonSlayer, Bronze: "2.00%", Silver: "2.50%", Gold: "3.00%"},
+	{Name: "Ossuary Damage", Category: CategoryDungeonSlayer, Bronze: "2.00%", Silver: "2.50%", Gold: "3.00%"},
+	{Name: "Pulma Damage", Category: CategoryDungeonSlayer, Bronze: "2.00%", Silver: "2.50%", Gold: "3.00%"},
+	{Name: "Shadowspire Cathedral Damage", Category: CategoryDungeonSlayer, Bronze: "2.00%", Silver: "2.50%", Gold: "3.00%"},
+	{Name: "Time Dungeon Damage", Category: CategoryDungeonSlayer, Bronze: "2.00%", Silver: "2.50%", Gold: "3.00%"},
+	{Name: "Wilderness Damage", Category: CategoryDungeonSlayer, Bronze: "2.00%", Silver: "2.50%", Gold: "3.00%"},
 
 	// Other Damage Type Links
-	{Name: "Backstab Damage", Bronze: "4.50%", Silver: "5.63%", Gold: "6.75%"},
-	{Name: "Damage to Diseased Creatures", Bronze: "1.75%", Silver: "2.1875%", Gold: "2.625%"},
-	{Name: "Damage to Bleeding Creatures", Bronze: "1.75%", Silver: "2.1875%", Gold: "2.625%"},
-	{Name: "Damage to Bosses", Bronze: "3.00%", Silver: "3.75%", Gold: "4.50%"},
-	{Name: "Damage to Creatures Above 66% HP", Bronze: "2.00%", Silver: "2.50%", Gold: "3.00%"},
-	{Name: "Damage to Creatures Below 33% HP", Bronze: "2.50%", Silver: "3.13%", Gold: "3.75%"},
-	{Name: "Damage Dealt By Player", Bronze: "1.25%", Silver: "1.56%", Gold: "1.88%"},
-	{Name: "Trap Damage", Bronze: "4.00%", Silver: "5.00%", Gold: "6.00%"},
+	{Name: "Backstab Damage", Category: CategoryOtherDamage, Bronze: "4.50%", Silver: "5.63%", Gold: "6.75%"},
+	{Name: "Damage to Diseased Creatures", Category: CategoryOtherDamage, Bronze: "1.75%", Silver: "2.1875%", Gold: "2.625%"},
+	{Name: "Damage to Bleeding Creatures", Category: CategoryOtherDamage, Bronze: "1.75%", Silver: "2.1875%", Gold: "2.625%"},
+	{Name: "Damage to Bosses", Category: CategoryOtherDamage, Bronze: "3.00%", Silver: "3.75%", Gold: "4.50%"},
+	{Name: "Damage to Creatures Above 66% HP", Category: CategoryOtherDamage, Bronze: "2.00%", Silver: "2.50%", Gold: "3.00%"},
+	{Name: "Damage to Creatures Below 33% HP", Category: CategoryOtherDamage, Bronze: "2.50%", Silver: "3.13%", Gold: "3.75%"},
+	{Name: "Damage Dealt By Player", Category: CategoryOtherDamage, Bronze: "1.25%", Silver: "1.56%", Gold: "1.88%"},
+	{Name: "Trap Damage", Category: CategoryOtherDamage, Bronze: "4.00%", Silver: "5.00%", Gold: "6.00%"},
 
 	// Poison Type Links
-	{Name: "Damage to Poisoned Creatures", Bronze: "1.75%", Silver: "2.19%", Gold: "2.63%"},
-	{Name: "Effective Poisoning Skill", Bronze: "3.00", Silver: "3.75", Gold: "4.50"},
-	{Name: "Poison Damage", Bronze: "5.00%", Silver: "6.25%", Gold: "7.50%"},
-	{Name: "Poison Damage/Resist Ignore", Bronze: "4.00%", Silver: "5.00%", Gold: "6.00%"},
+	{Name: "Damage to Poisoned Creatures", Category: CategoryPoison, Bronze: "1.75%", Silver: "2.19%", Gold: "2.63%"},
+	{Name: "Effective Poisoning Skill", Category: CategoryPoison, Bronze: "3.00", Silver: "3.75", Gold: "4.50"},
+	{Name: "Poison Damage", Category: CategoryPoison, Bronze: "5.00%", Silver: "6.25%", Gold: "7.50%"},
+	{Name: "Poison Damage/Resist Ignore", Category: CategoryPoison, Bronze: "4.00%", Silver: "5.00%", Gold: "6.00%"},
 
 	// Resistance Type Links
-	{Name: "Boss Damage Resistance", Bronze: "2.00%", Silver: "2.50%", Gold: "3.00%"},
-	{Name: "Damage Resistance", Bronze: "1.00%", Silver: "1.25%", Gold: "1.50%"},
-	{Name: "Physical Damage Resistance", Bronze: "1.50%", Silver: "1.88%", Gold: "2.25%"},
-	{Name: "Spell Damage Resistance", Bronze: "1.50%", Silver: "1.88%", Gold: "2.25%"},
+	{Name: "Boss Damage Resistance", Category: CategoryResistance, Bronze: "2.00%", Silver: "2.50%", Gold: "3.00%"},
+	{Name: "Damage Resistance", Category: CategoryResistance, Bronze: "1.00%", Silver: "1.25%", Gold: "1.50%"},
+	{Name: "Physical Damage Resistance", Category: CategoryResistance, Bronze: "1.50%", Silver: "1.88%", Gold: "2.25%"},
+	{Name: "Spell Damage Resistance", Category: CategoryResistance, Bronze: "1.50%", Silver: "1.88%", Gold: "2.25%"},
 
 	// Effective Skill Links
-	{Name: "Effective Alchemy Skill", Bronze: "3.00", Silver: "3.75", Gold: "4.50"},
-	{Name: "Alchemy/Healing/Veterinary", Bronze: "3.00", Silver: "3.75", Gold: "4.50"},
-	{Name: "Effective Arms Lore", Bronze: "3.00", Silver: "3.75", Gold: "4.50"},
-	{Name: "Effective Camping Skill", Bronze: "3.00", Silver: "3.75", Gold: "4.50"},
-	{Name: "Chivalry Skill", Bronze: "2.50", Silver: "3.13", Gold: "3.75"},
-	{Name: "Effective Harvest Skill", Bronze: "1.00", Silver: "1.25", Gold: "1.50"},
-	{Name: "Effective Magic Resist Skill", Bronze: "3.00", Silver: "3.75", Gold: "4.50"},
-	{Name: "Necromancy Skill", Bronze: "2.50", Silver: "3.13", Gold: "3.75"},
-	{Name: "Effective Parrying Skill", Bronze: "3.00", Silver: "3.75", Gold: "4.50"},
-	{Name: "Effective Skill on Chests", Bronze: "3.00", Silver: "3.75", Gold: "4.50"},
-	{Name: "Spirit Speak/Inscription", Bronze: "2.50", Silver: "3.13", Gold: "3.75"},
+	{Name: "Effective Alchemy Skill", Category: CategoryEffectiveSkill, Bronze: "3.00", Silver: "3.75", Gold: "4.50"},
+	{Name: "Alchemy/Healing/Veterinary", Category: CategoryEffectiveSkill, Bronze: "3.00", Silver: "3.75", Gold: "4.50"},
+	{Name: "Effective Arms Lore", Category: CategoryEffectiveSkill, Bronze: "3.00", Silver: "3.75", Gold: "4.50"},
+	{Name: "Effective Camping Skill", Category: CategoryEffectiveSkill, Bronze: "3.00", Silver: "3.75", Gold: "4.50"},
+	{Name: "Chivalry Skill", Category: CategoryEffectiveSkill, Bronze: "2.50", Silver: "3.13", Gold: "3.75"},
+	{Name: "Effective Harvest Skill", Category: CategoryEffectiveSkill, Bronze: "1.00", Silver: "1.25", Gold: "1.50"},
+	{Name: "Effective Magic Resist Skill", Category: CategoryEffectiveSkill, Bronze: "3.00", Silver: "3.75", Gold: "4.50"},
+	{Name: "Necromancy Skill", Category: CategoryEffectiveSkill, Bronze: "2.50", Silver: "3.13", Gold: "3.75"},
+	{Name: "Effective Parrying Skill", Category: CategoryEffectiveSkill, Bronze: "3.00", Silver: "3.75", Gold: "4.50"},
+	{Name: "Effective Skill on Chests", Category: CategoryEffectiveSkill, Bronze: "3.00", Silver: "3.75", Gold: "4.50"},
+	{Name: "Spirit Speak/Inscription", Category: CategoryEffectiveSkill, Bronze: "2.50", Silver: "3.13", Gold: "3.75"},
 
 	// Other Links
-	{Name: "Chance on Stealth for 5 Extra Steps", Bronze: "5.00%", Silver: "6.25%", Gold: "7.50%"},
-	{Name: "Chest Success Chances/Progress", Bronze: "3.00%", Silver: "3.75%", Gold: "4.50%"},
-	{Name: "Exceptional Quality Chance", Bronze: "1.50%", Silver: "1.88%", Gold: "2.25%"},
-	{Name: "Gold/Doubloon Drop Increase", Bronze: "1.00%", Silver: "1.50%", Gold: "2.00%"},
-	{Name: "Healing Received", Bronze: "3.00%", Silver: "3.75%", Gold: "4.50%"},
-	{Name: "Special Loot Chance", Bronze: "1.00%", Silver: "1.50%", Gold: "2.00%"},
-	{Name: "Rare Loot Chance", Bronze: "1.00%", Silver: "1.50%", Gold: "2.00%"},
-	{Name: "Special/Rare Loot Chance", Bronze: "1.00%", Silver: "1.50%", Gold: "2.00%"},
-	{Name: "Summon Duration and Dispel Resist", Bronze: "3.00%", Silver: "3.75%", Gold: "4.5%"},
+	{Name: "Chance on Stealth for 5 Extra Steps", Category: CategoryOther, Bronze: "5.00%", Silver: "6.25%", Gold: "7.50%"},
+	{Name: "Chest Success Chances/Progress", Category: CategoryOther, Bronze: "3.00%", Silver: "3.75%", Gold: "4.50%"},
+	{Name: "Exceptional Quality Chance", Category: CategoryOther, Bronze: "1.50%", Silver: "1.88%", Gold: "2.25%"},
+	{Name: "Gold/Doubloon Drop Increase", Category: CategoryOther, Bronze: "1.00%", Silver: "1.50%", Gold: "2.00%"},
+	{Name: "Healing Received", Category: CategoryOther, Bronze: "3.00%", Silver: "3.75%", Gold: "4.50%"},
+	{Name: "Special Loot Chance", Category: CategoryOther, Bronze: "1.00%", Silver: "1.50%", Gold: "2.00%"},
+	{Name: "Rare Loot Chance", Category: CategoryOther, Bronze: "1.00%", Silver: "1.50%", Gold: "2.00%"},
+	{Name: "Special/Rare Loot Chance", Category: CategoryOther, Bronze: "1.00%", Silver: "1.50%", Gold: "2.00%"},
+	{Name: "Summon Duration and Dispel Resist", Category: CategoryOther, Bronze: "3.00%", Silver: "3.75%", Gold: "4.5%"},
 }
 
 // GetLinkTypeBonus returns the bonus for a specific link type and quality
@@ -155,3 +190,221 @@ func GetAllLinkTypeNames() []string {
 	}
 	return names
 }
+
+// CatalogEntry is one version of a link type definition in the link catalog.
+// A rebalance adds a new version with a later EffectiveFrom instead of
+// overwriting, so links keep the values in force when they were added.
+type CatalogEntry struct {
+	Name          string    `json:"name" dynamodbav:"name"`
+	EffectiveFrom time.Time `json:"effective_from" dynamodbav:"effective_from"`
+	Category      string    `json:"category" dynamodbav:"category"`
+	Bronze        string    `json:"bronze" dynamodbav:"bronze"`
+	Silver        string    `json:"silver" dynamodbav:"silver"`
+	Gold          string    `json:"gold" dynamodbav:"gold"`
+	IsActive      bool      `json:"is_active" dynamodbav:"is_active"` // inactive types can't be added to inventory
+	UpdatedBy     string    `json:"updated_by" dynamodbav:"updated_by"`
+	UpdatedAt     time.Time `json:"updated_at" dynamodbav:"updated_at"`
+}
+
+// Bonus returns the entry's bonus for a quality
+func (e *CatalogEntry) Bonus(quality string) string {
+	switch quality {
+	case QualityBronze:
+		return e.Bronze
+	case QualitySilver:
+		return e.Silver
+	case QualityGold:
+		return e.Gold
+	}
+	return ""
+}
+
+// LinkCatalog resolves link type definitions across catalog versions
+type LinkCatalog struct {
+	versions map[string][]*CatalogEntry // oldest first
+}
+
+// NewLinkCatalog builds a catalog from entries in any order
+func NewLinkCatalog(entries []*CatalogEntry) *LinkCatalog {
+	c := &LinkCatalog{versions: make(map[string][]*CatalogEntry)}
+	for _, entry := range entries {
+		c.versions[entry.Name] = append(c.versions[entry.Name], entry)
+	}
+	for _, versions := range c.versions {
+		sort.Slice(versions, func(i, j int) bool {
+			return versions[i].EffectiveFrom.Before(versions[j].EffectiveFrom)
+		})
+	}
+	return c
+}
+
+// DefaultCatalogEntries returns the built-in AllLinkTypes as catalog entries
+// effective since the beginning of time
+func DefaultCatalogEntries() []*CatalogEntry {
+	entries := make([]*CatalogEntry, len(AllLinkTypes))
+	for i, lt := range AllLinkTypes {
+		entries[i] = &CatalogEntry{
+			Name:      lt.Name,
+			Category:  GetLinkCategory(lt.Name),
+			Bronze:    lt.Bronze,
+			Silver:    lt.Silver,
+			Gold:      lt.Gold,
+			IsActive:  true,
+			UpdatedBy: "system",
+		}
+	}
+	return entries
+}
+
+// WithBuiltinBaselines adds the built-in version of each built-in link type to
+// stored catalog entries, unless a version effective since the beginning of
+// time is stored for it. Later stored versions then apply from their
+// EffectiveFrom and the built-in values before it.
+func WithBuiltinBaselines(entries []*CatalogEntry) []*CatalogEntry {
+	hasBaseline := make(map[string]bool)
+	for _, entry := range entries {
+		if entry.EffectiveFrom.IsZero() {
+			hasBaseline[entry.Name] = true
+		}
+	}
+	for _, entry := range DefaultCatalogEntries() {
+		if !hasBaseline[entry.Name] {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// DefaultLinkCatalog returns a catalog of the built-in link types
+func DefaultLinkCatalog() *LinkCatalog {
+	return NewLinkCatalog(DefaultCatalogEntries())
+}
+
+// Resolve returns the version of a link type in force at a time, or nil if unknown
+func (c *LinkCatalog) Resolve(linkType string, at time.Time) *CatalogEntry {
+	var current *CatalogEntry
+	for _, entry := range c.versions[linkType] {
+		if entry.EffectiveFrom.After(at) {
+			break
+		}
+		current = entry
+	}
+	return current
+}
+
+// GetLinkTypeBonus returns the bonus for a link type and quality as it was at a time
+func (c *LinkCatalog) GetLinkTypeBonus(linkType, quality string, at time.Time) string {
+	if entry := c.Resolve(linkType, at); entry != nil {
+		if bonus := entry.Bonus(quality); bonus != "" {
+			return bonus
+		}
+	}
+	return "TBD" // To be determined for custom links
+}
+
+// InventoryDetails returns the category and bonus to stamp on a link added at a
+// time. Unknown link types are allowed as custom links; inactive ones are not.
+func (c *LinkCatalog) InventoryDetails(linkType, quality string, at time.Time) (category, bonus string, err error) {
+	entry := c.Resolve(linkType, at)
+	if entry == nil {
+		return GetLinkCategory(linkType), "TBD", nil
+	}
+	if !entry.IsActive {
+		return "", "", fmt.Errorf("link type %s is no longer active", linkType)
+	}
+	return entry.Category, c.GetLinkTypeBonus(linkType, quality, at), nil
+}
+
+// Current returns the version of every link type in force at a time, sorted by name
+func (c *LinkCatalog) Current(at time.Time, includeInactive bool) []*CatalogEntry {
+	var entries []*CatalogEntry
+	for name := range c.versions {
+		if entry := c.Resolve(name, at); entry != nil && (entry.IsActive || includeInactive) {
+			entries = append(entries, entry)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name < entries[j].Name
+	})
+	return entries
+}
+
+// CategoryLinkTypes lists the active link types in one category
+type CategoryLinkTypes struct {
+	Category  string   `json:"category"`
+	LinkTypes []string `json:"link_types"`
+}
+
+// Categories groups the active link types at a time by category, in
+// AllLinkCategories order; categories from the catalog table that aren't
+// built in are listed last
+func (c *LinkCatalog) Categories(at time.Time) []CategoryLinkTypes {
+	byCategory := make(map[string][]string)
+	for _, entry := range c.Current(at, false) {
+		byCategory[entry.Category] = append(byCategory[entry.Category], entry.Name)
+	}
+
+	var result []CategoryLinkTypes
+	for _, category := range AllLinkCategories {
+		if names, ok := byCategory[category]; ok {
+			result = append(result, CategoryLinkTypes{Category: category, LinkTypes: names})
+			delete(byCategory, category)
+		}
+	}
+
+	var extra []string
+	for category := range byCategory {
+		extra = append(extra, category)
+	}
+	sort.Strings(extra)
+	for _, category := range extra {
+		result = append(result, CategoryLinkTypes{Category: category, LinkTypes: byCategory[category]})
+	}
+
+	return result
+}
+
+// CategoryNames lists every category a link can be in: the built-in ones in
+// AllLinkCategories order, then any other category used by a version in the
+// catalog, sorted
+func (c *LinkCatalog) CategoryNames() []string {
+	names := append([]string(nil), AllLinkCategories...)
+	var extra []string
+	for _, versions := range c.versions {
+		for _, entry := range versions {
+			if !containsString(names, entry.Category) && !containsString(extra, entry.Category) {
+				extra = append(extra, entry.Category)
+			}
+		}
+	}
+	sort.Strings(extra)
+	return append(names, extra...)
+}
+
+// ParseCategory matches a category name case-insensitively against
+// CategoryNames
+func (c *LinkCatalog) ParseCategory(name string) (string, bool) {
+	for _, category := range c.CategoryNames() {
+		if strings.EqualFold(category, strings.TrimSpace(name)) {
+			return category, true
+		}
+	}
+	return "", false
+}
+
+// LinkCategory returns the category of a link type's latest version, or
+// GetLinkCategory for types not in the catalog. A nil catalog only knows the
+// built-in link types.
+func (c *LinkCatalog) LinkCategory(linkType string) string {
+	if c != nil {
+		if versions := c.versions[linkType]; len(versions) > 0 {
+			return versions[len(versions)-1].Category
+		}
+	}
+	return GetLinkCategory(linkType)
+}
+
+// History returns every version of a link type, oldest first
+func (c *LinkCatalog) History(linkType string) []*CatalogEntry {
+	return c.versions[linkType]
+}
@@ -0,0 +1,114 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLinkCatalogResolve(t *testing.T) {
+	rebalance := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	retired := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+
+	// Only the rebalance is stored; the built-in values stay the baseline
+	catalog := NewLinkCatalog(WithBuiltinBaselines([]*CatalogEntry{
+		{Name: "Melee Damage", EffectiveFrom: rebalance, Category: CategoryMelee, Bronze: "3.25%", Silver: "4.00%", Gold: "5.00%", IsActive: true},
+		{Name: "Boss Damage", EffectiveFrom: rebalance, Category: "Bossing", Bronze: "1.00%", Silver: "2.00%", Gold: "3.00%", IsActive: true},
+		{Name: "Boss Damage", EffectiveFrom: retired, Category: "Bossing", Bronze: "1.00%", Silver: "2.00%", Gold: "3.00%"},
+	}))
+
+	tests := []struct {
+		name     string
+		linkType string
+		at       time.Time
+		wantGold string // "" if the type should not resolve
+		active   bool
+	}{
+		{"built-in before its first stored version", "Melee Damage", rebalance.Add(-time.Hour), "4.50%", true},
+		{"built-in at its stored version", "Melee Damage", rebalance, "5.00%", true},
+		{"built-in after its stored version", "Melee Damage", retired, "5.00%", true},
+		{"untouched built-in", "Spell Damage", rebalance, "4.50%", true},
+		{"custom type before it existed", "Boss Damage", rebalance.Add(-time.Hour), "", false},
+		{"custom type", "Boss Damage", rebalance.Add(time.Hour), "3.00%", true},
+		{"custom type after retirement", "Boss Damage", retired, "3.00%", false},
+		{"unknown type", "Nothing", retired, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry := catalog.Resolve(tt.linkType, tt.at)
+			if tt.wantGold == "" {
+				if entry != nil {
+					t.Fatalf("Resolve = %+v, want nil", entry)
+				}
+				return
+			}
+			if entry == nil {
+				t.Fatal("Resolve = nil")
+			}
+			if entry.Gold != tt.wantGold || entry.IsActive != tt.active {
+				t.Errorf("Resolve = gold %s active %v, want gold %s active %v", entry.Gold, entry.IsActive, tt.wantGold, tt.active)
+			}
+		})
+	}
+}
+
+func TestWithBuiltinBaselinesKeepsStoredBaseline(t *testing.T) {
+	entries := WithBuiltinBaselines([]*CatalogEntry{
+		{Name: "Melee Damage", Category: CategoryMelee, Gold: "9.00%", IsActive: true},
+	})
+	catalog := NewLinkCatalog(entries)
+
+	if history := catalog.History("Melee Damage"); len(history) != 1 {
+		t.Fatalf("History has %d versions, want the stored baseline only", len(history))
+	}
+	if got := catalog.GetLinkTypeBonus("Melee Damage", QualityGold, time.Now()); got != "9.00%" {
+		t.Errorf("GetLinkTypeBonus = %s, want 9.00%%", got)
+	}
+	if len(entries) != len(AllLinkTypes) {
+		t.Errorf("got %d entries, want one per built-in type (%d)", len(entries), len(AllLinkTypes))
+	}
+}
+
+func TestCatalogCategories(t *testing.T) {
+	catalog := NewLinkCatalog(WithBuiltinBaselines([]*CatalogEntry{
+		{Name: "Boss Damage", Category: "Bossing", Gold: "3.00%", IsActive: true},
+	}))
+
+	tests := []struct {
+		name      string
+		link      InventoryLink
+		category  string // ?category= as typed
+		wantFound bool
+		want      string
+	}{
+		{"built-in category", InventoryLink{LinkType: "Melee Damage", Category: CategoryMelee}, "melee", true, CategoryMelee},
+		{"catalog category", InventoryLink{LinkType: "Boss Damage", Category: "Bossing"}, " BOSSING ", true, "Bossing"},
+		{"legacy link of a catalog type", InventoryLink{LinkType: "Boss Damage", Category: LegacyCategory}, "Bossing", true, "Bossing"},
+		{"custom link the catalog now defines", InventoryLink{LinkType: "Boss Damage", Category: CategoryCustom}, "Bossing", true, "Bossing"},
+		{"unknown type stays custom", InventoryLink{LinkType: "Nothing"}, "custom", true, CategoryCustom},
+		{"unknown category", InventoryLink{LinkType: "Melee Damage", Category: CategoryMelee}, "Raiding", false, CategoryMelee},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			category, ok := catalog.ParseCategory(tt.category)
+			if ok != tt.wantFound {
+				t.Fatalf("ParseCategory(%q) = %q, %v, want found %v", tt.category, category, ok, tt.wantFound)
+			}
+			tt.link.NormalizeCategory(catalog)
+			if tt.link.Category != tt.want {
+				t.Errorf("NormalizeCategory = %q, want %q", tt.link.Category, tt.want)
+			}
+			if ok && category != tt.link.Category {
+				t.Errorf("ParseCategory(%q) = %q, want the link's category %q", tt.category, category, tt.link.Category)
+			}
+		})
+	}
+
+	var builtin *LinkCatalog
+	legacy := InventoryLink{LinkType: "Boss Damage", Category: LegacyCategory}
+	legacy.NormalizeCategory(builtin)
+	if legacy.Category != CategoryCustom {
+		t.Errorf("NormalizeCategory without a catalog = %q, want %q", legacy.Category, CategoryCustom)
+	}
+}
@@ -12,6 +12,23 @@ const (
 	RankMaester  = "Maester"   // Officer, admin access
 )
 
+// Eligibility thresholds in days since joining the guild
+const (
+	SilverEligibilityDays = 30
+	GoldEligibilityDays   = 90
+)
+
+// Notification kinds members can opt out of
+const (
+	NotifyWins          = "wins"          // drawn as a winner
+	NotifyDistributions = "distributions" // received a link
+	NotifyEligibility   = "eligibility"   // became eligible for silver or gold
+	NotifyPromotions    = "promotions"    // promoted to Maester
+)
+
+// AllNotificationKinds lists every notification kind in display order
+var AllNotificationKinds = []string{NotifyWins, NotifyDistributions, NotifyEligibility, NotifyPromotions}
+
 // Member represents a guild member with simplified structure
 type Member struct {
 	DiscordID      string    `json:"discord_id" dynamodbav:"discord_id"`
@@ -25,6 +42,22 @@ type Member struct {
 	AddedBy        string    `json:"added_by" dynamodbav:"added_by"`
 	AddedDate      time.Time `json:"added_date" dynamodbav:"added_date"`
 	UpdatedAt      time.Time `json:"updated_at" dynamodbav:"updated_at"`
+
+	DisabledNotifications []string    `json:"disabled_notifications,omitempty" dynamodbav:"disabled_notifications,omitempty,stringset"` // notification kinds the member opted out of
+	Characters            []Character `json:"characters,omitempty" dynamodbav:"characters,omitempty"`                         // in-game characters links can be traded to
+	BuildTags             []string    `json:"build_tags,omitempty" dynamodbav:"build_tags,omitempty"`                         // how the member plays, used to match link types
+}
+
+// EligibilityChange describes what changed during a rank and eligibility update
+type EligibilityChange struct {
+	PreviousRank string
+	NewlySilver  bool // became silver eligible
+	NewlyGold    bool // became gold eligible
+}
+
+// Changed returns true if the rank or any eligibility flag changed
+func (c EligibilityChange) Changed(m *Member) bool {
+	return c.PreviousRank != m.Rank || c.NewlySilver || c.NewlyGold
 }
 
 // NewMember creates a new member with calculated rank and eligibility
@@ -44,7 +77,18 @@ func NewMember(discordID, username string, joinDate time.Time, addedBy string) *
 }
 
 // UpdateRankAndEligibility calculates and updates rank and eligibility based on join date
-func (m *Member) UpdateRankAndEligibility() {
+func (m *Member) UpdateRankAndEligibility() EligibilityChange {
+	change := EligibilityChange{PreviousRank: m.Rank}
+	wasSilver, wasGold := m.SilverEligible, m.GoldEligible
+
+	m.applyRankAndEligibility()
+
+	change.NewlySilver = !wasSilver && m.SilverEligible
+	change.NewlyGold = !wasGold && m.GoldEligible
+	return change
+}
+
+func (m *Member) applyRankAndEligibility() {
 	m.DaysInGuild = int(time.Since(m.JoinDate).Hours() / 24)
 	m.UpdatedAt = time.Now()
 
@@ -57,11 +101,11 @@ func (m *Member) UpdateRankAndEligibility() {
 	}
 
 	// Calculate rank based on days in guild
-	if m.DaysInGuild < 30 {
+	if m.DaysInGuild < SilverEligibilityDays {
 		m.Rank = RankBookWorm
 		m.SilverEligible = false
 		m.GoldEligible = false
-	} else if m.DaysInGuild < 90 {
+	} else if m.DaysInGuild < GoldEligibilityDays {
 		m.Rank = RankScholar
 		m.SilverEligible = true
 		m.GoldEligible = false
@@ -72,6 +116,26 @@ func (m *Member) UpdateRankAndEligibility() {
 	}
 }
 
+// WantsNotification returns true unless the member opted out of a notification kind
+func (m *Member) WantsNotification(kind string) bool {
+	return !containsString(m.DisabledNotifications, kind)
+}
+
+// SetNotification enables or disables a notification kind for the member
+func (m *Member) SetNotification(kind string, enabled bool) {
+	var disabled []string
+	for _, k := range m.DisabledNotifications {
+		if k != kind {
+			disabled = append(disabled, k)
+		}
+	}
+	if !enabled {
+		disabled = append(disabled, kind)
+	}
+	m.DisabledNotifications = disabled
+	m.UpdatedAt = time.Now()
+}
+
 // PromoteToOfficer promotes member to Maester rank
 func (m *Member) PromoteToOfficer() {
 	m.IsOfficer = true
@@ -87,6 +151,44 @@ func (m *Member) DemoteFromOfficer() {
 	m.UpdateRankAndEligibility()
 }
 
+// IsEligibleFor returns true if member can receive links of the given quality
+func (m *Member) IsEligibleFor(quality string) bool {
+	switch quality {
+	case QualitySilver:
+		return m.SilverEligible
+	case QualityGold:
+		return m.GoldEligible
+	}
+	return false
+}
+
+// EligibleSince returns when the member became eligible for a quality. Officers
+// count as eligible from their join date, since promotions aren't dated.
+func (m *Member) EligibleSince(quality string) (time.Time, bool) {
+	if m.IsOfficer && (quality == QualitySilver || quality == QualityGold) {
+		return m.JoinDate, true
+	}
+	switch quality {
+	case QualitySilver:
+		return m.JoinDate.AddDate(0, 0, SilverEligibilityDays), true
+	case QualityGold:
+		return m.JoinDate.AddDate(0, 0, GoldEligibilityDays), true
+	}
+	return time.Time{}, false
+}
+
+// FilterEligibleMembers refreshes eligibility and returns members eligible for a quality
+func FilterEligibleMembers(members []*Member, quality string) []*Member {
+	var eligible []*Member
+	for _, member := range members {
+		member.UpdateRankAndEligibility()
+		if member.IsEligibleFor(quality) {
+			eligible = append(eligible, member)
+		}
+	}
+	return eligible
+}
+
 // CanEditSystem returns true if member has admin privileges
 func (m *Member) CanEditSystem() bool {
 	return m.IsOfficer && m.Rank == RankMaester
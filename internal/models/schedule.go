@@ -0,0 +1,83 @@
+package models
+
+import (
+	"time"
+)
+
+// Schedule represents a recurring distribution round for one link quality
+type Schedule struct {
+	ScheduleID    string    `json:"schedule_id" dynamodbav:"schedule_id"`
+	Name          string    `json:"name" dynamodbav:"name"`                             // e.g., "Monthly Gold Round"
+	Quality       string    `json:"quality" dynamodbav:"quality"`                       // silver or gold
+	Cron          string    `json:"cron" dynamodbav:"cron"`                             // e.g., "0 20 1 * *" for 20:00 on the 1st
+	TimeZone      string    `json:"time_zone" dynamodbav:"time_zone"`                   // IANA name, e.g. "America/Chicago"
+	ChannelID     string    `json:"channel_id" dynamodbav:"channel_id"`                 // Discord channel for announcements
+	DrawAfter     int       `json:"draw_after_minutes" dynamodbav:"draw_after_minutes"` // minutes after the round opens to draw; 0 = officers draw manually
+	RemindAfter   int       `json:"remind_after_hours" dynamodbav:"remind_after_hours"` // hours a winner may stay pending before officers are reminded; 0 = never
+	IsActive      bool      `json:"is_active" dynamodbav:"is_active"`
+	CreatedBy     string    `json:"created_by" dynamodbav:"created_by"`
+	CreatedAt     time.Time `json:"created_at" dynamodbav:"created_at"`
+	NextRunAt     time.Time `json:"next_run_at" dynamodbav:"next_run_at"`
+	LastRunAt     time.Time `json:"last_run_at,omitempty" dynamodbav:"last_run_at,omitempty"`
+	CurrentListID string    `json:"current_list_id,omitempty" dynamodbav:"current_list_id,omitempty"` // list created by the latest run
+	DrawAt        time.Time `json:"draw_at,omitempty" dynamodbav:"draw_at,omitempty"`                 // when the automatic draw is due; zero once done
+	RemindedAt    time.Time `json:"reminded_at,omitempty" dynamodbav:"reminded_at,omitempty"`         // last reminder about a pending winner
+}
+
+// NewSchedule creates a new active schedule; NextRunAt must be set by the caller
+func NewSchedule(name, quality, cron, timeZone, channelID string, drawAfter, remindAfter int, createdBy string) *Schedule {
+	return &Schedule{
+		ScheduleID:  NewID(IDPrefixSchedule),
+		Name:        name,
+		Quality:     quality,
+		Cron:        cron,
+		TimeZone:    timeZone,
+		ChannelID:   channelID,
+		DrawAfter:   drawAfter,
+		RemindAfter: remindAfter,
+		IsActive:    true,
+		CreatedBy:   createdBy,
+		CreatedAt:   time.Now(),
+	}
+}
+
+// Location returns the schedule's time zone, falling back to UTC
+func (s *Schedule) Location() *time.Location {
+	loc, err := time.LoadLocation(s.TimeZone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// IsDue returns true if the next round should open
+func (s *Schedule) IsDue(now time.Time) bool {
+	return s.IsActive && !s.NextRunAt.IsZero() && !now.Before(s.NextRunAt)
+}
+
+// IsDrawDue returns true if the automatic draw for the current round should run
+func (s *Schedule) IsDrawDue(now time.Time) bool {
+	return s.IsActive && s.CurrentListID != "" && !s.DrawAt.IsZero() && !now.Before(s.DrawAt)
+}
+
+// NeedsReminder returns true if a draw has been pending longer than RemindAfter
+// and officers haven't been reminded within that interval
+func (s *Schedule) NeedsReminder(pending *DrawRecord, now time.Time) bool {
+	if s.RemindAfter <= 0 || pending == nil {
+		return false
+	}
+	interval := time.Duration(s.RemindAfter) * time.Hour
+	return now.Sub(pending.DrawnAt) >= interval && now.Sub(s.RemindedAt) >= interval
+}
+
+// StartRound records that a round opened with a new list
+func (s *Schedule) StartRound(listID string, now, next time.Time) {
+	s.CurrentListID = listID
+	s.LastRunAt = now
+	s.NextRunAt = next
+	s.RemindedAt = time.Time{}
+	s.DrawAt = time.Time{}
+	if s.DrawAfter > 0 {
+		s.DrawAt = now.Add(time.Duration(s.DrawAfter) * time.Minute)
+	}
+}
@@ -0,0 +1,170 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Inventory summary groupings
+const (
+	GroupByLinkType = "link_type"
+	GroupByCategory = "category"
+)
+
+// InventorySummary counts links by link type (or category) and quality
+type InventorySummary map[string]map[string]int
+
+// SummarizeInventory groups links by link type, or by category when groupBy
+// is GroupByCategory, and counts them per quality
+func SummarizeInventory(links []*InventoryLink, groupBy string) InventorySummary {
+	summary := make(InventorySummary)
+	for _, link := range links {
+		key := link.LinkType
+		if groupBy == GroupByCategory {
+			key = link.Category
+		}
+		if summary[key] == nil {
+			summary[key] = make(map[string]int)
+		}
+		summary[key][link.Quality]++
+	}
+	return summary
+}
+
+// QualityTotal returns the number of links of a quality across all groups
+func (s InventorySummary) QualityTotal(quality string) int {
+	total := 0
+	for _, counts := range s {
+		total += counts[quality]
+	}
+	return total
+}
+
+// StockThreshold is the minimum number of available links of a quality,
+// optionally of one link type, the guild wants to keep in stock
+type StockThreshold struct {
+	ThresholdID string    `json:"threshold_id" dynamodbav:"threshold_id"` // see StockThresholdID
+	Quality     string    `json:"quality" dynamodbav:"quality"`
+	LinkType    string    `json:"link_type,omitempty" dynamodbav:"link_type,omitempty"` // empty covers every link type
+	Minimum     int       `json:"minimum" dynamodbav:"minimum"`
+	UpdatedBy   string    `json:"updated_by" dynamodbav:"updated_by"`
+	UpdatedAt   time.Time `json:"updated_at" dynamodbav:"updated_at"`
+	Alerting    bool      `json:"alerting" dynamodbav:"alerting"` // an alert was sent and stock has not recovered yet
+}
+
+// StockThresholdID returns the key of the threshold for a quality and
+// optional link type, e.g. "gold" or "gold:Melee Damage"
+func StockThresholdID(quality, linkType string) string {
+	if linkType == "" {
+		return quality
+	}
+	return quality + ":" + linkType
+}
+
+// NewStockThreshold creates a threshold for a quality and optional link type
+func NewStockThreshold(quality, linkType string, minimum int, updatedBy string) (*StockThreshold, error) {
+	if quality != QualityBronze && quality != QualitySilver && quality != QualityGold {
+		return nil, fmt.Errorf("quality must be bronze, silver, or gold")
+	}
+	if minimum <= 0 {
+		return nil, fmt.Errorf("minimum must be at least 1")
+	}
+	return &StockThreshold{
+		ThresholdID: StockThresholdID(quality, linkType),
+		Quality:     quality,
+		LinkType:    linkType,
+		Minimum:     minimum,
+		UpdatedBy:   updatedBy,
+		UpdatedAt:   time.Now(),
+	}, nil
+}
+
+// Describe returns e.g. "gold Melee Damage" or "gold (all types)"
+func (t *StockThreshold) Describe() string {
+	if t.LinkType == "" {
+		return t.Quality + " (all types)"
+	}
+	return t.Quality + " " + t.LinkType
+}
+
+// Available returns how many links the threshold counts in a link-type summary
+func (t *StockThreshold) Available(summary InventorySummary) int {
+	if t.LinkType == "" {
+		return summary.QualityTotal(t.Quality)
+	}
+	return summary[t.LinkType][t.Quality]
+}
+
+// StockAlert reports a threshold whose available stock is below its minimum
+type StockAlert struct {
+	ThresholdID string `json:"threshold_id"`
+	Quality     string `json:"quality"`
+	LinkType    string `json:"link_type,omitempty"`
+	Minimum     int    `json:"minimum"`
+	Available   int    `json:"available"`
+}
+
+// Describe returns e.g. "gold Melee Damage: 0 of 2"
+func (a StockAlert) Describe() string {
+	name := a.Quality + " " + a.LinkType
+	if a.LinkType == "" {
+		name = a.Quality + " (all types)"
+	}
+	return fmt.Sprintf("%s: %d of %d", name, a.Available, a.Minimum)
+}
+
+// CheckStock compares available links, summarized by link type, against the
+// thresholds and returns an alert for each one that is not met, emptiest first
+func CheckStock(thresholds []*StockThreshold, summary InventorySummary) []StockAlert {
+	var alerts []StockAlert
+	for _, t := range thresholds {
+		available := t.Available(summary)
+		if available >= t.Minimum {
+			continue
+		}
+		alerts = append(alerts, StockAlert{
+			ThresholdID: t.ThresholdID,
+			Quality:     t.Quality,
+			LinkType:    t.LinkType,
+			Minimum:     t.Minimum,
+			Available:   available,
+		})
+	}
+	sort.Slice(alerts, func(i, j int) bool {
+		if alerts[i].Available != alerts[j].Available {
+			return alerts[i].Available < alerts[j].Available
+		}
+		return alerts[i].ThresholdID < alerts[j].ThresholdID
+	})
+	return alerts
+}
+
+// UpdateAlertState marks thresholds as alerting or recovered. It returns the
+// alerts that are new since the last check, so each shortage is announced
+// once, and the thresholds whose state changed and need saving.
+func UpdateAlertState(thresholds []*StockThreshold, alerts []StockAlert) ([]StockAlert, []*StockThreshold) {
+	low := make(map[string]bool)
+	for _, alert := range alerts {
+		low[alert.ThresholdID] = true
+	}
+
+	var changed []*StockThreshold
+	for _, t := range thresholds {
+		if t.Alerting != low[t.ThresholdID] {
+			t.Alerting = low[t.ThresholdID]
+			changed = append(changed, t)
+		}
+	}
+
+	var fresh []StockAlert
+	for _, alert := range alerts {
+		for _, t := range changed {
+			if t.ThresholdID == alert.ThresholdID {
+				fresh = append(fresh, alert)
+				break
+			}
+		}
+	}
+	return fresh, changed
+}
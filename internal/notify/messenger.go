@@ -0,0 +1,125 @@
+package notify
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"flavaflav/internal/models"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Messenger sends direct-message notifications to members through Discord
+type Messenger struct {
+	session *discordgo.Session
+}
+
+// NewMessenger creates a messenger using an existing Discord session
+func NewMessenger(session *discordgo.Session) *Messenger {
+	return &Messenger{session: session}
+}
+
+// NewMessengerFromToken creates a REST-only messenger for processes without a gateway connection
+func NewMessengerFromToken(token string) (*Messenger, error) {
+	session, err := discordgo.New("Bot " + token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Discord session: %v", err)
+	}
+	return NewMessenger(session), nil
+}
+
+// NotifyMember sends an embed to a member unless they opted out of the notification kind.
+// A nil messenger is a no-op so callers don't need to check whether DMs are configured.
+func (m *Messenger) NotifyMember(member *models.Member, kind string, embed *discordgo.MessageEmbed) error {
+	if m == nil || member == nil || !member.WantsNotification(kind) {
+		return nil
+	}
+
+	channel, err := m.session.UserChannelCreate(member.DiscordID)
+	if err != nil {
+		return fmt.Errorf("failed to open DM channel with %s: %v", member.Username, err)
+	}
+
+	if embed.Footer == nil {
+		embed.Footer = &discordgo.MessageEmbedFooter{Text: "Manage these messages with /notifications"}
+	}
+
+	_, err = m.session.ChannelMessageSendEmbed(channel.ID, embed)
+	if err != nil {
+		return fmt.Errorf("failed to send DM to %s: %v", member.Username, err)
+	}
+
+	return nil
+}
+
+// WinEmbed builds the message sent when a member is drawn as a winner
+func WinEmbed(list *models.DistributionList) *discordgo.MessageEmbed {
+	return &discordgo.MessageEmbed{
+		Title:       fmt.Sprintf("🎉 You won a %s link!", list.Quality),
+		Color:       QualityColor(list.Quality),
+		Description: fmt.Sprintf("You were drawn from **%s**. Be online in game so a Maester can hand it over.", list.ListName),
+		Timestamp:   time.Now().Format(time.RFC3339),
+	}
+}
+
+// DistributionEmbed builds the message sent when a member receives a link
+func DistributionEmbed(distribution *models.Distribution) *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{
+		Title:       "You received a mastery link",
+		Color:       QualityColor(distribution.Quality),
+		Description: fmt.Sprintf("**%s** is yours. Enjoy!", distribution.GetDisplayName()),
+		Timestamp:   distribution.DistributedAt.Format(time.RFC3339),
+	}
+	if distribution.Character != "" {
+		embed.Fields = []*discordgo.MessageEmbedField{{Name: "Character", Value: distribution.Character}}
+	}
+	return embed
+}
+
+// EligibilityEmbed builds the message sent when a member becomes newly eligible
+func EligibilityEmbed(member *models.Member, change models.EligibilityChange) *discordgo.MessageEmbed {
+	var qualities []string
+	if change.NewlySilver {
+		qualities = append(qualities, models.QualitySilver)
+	}
+	if change.NewlyGold {
+		qualities = append(qualities, models.QualityGold)
+	}
+
+	quality := models.QualitySilver
+	if change.NewlyGold {
+		quality = models.QualityGold
+	}
+
+	return &discordgo.MessageEmbed{
+		Title:       "You are now eligible for more links",
+		Color:       QualityColor(quality),
+		Description: fmt.Sprintf("After %d days in the guild you can now be drawn for %s links. Your rank is **%s**.", member.DaysInGuild, strings.Join(qualities, " and "), member.Rank),
+		Timestamp:   time.Now().Format(time.RFC3339),
+	}
+}
+
+// PromotionEmbed builds the message sent when a member is promoted to Maester
+func PromotionEmbed(member *models.Member) *discordgo.MessageEmbed {
+	return &discordgo.MessageEmbed{
+		Title:       "You have been promoted",
+		Color:       0x800080,
+		Description: fmt.Sprintf("Congratulations, you are now a **%s**.", member.Rank),
+		Timestamp:   time.Now().Format(time.RFC3339),
+	}
+}
+
+// QualityColor returns the embed color for a link quality
+func QualityColor(quality string) int {
+	switch quality {
+	case models.QualityBronze:
+		return 0xCD7F32
+	case models.QualitySilver:
+		return 0xC0C0C0
+	case models.QualityGold:
+		return 0xFFD700
+	default:
+		return 0x666666
+	}
+}
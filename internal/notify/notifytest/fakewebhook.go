@@ -0,0 +1,82 @@
+// Package notifytest provides a fake Discord webhook for testing notify
+package notifytest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// FakeWebhookServer is a local stand-in for a Discord webhook that captures
+// payloads without posting to a real channel
+type FakeWebhookServer struct {
+	*httptest.Server
+
+	mu         sync.Mutex
+	payloads   []discordgo.WebhookParams
+	failures   []int  // status codes returned before accepting requests
+	retryAfter string // Retry-After sent with 429 responses
+	requests   int
+}
+
+// NewFakeWebhookServer starts a fake webhook server; call Close when done
+func NewFakeWebhookServer() *FakeWebhookServer {
+	f := &FakeWebhookServer{retryAfter: "0"}
+	f.Server = httptest.NewServer(http.HandlerFunc(f.handle))
+	return f
+}
+
+// FailNext makes the next requests fail with the given status codes, in order
+func (f *FakeWebhookServer) FailNext(statusCodes ...int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.failures = append(f.failures, statusCodes...)
+}
+
+// SetRetryAfter sets the Retry-After header, in seconds, sent with 429 responses
+func (f *FakeWebhookServer) SetRetryAfter(seconds string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.retryAfter = seconds
+}
+
+// Requests returns how many requests were received, including failed ones
+func (f *FakeWebhookServer) Requests() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.requests
+}
+
+// Payloads returns every payload accepted so far
+func (f *FakeWebhookServer) Payloads() []discordgo.WebhookParams {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]discordgo.WebhookParams(nil), f.payloads...)
+}
+
+func (f *FakeWebhookServer) handle(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.requests++
+	if len(f.failures) > 0 {
+		status := f.failures[0]
+		f.failures = f.failures[1:]
+		if status == http.StatusTooManyRequests {
+			w.Header().Set("Retry-After", f.retryAfter)
+		}
+		w.WriteHeader(status)
+		return
+	}
+
+	var payload discordgo.WebhookParams
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	f.payloads = append(f.payloads, payload)
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,280 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"flavaflav/internal/models"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Webhook event types; each can be routed to its own channel
+const (
+	EventInventory     = "inventory"     // links added to inventory
+	EventWinners       = "winners"       // winner drawn from a list
+	EventDistributions = "distributions" // link handed to a member
+	EventStock         = "stock"         // available stock fell below a threshold
+)
+
+// webhookEnv maps each event to the environment variable holding its webhook URLs
+var webhookEnv = map[string]string{
+	EventInventory:     "DISCORD_WEBHOOK_INVENTORY_URL",
+	EventWinners:       "DISCORD_WEBHOOK_WINNERS_URL",
+	EventDistributions: "DISCORD_WEBHOOK_DISTRIBUTIONS_URL",
+	EventStock:         "DISCORD_WEBHOOK_STOCK_URL",
+}
+
+// WebhookNotifier posts channel announcements to Discord webhook URLs.
+// Announcements are sent while handling API requests, so retries stop once
+// the retry budget is spent and long rate limits aren't waited out.
+type WebhookNotifier struct {
+	urls          map[string][]string
+	client        *http.Client
+	maxRetries    int
+	backoff       time.Duration
+	maxRetryAfter time.Duration // longest Retry-After worth waiting for
+	budget        time.Duration // total time for one Notify call, retries included
+	username      string
+}
+
+// NewWebhookNotifier creates a notifier with event-to-URL routing
+func NewWebhookNotifier(urls map[string][]string) *WebhookNotifier {
+	return &WebhookNotifier{
+		urls:          urls,
+		client:        &http.Client{Timeout: 3 * time.Second},
+		maxRetries:    3,
+		backoff:       500 * time.Millisecond,
+		maxRetryAfter: 2 * time.Second,
+		budget:        5 * time.Second,
+		username:      "FlavaFlav",
+	}
+}
+
+// NewWebhookNotifierFromEnv reads DISCORD_WEBHOOK_URL as the default for every
+// event plus per-event overrides; URLs may be comma separated. Returns nil if
+// no webhook is configured.
+func NewWebhookNotifierFromEnv() *WebhookNotifier {
+	defaults := splitURLs(os.Getenv("DISCORD_WEBHOOK_URL"))
+	urls := make(map[string][]string)
+	for event, key := range webhookEnv {
+		if override := splitURLs(os.Getenv(key)); len(override) > 0 {
+			urls[event] = override
+		} else if len(defaults) > 0 {
+			urls[event] = defaults
+		}
+	}
+	if len(urls) == 0 {
+		return nil
+	}
+	return NewWebhookNotifier(urls)
+}
+
+// SetRetryPolicy overrides the retry count and base backoff (doubled per attempt)
+func (n *WebhookNotifier) SetRetryPolicy(maxRetries int, backoff time.Duration) {
+	n.maxRetries = maxRetries
+	n.backoff = backoff
+}
+
+// SetRetryLimits overrides the longest Retry-After waited for and the total
+// time one Notify call may take
+func (n *WebhookNotifier) SetRetryLimits(maxRetryAfter, budget time.Duration) {
+	n.maxRetryAfter = maxRetryAfter
+	n.budget = budget
+}
+
+// Notify posts an embed to every webhook configured for an event.
+// A nil notifier is a no-op so callers don't need to check whether webhooks are configured.
+func (n *WebhookNotifier) Notify(ctx context.Context, event string, embed *discordgo.MessageEmbed) error {
+	if n == nil {
+		return nil
+	}
+
+	body, err := json.Marshal(&discordgo.WebhookParams{
+		Username: n.username,
+		Embeds:   []*discordgo.MessageEmbed{embed},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal webhook payload: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, n.budget)
+	defer cancel()
+
+	var failures []string
+	for _, url := range n.urls[event] {
+		if err := n.post(ctx, url, body); err != nil {
+			failures = append(failures, err.Error())
+		}
+	}
+	if len(failures) > 0 {
+		return fmt.Errorf("webhook %s failed: %s", event, strings.Join(failures, "; "))
+	}
+
+	return nil
+}
+
+// post sends one payload, retrying server errors and honouring Discord rate
+// limits until the context's deadline
+func (n *WebhookNotifier) post(ctx context.Context, url string, body []byte) error {
+	delay := n.backoff
+	var lastErr error
+
+	for attempt := 0; attempt <= n.maxRetries; attempt++ {
+		if attempt > 0 {
+			if deadline, ok := ctx.Deadline(); ok && time.Now().Add(delay).After(deadline) {
+				return fmt.Errorf("gave up after %d attempts, out of time: %v", attempt, lastErr)
+			}
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(delay):
+			}
+			delay *= 2
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+		if err != nil {
+			return fmt.Errorf("invalid webhook URL: %v", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := n.client.Do(req)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		resp.Body.Close()
+
+		switch {
+		case resp.StatusCode >= 200 && resp.StatusCode < 300:
+			return nil
+		case resp.StatusCode == http.StatusTooManyRequests:
+			if seconds, err := strconv.ParseFloat(resp.Header.Get("Retry-After"), 64); err == nil {
+				delay = time.Duration(seconds * float64(time.Second))
+			}
+			if delay > n.maxRetryAfter {
+				return fmt.Errorf("rate limited for %v", delay)
+			}
+			lastErr = fmt.Errorf("rate limited")
+		case resp.StatusCode >= 500:
+			lastErr = fmt.Errorf("status %d", resp.StatusCode)
+		default:
+			// Client errors (bad URL, deleted webhook) won't succeed on retry
+			return fmt.Errorf("status %d", resp.StatusCode)
+		}
+	}
+
+	return fmt.Errorf("gave up after %d attempts: %v", n.maxRetries+1, lastErr)
+}
+
+// InventoryAddedEmbed announces links added to inventory
+func InventoryAddedEmbed(links []*models.InventoryLink, addedBy string) *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{
+		Title:       fmt.Sprintf("📦 %d links added to inventory", len(links)),
+		Color:       0x00ff00,
+		Description: countLinks(links),
+		Footer:      &discordgo.MessageEmbedFooter{Text: "Added by " + addedBy},
+		Timestamp:   time.Now().Format(time.RFC3339),
+	}
+	if len(links) > 0 && links[0].DonorName != "" {
+		embed.Fields = []*discordgo.MessageEmbedField{{Name: "🎁 Donated by", Value: links[0].DonorName}}
+	}
+	return embed
+}
+
+// FusionEmbed announces lower-quality links fused into a new link
+func FusionEmbed(inputs []*models.InventoryLink, result *models.InventoryLink, fusedBy string) *discordgo.MessageEmbed {
+	return &discordgo.MessageEmbed{
+		Title:       fmt.Sprintf("⚗️ %d links fused into %s", len(inputs), result.GetDisplayName()),
+		Color:       QualityColor(result.Quality),
+		Description: countLinks(inputs),
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "New Link ID", Value: result.LinkID, Inline: true},
+		},
+		Footer:    &discordgo.MessageEmbedFooter{Text: "Fused by " + fusedBy},
+		Timestamp: time.Now().Format(time.RFC3339),
+	}
+}
+
+// countLinks lists links as "3 × gold Melee Damage (4.50%)" lines in the
+// order they first appear
+func countLinks(links []*models.InventoryLink) string {
+	counts := make(map[string]int)
+	var order []string
+	for _, link := range links {
+		name := link.GetDisplayName()
+		if counts[name] == 0 {
+			order = append(order, name)
+		}
+		counts[name]++
+	}
+
+	var lines []string
+	for _, name := range order {
+		lines = append(lines, fmt.Sprintf("%d × %s", counts[name], name))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// WinnerAnnouncementEmbed announces a winner drawn from a list
+func WinnerAnnouncementEmbed(list *models.DistributionList, winner *models.Member) *discordgo.MessageEmbed {
+	return &discordgo.MessageEmbed{
+		Title:       fmt.Sprintf("🎉 %s Link Winner!", strings.Title(list.Quality)),
+		Color:       QualityColor(list.Quality),
+		Description: fmt.Sprintf("**%s** has been selected for a %s link!", winner.Username, list.Quality),
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "List", Value: list.ListName, Inline: true},
+			{Name: "Total Eligible", Value: strconv.Itoa(list.GetMemberCount()), Inline: true},
+		},
+		Timestamp: time.Now().Format(time.RFC3339),
+	}
+}
+
+// DistributionAnnouncementEmbed announces a link handed to a member
+func DistributionAnnouncementEmbed(distribution *models.Distribution) *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{
+		Title:       "Link Distributed",
+		Color:       QualityColor(distribution.Quality),
+		Description: fmt.Sprintf("**%s** received %s", distribution.MemberUsername, distribution.GetDisplayName()),
+		Footer:      &discordgo.MessageEmbedFooter{Text: "Distributed by " + distribution.DistributedBy},
+		Timestamp:   distribution.DistributedAt.Format(time.RFC3339),
+	}
+	if distribution.Character != "" {
+		embed.Fields = []*discordgo.MessageEmbedField{{Name: "Character", Value: distribution.Character, Inline: true}}
+	}
+	return embed
+}
+
+// StockAlertEmbed warns officers that stock is below one or more thresholds
+func StockAlertEmbed(alerts []models.StockAlert) *discordgo.MessageEmbed {
+	var lines []string
+	for _, alert := range alerts {
+		lines = append(lines, "• "+alert.Describe())
+	}
+
+	return &discordgo.MessageEmbed{
+		Title:       "⚠️ Low link stock",
+		Color:       0xff9900,
+		Description: strings.Join(lines, "\n"),
+		Footer:      &discordgo.MessageEmbedFooter{Text: "Available links vs. minimum stock"},
+		Timestamp:   time.Now().Format(time.RFC3339),
+	}
+}
+
+func splitURLs(value string) []string {
+	var urls []string
+	for _, url := range strings.Split(value, ",") {
+		if url = strings.TrimSpace(url); url != "" {
+			urls = append(urls, url)
+		}
+	}
+	return urls
+}
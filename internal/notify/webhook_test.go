@@ -0,0 +1,153 @@
+package notify
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"flavaflav/internal/notify/notifytest"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestWebhookNotify(t *testing.T) {
+	tests := []struct {
+		name         string
+		failures     []int
+		wantErr      bool
+		wantRequests int
+	}{
+		{name: "delivered", wantRequests: 1},
+		{name: "server errors are retried", failures: []int{http.StatusInternalServerError, http.StatusBadGateway}, wantRequests: 3},
+		{name: "rate limits are retried", failures: []int{http.StatusTooManyRequests}, wantRequests: 2},
+		{name: "gives up after the last retry", failures: []int{500, 500, 500, 500}, wantErr: true, wantRequests: 3},
+		{name: "client errors are not retried", failures: []int{http.StatusNotFound}, wantErr: true, wantRequests: 1},
+		{name: "bad requests are not retried", failures: []int{http.StatusBadRequest}, wantErr: true, wantRequests: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := notifytest.NewFakeWebhookServer()
+			defer server.Close()
+			server.FailNext(tt.failures...)
+
+			notifier := NewWebhookNotifier(map[string][]string{EventWinners: {server.URL}})
+			notifier.SetRetryPolicy(2, time.Millisecond)
+
+			err := notifier.Notify(context.Background(), EventWinners, &discordgo.MessageEmbed{Title: "Winner"})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Notify error = %v, want error %v", err, tt.wantErr)
+			}
+			if got := server.Requests(); got != tt.wantRequests {
+				t.Errorf("requests = %d, want %d", got, tt.wantRequests)
+			}
+
+			payloads := server.Payloads()
+			if tt.wantErr {
+				if len(payloads) != 0 {
+					t.Errorf("payloads = %d, want none", len(payloads))
+				}
+				return
+			}
+			if len(payloads) != 1 || payloads[0].Username != "FlavaFlav" || payloads[0].Embeds[0].Title != "Winner" {
+				t.Errorf("payloads = %+v, want one winner embed from FlavaFlav", payloads)
+			}
+		})
+	}
+}
+
+func TestWebhookHonoursRetryAfter(t *testing.T) {
+	server := notifytest.NewFakeWebhookServer()
+	defer server.Close()
+	server.SetRetryAfter("0.2")
+	server.FailNext(http.StatusTooManyRequests)
+
+	notifier := NewWebhookNotifier(map[string][]string{EventStock: {server.URL}})
+	notifier.SetRetryPolicy(1, time.Millisecond)
+
+	start := time.Now()
+	if err := notifier.Notify(context.Background(), EventStock, &discordgo.MessageEmbed{}); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+	if waited := time.Since(start); waited < 200*time.Millisecond {
+		t.Errorf("retried after %v, want at least the 200ms Retry-After", waited)
+	}
+}
+
+func TestWebhookRouting(t *testing.T) {
+	fallback := notifytest.NewFakeWebhookServer()
+	defer fallback.Close()
+	winners := notifytest.NewFakeWebhookServer()
+	defer winners.Close()
+
+	t.Setenv("DISCORD_WEBHOOK_URL", fallback.URL)
+	t.Setenv("DISCORD_WEBHOOK_WINNERS_URL", winners.URL)
+	notifier := NewWebhookNotifierFromEnv()
+
+	ctx := context.Background()
+	for _, event := range []string{EventInventory, EventWinners, EventDistributions} {
+		if err := notifier.Notify(ctx, event, &discordgo.MessageEmbed{Title: event}); err != nil {
+			t.Fatalf("Notify %s: %v", event, err)
+		}
+	}
+	if got := len(fallback.Payloads()); got != 2 {
+		t.Errorf("default webhook got %d payloads, want 2", got)
+	}
+	if got := winners.Payloads(); len(got) != 1 || got[0].Embeds[0].Title != EventWinners {
+		t.Errorf("winners webhook got %+v, want the winners embed", got)
+	}
+}
+
+func TestNilWebhookNotifier(t *testing.T) {
+	t.Setenv("DISCORD_WEBHOOK_URL", "")
+	for _, key := range webhookEnv {
+		t.Setenv(key, "")
+	}
+	notifier := NewWebhookNotifierFromEnv()
+	if notifier != nil {
+		t.Fatal("NewWebhookNotifierFromEnv without URLs should return nil")
+	}
+	if err := notifier.Notify(context.Background(), EventWinners, &discordgo.MessageEmbed{}); err != nil {
+		t.Errorf("nil notifier: %v", err)
+	}
+}
+
+func TestWebhookRetryLimits(t *testing.T) {
+	tests := []struct {
+		name       string
+		retryAfter string
+		failures   []int
+		budget     time.Duration
+		within     time.Duration
+	}{
+		{name: "long Retry-After is not waited out", retryAfter: "30", failures: []int{http.StatusTooManyRequests}, budget: time.Minute, within: time.Second},
+		{name: "retries stop when the budget is spent", failures: []int{500, 500, 500, 500}, budget: 150 * time.Millisecond, within: time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := notifytest.NewFakeWebhookServer()
+			defer server.Close()
+			if tt.retryAfter != "" {
+				server.SetRetryAfter(tt.retryAfter)
+			}
+			server.FailNext(tt.failures...)
+
+			notifier := NewWebhookNotifier(map[string][]string{EventInventory: {server.URL}})
+			notifier.SetRetryPolicy(3, 100*time.Millisecond)
+			notifier.SetRetryLimits(time.Second, tt.budget)
+
+			start := time.Now()
+			if err := notifier.Notify(context.Background(), EventInventory, &discordgo.MessageEmbed{}); err == nil {
+				t.Fatal("Notify succeeded, want an error")
+			}
+			if took := time.Since(start); took > tt.within {
+				t.Errorf("Notify took %v, want under %v", took, tt.within)
+			}
+			if len(server.Payloads()) != 0 {
+				t.Error("payload was delivered")
+			}
+		})
+	}
+}
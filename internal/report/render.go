@@ -0,0 +1,138 @@
+package report
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"flavaflav/internal/models"
+)
+
+// WriteCSV writes the report as one table with a section column, so every
+// line can be filtered in a spreadsheet:
+//
+//	section,name,gold,silver,bronze,total
+func (r *Report) WriteCSV(w io.Writer) error {
+	out := csv.NewWriter(w)
+	out.Write(append(append([]string{"section", "name"}, qualities...), "total"))
+
+	writeRow := func(section string, row Row) {
+		record := []string{section, csvText(row.Name)}
+		for _, quality := range qualities {
+			record = append(record, strconv.Itoa(row.ByQuality[quality]))
+		}
+		out.Write(append(record, strconv.Itoa(row.Total)))
+	}
+	writeTotal := func(section, name string, total int) {
+		out.Write([]string{section, csvText(name), "", "", "", strconv.Itoa(total)})
+	}
+
+	out.Write([]string{"range", csvText(r.Range.Name), "", "", "", ""})
+	writeRow("summary", r.Distributions)
+	writeTotal("summary", "Reversed", r.Reversed)
+	for _, row := range r.ByMember {
+		writeRow("member", row)
+	}
+	for _, row := range r.ByLinkType {
+		writeRow("link_type", row)
+	}
+	writeRow("inventory", r.InventoryAdded)
+	writeRow("inventory", r.FusionResults)
+	writeRow("inventory", r.Remaining)
+	writeTotal("draws", "Draws run", r.DrawsRun)
+	for _, outcome := range drawOutcomeOrder {
+		if r.DrawOutcomes[outcome] > 0 {
+			writeTotal("draw_outcome", outcome, r.DrawOutcomes[outcome])
+		}
+	}
+	for _, list := range r.DrawsByList {
+		writeTotal("draw_list", list.ListName, list.Draws)
+	}
+
+	out.Flush()
+	if err := out.Error(); err != nil {
+		return fmt.Errorf("failed to write report CSV: %v", err)
+	}
+	return nil
+}
+
+// csvText keeps a name from a member or officer, e.g. a username, from being
+// run as a formula when the CSV is opened in a spreadsheet
+func csvText(text string) string {
+	if text != "" && strings.ContainsAny(text[:1], "=+-@\t\r") {
+		return "'" + text
+	}
+	return text
+}
+
+// Markdown renders the report for posting in Discord or a wiki
+func (r *Report) Markdown() string {
+	var b strings.Builder
+	last := r.Range.To.AddDate(0, 0, -1)
+
+	fmt.Fprintf(&b, "# Link Distribution Report: %s\n\n", r.Range.Name)
+	fmt.Fprintf(&b, "_%s to %s, generated %s_\n\n", r.Range.From.Format("2 Jan 2006"), last.Format("2 Jan 2006"), r.GeneratedAt.Format("2 Jan 2006 15:04 MST"))
+
+	b.WriteString("## Summary\n\n")
+	fmt.Fprintf(&b, "- Links distributed: %d", r.Distributions.Total)
+	if r.Distributions.Total > 0 {
+		fmt.Fprintf(&b, " (%s)", describeQualities(r.Distributions.ByQuality))
+	}
+	b.WriteString("\n")
+	fmt.Fprintf(&b, "- Members who received links: %d\n", len(r.ByMember))
+	if r.Reversed > 0 {
+		fmt.Fprintf(&b, "- Reversed and not counted: %d\n", r.Reversed)
+	}
+	fmt.Fprintf(&b, "- Links added to inventory: %d\n", r.InventoryAdded.Total)
+	fmt.Fprintf(&b, "- Draws run: %d\n\n", r.DrawsRun)
+
+	if len(r.ByMember) > 0 {
+		b.WriteString("## By Member\n\n")
+		writeTable(&b, "Member", r.ByMember)
+	}
+	if len(r.ByLinkType) > 0 {
+		b.WriteString("## By Link Type\n\n")
+		writeTable(&b, "Link Type", r.ByLinkType)
+	}
+
+	b.WriteString("## Inventory\n\n")
+	writeTable(&b, "", []Row{r.InventoryAdded, r.FusionResults, r.Remaining})
+
+	if r.DrawsRun > 0 {
+		b.WriteString("## Draws\n\n")
+		var outcomes []string
+		for _, outcome := range drawOutcomeOrder {
+			if r.DrawOutcomes[outcome] > 0 {
+				outcomes = append(outcomes, fmt.Sprintf("%s %d", outcome, r.DrawOutcomes[outcome]))
+			}
+		}
+		fmt.Fprintf(&b, "%d draws: %s\n\n", r.DrawsRun, strings.Join(outcomes, ", "))
+		b.WriteString("| List | Quality | Draws | Confirmed |\n|---|---|---:|---:|\n")
+		for _, list := range r.DrawsByList {
+			fmt.Fprintf(&b, "| %s | %s | %d | %d |\n", escapeCell(list.ListName), list.Quality, list.Draws, list.ByOutcome[models.DrawConfirmed])
+		}
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+// writeTable renders rows as a Markdown table with a column per quality
+func writeTable(b *strings.Builder, heading string, rows []Row) {
+	fmt.Fprintf(b, "| %s | Gold | Silver | Bronze | Total |\n|---|---:|---:|---:|---:|\n", heading)
+	for _, row := range rows {
+		fmt.Fprintf(b, "| %s |", escapeCell(row.Name))
+		for _, quality := range qualities {
+			fmt.Fprintf(b, " %d |", row.ByQuality[quality])
+		}
+		fmt.Fprintf(b, " %d |\n", row.Total)
+	}
+	b.WriteString("\n")
+}
+
+// escapeCell keeps names containing pipes from breaking a table
+func escapeCell(text string) string {
+	return strings.ReplaceAll(text, "|", `\|`)
+}
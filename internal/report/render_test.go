@@ -0,0 +1,142 @@
+package report
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+	"testing"
+	"time"
+
+	"flavaflav/internal/models"
+)
+
+var september = Range{
+	Name: "September 2026",
+	From: time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC),
+	To:   time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC),
+}
+
+func testReport() *Report {
+	in := september.From.Add(48 * time.Hour)
+	distribution := func(memberID, username, linkType, quality string) *models.Distribution {
+		return &models.Distribution{MemberID: memberID, MemberUsername: username, LinkType: linkType, Quality: quality, DistributedAt: in}
+	}
+	reversed := distribution("3", "carol", "Melee Damage", models.QualityGold)
+	reversed.Status = models.DistributionReversed
+
+	return Build(
+		[]*models.Distribution{
+			distribution("1", "=HYPERLINK(\"http://x\")", "Melee Damage", models.QualityGold),
+			distribution("1", "=HYPERLINK(\"http://x\")", "Spell Damage", models.QualitySilver),
+			distribution("2", "bob | the builder", "Melee Damage", models.QualityGold),
+			reversed,
+			{MemberID: "4", MemberUsername: "dave", LinkType: "Melee Damage", Quality: models.QualityGold, DistributedAt: september.To},
+		},
+		[]*models.InventoryLink{
+			{LinkType: "Melee Damage", Quality: models.QualityGold, Status: models.LinkStatusAvailable, AddedDate: in},
+		},
+		[]*models.DistributionList{
+			{ListID: "list_1", ListName: "+Gold Links", Quality: models.QualityGold, Draws: []models.DrawRecord{
+				{MemberID: "1", Outcome: models.DrawConfirmed, DrawnAt: in},
+				{MemberID: "2", Outcome: models.DrawRerolled, DrawnAt: in},
+			}},
+		},
+		september,
+		september.To,
+	)
+}
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := testReport().WriteCSV(&buf); err != nil {
+		t.Fatalf("WriteCSV: %v", err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("output is not valid CSV: %v", err)
+	}
+
+	rows := make(map[string][]string)
+	for _, record := range records {
+		rows[record[0]+"/"+record[1]] = record
+	}
+
+	tests := []struct {
+		key  string
+		want string // the row joined with commas
+	}{
+		{"section/name", "section,name,gold,silver,bronze,total"},
+		{"range/September 2026", "range,September 2026,,,,"},
+		{"summary/Distributed", "summary,Distributed,2,1,0,3"},
+		{"summary/Reversed", "summary,Reversed,,,,1"},
+		{`member/'=HYPERLINK("http://x")`, `member,'=HYPERLINK("http://x"),1,1,0,2`},
+		{"member/bob | the builder", "member,bob | the builder,1,0,0,1"},
+		{"link_type/Melee Damage", "link_type,Melee Damage,2,0,0,2"},
+		{"inventory/Available now", "inventory,Available now,1,0,0,1"},
+		{"draws/Draws run", "draws,Draws run,,,,2"},
+		{"draw_outcome/rerolled", "draw_outcome,rerolled,,,,1"},
+		{"draw_list/'+Gold Links", "draw_list,'+Gold Links,,,,2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			row, ok := rows[tt.key]
+			if !ok {
+				t.Fatalf("no %s row in\n%v", tt.key, records)
+			}
+			if got := strings.Join(row, ","); got != tt.want {
+				t.Errorf("row = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCSVText(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"alice", "alice"},
+		{"", ""},
+		{"=1+1", "'=1+1"},
+		{"+44 123", "'+44 123"},
+		{"-2", "'-2"},
+		{"@SUM(A1)", "'@SUM(A1)"},
+		{"\t=1", "'\t=1"},
+		{"a=b", "a=b"},
+	}
+	for _, tt := range tests {
+		if got := csvText(tt.text); got != tt.want {
+			t.Errorf("csvText(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	markdown := testReport().Markdown()
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"title", "# Link Distribution Report: September 2026\n"},
+		{"date range", "_1 Sep 2026 to 30 Sep 2026, generated 1 Oct 2026 00:00 UTC_"},
+		{"summary", "- Links distributed: 3 (gold 2, silver 1)\n"},
+		{"reversed", "- Reversed and not counted: 1\n"},
+		{"members", "- Members who received links: 2\n"},
+		{"pipes are escaped", `| bob \| the builder | 1 | 0 | 0 | 1 |`},
+		{"link types", "| Melee Damage | 2 | 0 | 0 | 2 |"},
+		{"draw outcomes", "2 draws: confirmed 1, rerolled 1\n"},
+		{"draws by list", "| +Gold Links | gold | 2 | 1 |"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !strings.Contains(markdown, tt.want) {
+				t.Errorf("Markdown is missing %q:\n%s", tt.want, markdown)
+			}
+		})
+	}
+
+	if empty := Build(nil, nil, nil, september, september.To).Markdown(); strings.Contains(empty, "## By Member") || strings.Contains(empty, "## Draws") {
+		t.Errorf("empty report has member or draw sections:\n%s", empty)
+	}
+}
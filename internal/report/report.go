@@ -0,0 +1,240 @@
+// Package report builds the distribution report leadership posts each month,
+// and renders it as CSV or Markdown.
+package report
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"flavaflav/internal/models"
+)
+
+// Report formats
+const (
+	FormatJSON     = "json"
+	FormatCSV      = "csv"
+	FormatMarkdown = "markdown"
+)
+
+// qualities lists link qualities in report column order
+var qualities = []string{models.QualityGold, models.QualitySilver, models.QualityBronze}
+
+// Range is a half-open time range [From, To) covered by a report
+type Range struct {
+	Name string    `json:"name"` // e.g., "September 2026"
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+}
+
+// Contains returns true if t falls within the range
+func (r Range) Contains(t time.Time) bool {
+	return !t.Before(r.From) && t.Before(r.To)
+}
+
+// ParseRange reads a month (YYYY-MM) or an inclusive from/to date range
+// (YYYY-MM-DD) in a time zone. With neither, it returns the last full month.
+func ParseRange(month, from, to string, loc *time.Location, now time.Time) (Range, error) {
+	if month != "" && (from != "" || to != "") {
+		return Range{}, fmt.Errorf("use either month or from/to, not both")
+	}
+
+	if from != "" || to != "" {
+		if from == "" || to == "" {
+			return Range{}, fmt.Errorf("from and to are both required")
+		}
+		start, err := time.ParseInLocation("2006-01-02", from, loc)
+		if err != nil {
+			return Range{}, fmt.Errorf("invalid from date %q (use YYYY-MM-DD)", from)
+		}
+		end, err := time.ParseInLocation("2006-01-02", to, loc)
+		if err != nil {
+			return Range{}, fmt.Errorf("invalid to date %q (use YYYY-MM-DD)", to)
+		}
+		if end.Before(start) {
+			return Range{}, fmt.Errorf("to is before from")
+		}
+		return Range{Name: from + " to " + to, From: start, To: end.AddDate(0, 0, 1)}, nil
+	}
+
+	var start time.Time
+	if month == "" {
+		local := now.In(loc)
+		start = time.Date(local.Year(), local.Month(), 1, 0, 0, 0, 0, loc).AddDate(0, -1, 0)
+	} else {
+		var err error
+		start, err = time.ParseInLocation("2006-01", month, loc)
+		if err != nil {
+			return Range{}, fmt.Errorf("invalid month %q (use YYYY-MM)", month)
+		}
+	}
+	return Range{Name: start.Format("January 2006"), From: start, To: start.AddDate(0, 1, 0)}, nil
+}
+
+// Row counts links for one member, link type or inventory line by quality
+type Row struct {
+	Name      string         `json:"name"`
+	ByQuality map[string]int `json:"by_quality"`
+	Total     int            `json:"total"`
+}
+
+func (r *Row) add(quality string) {
+	if r.ByQuality == nil {
+		r.ByQuality = make(map[string]int)
+	}
+	r.ByQuality[quality]++
+	r.Total++
+}
+
+// ListDraws counts the draws run from one distribution list
+type ListDraws struct {
+	ListID    string         `json:"list_id"`
+	ListName  string         `json:"list_name"`
+	Quality   string         `json:"quality"`
+	Draws     int            `json:"draws"`
+	ByOutcome map[string]int `json:"by_outcome"`
+}
+
+// Report summarises distributions, inventory and draws over a range
+type Report struct {
+	Range         Range     `json:"range"`
+	GeneratedAt   time.Time `json:"generated_at"`
+	Distributions Row       `json:"distributions"` // links distributed, not counting reversed ones
+	Reversed      int       `json:"reversed"`      // distributions in the range that were later reversed
+	ByMember      []Row     `json:"by_member"`
+	ByLinkType    []Row     `json:"by_link_type"`
+
+	InventoryAdded Row `json:"inventory_added"` // links entered, not counting fusion results
+	FusionResults  Row `json:"fusion_results"`  // links made by fusion
+	Remaining      Row `json:"remaining"`       // available when the report was generated
+
+	DrawsRun     int            `json:"draws_run"`
+	DrawOutcomes map[string]int `json:"draw_outcomes"` // current outcome of each draw
+	DrawsByList  []ListDraws    `json:"draws_by_list"`
+}
+
+// Build computes the report for a range from every distribution, inventory
+// link and distribution list
+func Build(distributions []*models.Distribution, links []*models.InventoryLink, lists []*models.DistributionList, rng Range, now time.Time) *Report {
+	report := &Report{
+		Range:          rng,
+		GeneratedAt:    now,
+		Distributions:  Row{Name: "Distributed"},
+		InventoryAdded: Row{Name: "Added"},
+		FusionResults:  Row{Name: "Made by fusion"},
+		Remaining:      Row{Name: "Available now"},
+		DrawOutcomes:   make(map[string]int),
+	}
+
+	byMember := make(map[string]*Row)
+	byLinkType := make(map[string]*Row)
+	for _, d := range distributions {
+		if !rng.Contains(d.DistributedAt) {
+			continue
+		}
+		if d.IsReversed() {
+			report.Reversed++
+			continue
+		}
+		report.Distributions.add(d.Quality)
+
+		member, ok := byMember[d.MemberID]
+		if !ok {
+			member = &Row{}
+			byMember[d.MemberID] = member
+		}
+		member.Name = d.MemberUsername
+		member.add(d.Quality)
+
+		linkType, ok := byLinkType[d.LinkType]
+		if !ok {
+			linkType = &Row{Name: d.LinkType}
+			byLinkType[d.LinkType] = linkType
+		}
+		linkType.add(d.Quality)
+	}
+	report.ByMember = sortedRows(byMember)
+	report.ByLinkType = sortedRows(byLinkType)
+
+	for _, link := range links {
+		if link.Status == models.LinkStatusAvailable {
+			report.Remaining.add(link.Quality)
+		}
+		if !rng.Contains(link.AddedDate) {
+			continue
+		}
+		if link.IsFusionResult() {
+			report.FusionResults.add(link.Quality)
+		} else {
+			report.InventoryAdded.add(link.Quality)
+		}
+	}
+
+	for _, list := range lists {
+		entry := ListDraws{ListID: list.ListID, ListName: list.ListName, Quality: list.Quality, ByOutcome: make(map[string]int)}
+		for _, draw := range list.Draws {
+			if !rng.Contains(draw.DrawnAt) {
+				continue
+			}
+			entry.Draws++
+			entry.ByOutcome[draw.Outcome]++
+			report.DrawOutcomes[draw.Outcome]++
+		}
+		if entry.Draws > 0 {
+			report.DrawsRun += entry.Draws
+			report.DrawsByList = append(report.DrawsByList, entry)
+		}
+	}
+	sort.Slice(report.DrawsByList, func(i, j int) bool {
+		return report.DrawsByList[i].ListName < report.DrawsByList[j].ListName
+	})
+
+	return report
+}
+
+// sortedRows returns rows with the most links first, then by name
+func sortedRows(rows map[string]*Row) []Row {
+	sorted := make([]Row, 0, len(rows))
+	for _, row := range rows {
+		sorted = append(sorted, *row)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Total != sorted[j].Total {
+			return sorted[i].Total > sorted[j].Total
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+	return sorted
+}
+
+// Filename returns a download name for the report in a format, e.g.
+// "flavaflav-report-2026-09-01-to-2026-09-30.csv"
+func (r *Report) Filename(format string) string {
+	ext := "json"
+	switch format {
+	case FormatCSV:
+		ext = "csv"
+	case FormatMarkdown:
+		ext = "md"
+	}
+	last := r.Range.To.AddDate(0, 0, -1)
+	return fmt.Sprintf("flavaflav-report-%s-to-%s.%s", r.Range.From.Format("2006-01-02"), last.Format("2006-01-02"), ext)
+}
+
+// drawOutcomeOrder lists draw outcomes in report order
+var drawOutcomeOrder = []string{
+	models.DrawConfirmed, models.DrawReserved, models.DrawPending, models.DrawRerolled,
+	models.DrawCancelled, models.DrawExpired, models.DrawReversed,
+}
+
+// describeQualities renders counts like "gold 2, silver 3"
+func describeQualities(counts map[string]int) string {
+	var parts []string
+	for _, quality := range qualities {
+		if counts[quality] > 0 {
+			parts = append(parts, fmt.Sprintf("%s %d", quality, counts[quality]))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,97 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCron(t *testing.T) {
+	tests := []struct {
+		expr    string
+		wantErr bool
+	}{
+		{expr: "0 20 1 * *"},
+		{expr: "*/15 * * * *"},
+		{expr: "0-30/10 9-17 * * 1-5"},
+		{expr: "0 0 1,15 * *"},
+		{expr: "0 12 * * 7"},
+		{expr: "  @monthly "},
+		{expr: "@weekly"},
+		{expr: "0 20 1 *", wantErr: true},
+		{expr: "0 20 1 * * *", wantErr: true},
+		{expr: "60 * * * *", wantErr: true},
+		{expr: "0 24 * * *", wantErr: true},
+		{expr: "0 0 0 * *", wantErr: true},
+		{expr: "0 0 * 13 *", wantErr: true},
+		{expr: "0 0 * * 8", wantErr: true},
+		{expr: "0 10-5 * * *", wantErr: true},
+		{expr: "*/0 * * * *", wantErr: true},
+		{expr: "a * * * *", wantErr: true},
+		{expr: "@often", wantErr: true},
+		{expr: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			_, err := ParseCron(tt.expr)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseCron(%q) error = %v, want error %v", tt.expr, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCronNext(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+	utc := func(s string) time.Time {
+		v, _ := time.Parse("2006-01-02 15:04", s)
+		return v
+	}
+	ny := func(s string) time.Time {
+		v, _ := time.ParseInLocation("2006-01-02 15:04", s, newYork)
+		return v
+	}
+
+	tests := []struct {
+		name string
+		expr string
+		from time.Time
+		want time.Time
+	}{
+		{"later the same day", "0 20 1 * *", utc("2026-03-01 12:00"), utc("2026-03-01 20:00")},
+		{"strictly after", "0 20 1 * *", utc("2026-03-01 20:00"), utc("2026-04-01 20:00")},
+		{"seconds are dropped", "*/15 * * * *", utc("2026-03-01 10:14").Add(59 * time.Second), utc("2026-03-01 10:15")},
+		{"month rollover", "30 9 * * *", utc("2026-01-31 10:00"), utc("2026-02-01 09:30")},
+		{"leap day", "0 0 29 2 *", utc("2026-03-01 00:00"), utc("2028-02-29 00:00")},
+		{"weekday", "0 18 * * 5", utc("2026-10-18 00:00"), utc("2026-10-23 18:00")},
+		{"7 is Sunday", "0 18 * * 7", utc("2026-10-19 00:00"), utc("2026-10-25 18:00")},
+		{"either day field matches", "0 0 13 * 5", utc("2026-10-01 00:00"), utc("2026-10-02 00:00")},
+		{"never within five years", "0 0 31 2 *", utc("2026-01-01 00:00"), time.Time{}},
+		{"local time", "0 20 1 * *", ny("2026-03-01 12:00"), ny("2026-03-01 20:00")},
+		{"across spring forward", "0 20 * * *", ny("2026-03-07 21:00"), ny("2026-03-08 20:00")},
+		{"first hour after the gap", "0 3 * * *", ny("2026-03-08 01:37"), ny("2026-03-08 03:00")},
+		{"skipped time waits a day", "30 2 * * *", ny("2026-03-08 00:00"), ny("2026-03-09 02:30")},
+		{"across fall back", "0 20 * * *", ny("2026-10-31 21:00"), ny("2026-11-01 20:00")},
+		{"repeated time runs once", "30 1 * * *", ny("2026-11-01 00:30").Add(time.Hour), ny("2026-11-02 01:30")},
+		{"repeated hour every quarter", "*/15 1 * * *", ny("2026-11-01 00:45").Add(time.Hour), ny("2026-11-02 01:00")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cron, err := ParseCron(tt.expr)
+			if err != nil {
+				t.Fatalf("ParseCron(%q): %v", tt.expr, err)
+			}
+			got := cron.Next(tt.from)
+			if !got.Equal(tt.want) {
+				t.Errorf("Next(%v) = %v, want %v", tt.from, got, tt.want)
+			}
+			if !got.IsZero() && got.Location() != tt.from.Location() {
+				t.Errorf("Next returned %v, want it in %v", got.Location(), tt.from.Location())
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package stats
+
+import (
+	"sort"
+	"time"
+
+	"flavaflav/internal/models"
+)
+
+// MemberAttendance summarises the events one member attended
+type MemberAttendance struct {
+	MemberID       string         `json:"member_id"`
+	MemberUsername string         `json:"member_username"`
+	Events         int            `json:"events"`
+	ByKind         map[string]int `json:"by_kind"`
+	LastAttendedAt time.Time      `json:"last_attended_at,omitempty"`
+}
+
+// AttendanceBoard lists every member with the events they attended that
+// started within the period, most events first, so members who attended
+// nothing are listed too
+func AttendanceBoard(events []*models.Event, members []*models.Member, period Period) []MemberAttendance {
+	byMember := make(map[string]*MemberAttendance, len(members))
+	for _, member := range members {
+		byMember[member.DiscordID] = &MemberAttendance{
+			MemberID:       member.DiscordID,
+			MemberUsername: member.Username,
+			ByKind:         make(map[string]int),
+		}
+	}
+
+	for _, event := range events {
+		if !period.Contains(event.StartsAt) {
+			continue
+		}
+		for _, id := range event.Attendees {
+			entry, ok := byMember[id]
+			if !ok {
+				continue // no longer a member
+			}
+			entry.Events++
+			entry.ByKind[event.Kind]++
+			if event.StartsAt.After(entry.LastAttendedAt) {
+				entry.LastAttendedAt = event.StartsAt
+			}
+		}
+	}
+
+	board := make([]MemberAttendance, 0, len(byMember))
+	for _, entry := range byMember {
+		board = append(board, *entry)
+	}
+	sort.Slice(board, func(i, j int) bool {
+		if board[i].Events != board[j].Events {
+			return board[i].Events > board[j].Events
+		}
+		return board[i].MemberUsername < board[j].MemberUsername
+	})
+
+	return board
+}
+
+// EventsIn returns the events that started within the period, newest first
+func EventsIn(events []*models.Event, period Period) []*models.Event {
+	var in []*models.Event
+	for _, event := range events {
+		if period.Contains(event.StartsAt) {
+			in = append(in, event)
+		}
+	}
+	sort.Slice(in, func(i, j int) bool {
+		return in[i].StartsAt.After(in[j].StartsAt)
+	})
+	return in
+}
@@ -0,0 +1,74 @@
+package stats
+
+import (
+	"sort"
+	"time"
+
+	"flavaflav/internal/models"
+)
+
+// MemberDonations summarises the links one member donated
+type MemberDonations struct {
+	MemberID       string         `json:"member_id"`
+	MemberUsername string         `json:"member_username"`
+	Total          int            `json:"total"`
+	ByQuality      map[string]int `json:"by_quality"`
+	LastDonationAt time.Time      `json:"last_donation_at"`
+}
+
+// DonationLeaderboard ranks members by links donated within the period,
+// optionally limited to one quality. Ties go to the member with more gold,
+// then more silver links.
+func DonationLeaderboard(links []*models.InventoryLink, period Period, quality string) []MemberDonations {
+	byDonor := make(map[string]*MemberDonations)
+	for _, link := range links {
+		if !link.CountsAsDonation() || !period.Contains(link.AddedDate) || (quality != "" && link.Quality != quality) {
+			continue
+		}
+		entry, ok := byDonor[link.DonatedBy]
+		if !ok {
+			entry = &MemberDonations{MemberID: link.DonatedBy, ByQuality: make(map[string]int)}
+			byDonor[link.DonatedBy] = entry
+		}
+		entry.Total++
+		entry.ByQuality[link.Quality]++
+		if link.AddedDate.After(entry.LastDonationAt) {
+			entry.LastDonationAt = link.AddedDate
+			entry.MemberUsername = link.DonorName // keep the most recent name
+		}
+	}
+
+	board := make([]MemberDonations, 0, len(byDonor))
+	for _, entry := range byDonor {
+		board = append(board, *entry)
+	}
+	sort.Slice(board, func(i, j int) bool {
+		a, b := board[i], board[j]
+		if a.Total != b.Total {
+			return a.Total > b.Total
+		}
+		if a.ByQuality[models.QualityGold] != b.ByQuality[models.QualityGold] {
+			return a.ByQuality[models.QualityGold] > b.ByQuality[models.QualityGold]
+		}
+		if a.ByQuality[models.QualitySilver] != b.ByQuality[models.QualitySilver] {
+			return a.ByQuality[models.QualitySilver] > b.ByQuality[models.QualitySilver]
+		}
+		return a.MemberUsername < b.MemberUsername
+	})
+
+	return board
+}
+
+// DonationsBy returns the links a member donated, newest first
+func DonationsBy(links []*models.InventoryLink, memberID string) []*models.InventoryLink {
+	var donated []*models.InventoryLink
+	for _, link := range links {
+		if link.DonatedBy == memberID && link.CountsAsDonation() {
+			donated = append(donated, link)
+		}
+	}
+	sort.Slice(donated, func(i, j int) bool {
+		return donated[i].AddedDate.After(donated[j].AddedDate)
+	})
+	return donated
+}
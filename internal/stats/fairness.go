@@ -0,0 +1,185 @@
+package stats
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"flavaflav/internal/models"
+)
+
+// fairnessWaitsListed limits the longest waits reported per quality
+const fairnessWaitsListed = 10
+
+// FairnessQualities are the qualities with eligibility rules to measure
+var FairnessQualities = []string{models.QualitySilver, models.QualityGold}
+
+// MemberFairness compares the links of one quality a member received with
+// their share of the guild's eligible days
+type MemberFairness struct {
+	MemberID       string    `json:"member_id"`
+	MemberUsername string    `json:"member_username"`
+	EligibleSince  time.Time `json:"eligible_since"`
+	EligibleDays   int       `json:"eligible_days"` // days eligible within the period
+	ExpectedWins   float64   `json:"expected_wins"`
+	ActualWins     int       `json:"actual_wins"`
+	Difference     float64   `json:"difference"` // actual minus expected
+	LastAwardAt    time.Time `json:"last_award_at"`
+	WaitDays       int       `json:"wait_days"` // since the last award, or since becoming eligible
+}
+
+// QualityFairness is the fairness analysis for one quality
+type QualityFairness struct {
+	Quality         string           `json:"quality"`
+	Awards          int              `json:"awards"`           // awards to current members in the period
+	OtherAwards     int              `json:"other_awards"`     // awards to members no longer in the guild
+	EligibleMembers int              `json:"eligible_members"` // members eligible for part of the period
+	Gini            float64          `json:"gini"`             // 0 = everyone won the same, 1 = one member won everything
+	ExpectedGini    float64          `json:"expected_gini"`    // the Gini coefficient tenure alone would give
+	Members         []MemberFairness `json:"members"`          // most under-served first
+	LongestWaits    []MemberFairness `json:"longest_waits"`
+}
+
+// FairnessReport is the fairness analysis for a period
+type FairnessReport struct {
+	Period    Period            `json:"period"`
+	Qualities []QualityFairness `json:"qualities"`
+}
+
+// Fairness compares each eligible member's awards with what a fair share of
+// the awards would be, weighting members by the days they were eligible
+// within the period. Eligibility follows the tenure rules only: attendance
+// and build matching at draw time are not replayed. Reversed distributions
+// are not counted. An empty quality analyses silver and gold.
+func Fairness(distributions []*models.Distribution, members []*models.Member, period Period, quality string, now time.Time) *FairnessReport {
+	qualities := FairnessQualities
+	if quality != "" {
+		qualities = []string{quality}
+	}
+
+	report := &FairnessReport{Period: period}
+	for _, q := range qualities {
+		report.Qualities = append(report.Qualities, qualityFairness(distributions, members, period, q, now))
+	}
+	return report
+}
+
+func qualityFairness(distributions []*models.Distribution, members []*models.Member, period Period, quality string, now time.Time) QualityFairness {
+	end := period.To
+	if end.After(now) {
+		end = now
+	}
+
+	result := QualityFairness{Quality: quality, Members: []MemberFairness{}, LongestWaits: []MemberFairness{}}
+	byMember := make(map[string]*MemberFairness)
+	totalDays := 0
+	for _, member := range members {
+		since, ok := member.EligibleSince(quality)
+		if !ok || !since.Before(end) {
+			continue
+		}
+		start := since
+		if start.Before(period.From) {
+			start = period.From
+		}
+		days := int(end.Sub(start).Hours() / 24)
+		if days <= 0 {
+			continue
+		}
+		byMember[member.DiscordID] = &MemberFairness{
+			MemberID:       member.DiscordID,
+			MemberUsername: member.Username,
+			EligibleSince:  since,
+			EligibleDays:   days,
+		}
+		totalDays += days
+	}
+
+	for _, d := range distributions {
+		if d.IsReversed() || d.Quality != quality || d.DistributedAt.After(end) {
+			continue
+		}
+		entry, ok := byMember[d.MemberID]
+		if ok && d.DistributedAt.After(entry.LastAwardAt) {
+			entry.LastAwardAt = d.DistributedAt
+		}
+		if !period.Contains(d.DistributedAt) {
+			continue
+		}
+		if !ok {
+			result.OtherAwards++
+			continue
+		}
+		entry.ActualWins++
+		result.Awards++
+	}
+
+	actual := make([]float64, 0, len(byMember))
+	expected := make([]float64, 0, len(byMember))
+	for _, entry := range byMember {
+		if totalDays > 0 {
+			entry.ExpectedWins = round2(float64(result.Awards) * float64(entry.EligibleDays) / float64(totalDays))
+		}
+		entry.Difference = round2(float64(entry.ActualWins) - entry.ExpectedWins)
+
+		waitFrom := entry.EligibleSince
+		if entry.LastAwardAt.After(waitFrom) {
+			waitFrom = entry.LastAwardAt
+		}
+		entry.WaitDays = int(end.Sub(waitFrom).Hours() / 24)
+
+		result.Members = append(result.Members, *entry)
+		actual = append(actual, float64(entry.ActualWins))
+		expected = append(expected, entry.ExpectedWins)
+	}
+	result.EligibleMembers = len(result.Members)
+	result.Gini = round2(Gini(actual))
+	result.ExpectedGini = round2(Gini(expected))
+
+	sort.Slice(result.Members, func(i, j int) bool {
+		a, b := result.Members[i], result.Members[j]
+		if a.Difference != b.Difference {
+			return a.Difference < b.Difference
+		}
+		return a.MemberUsername < b.MemberUsername
+	})
+
+	waits := append([]MemberFairness(nil), result.Members...)
+	sort.Slice(waits, func(i, j int) bool {
+		if waits[i].WaitDays != waits[j].WaitDays {
+			return waits[i].WaitDays > waits[j].WaitDays
+		}
+		return waits[i].MemberUsername < waits[j].MemberUsername
+	})
+	if len(waits) > fairnessWaitsListed {
+		waits = waits[:fairnessWaitsListed]
+	}
+	result.LongestWaits = waits
+
+	return result
+}
+
+// Gini returns the Gini coefficient of non-negative values: 0 when all are
+// equal, approaching 1 when one value holds everything
+func Gini(values []float64) float64 {
+	n := len(values)
+	if n == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	var sum, weighted float64
+	for i, v := range sorted {
+		sum += v
+		weighted += float64(i+1) * v
+	}
+	if sum == 0 {
+		return 0
+	}
+	return 2*weighted/(float64(n)*sum) - float64(n+1)/float64(n)
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,106 @@
+package stats
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"flavaflav/internal/models"
+)
+
+func TestGini(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []float64
+		want   float64
+	}{
+		{"no members", nil, 0},
+		{"nobody won", []float64{0, 0, 0}, 0},
+		{"everyone won the same", []float64{2, 2, 2}, 0},
+		{"one of four won everything", []float64{0, 4, 0, 0}, 0.75},
+		{"uneven", []float64{3, 0, 1}, 0.5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Gini(tt.values); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("Gini(%v) = %v, want %v", tt.values, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFairness(t *testing.T) {
+	period := Period{
+		Name: "September 2026",
+		From: time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC),
+	}
+	day := func(month time.Month, d int) time.Time { return time.Date(2026, month, d, 0, 0, 0, 0, time.UTC) }
+
+	longAgo := day(1, 1)
+	members := []*models.Member{
+		{DiscordID: "1", Username: "alice", JoinDate: longAgo},
+		{DiscordID: "2", Username: "bob", JoinDate: longAgo},
+		{DiscordID: "3", Username: "carol", JoinDate: day(9, 16).AddDate(0, 0, -models.GoldEligibilityDays)},
+		{DiscordID: "4", Username: "dave", JoinDate: day(9, 20)}, // not gold eligible yet
+	}
+	gold := func(memberID string, at time.Time) *models.Distribution {
+		return &models.Distribution{MemberID: memberID, Quality: models.QualityGold, DistributedAt: at}
+	}
+	reversed := gold("2", day(9, 25))
+	reversed.Status = models.DistributionReversed
+	distributions := []*models.Distribution{
+		gold("1", day(9, 5)), gold("1", day(9, 10)), gold("1", day(9, 20)),
+		gold("2", day(8, 1)), gold("2", day(9, 2)),
+		reversed,
+		gold("9", day(9, 3)), // member has left
+		{MemberID: "3", Quality: models.QualitySilver, DistributedAt: day(9, 4)},
+	}
+
+	report := Fairness(distributions, members, period, models.QualityGold, period.To)
+	if len(report.Qualities) != 1 {
+		t.Fatalf("got %d qualities, want gold only", len(report.Qualities))
+	}
+	q := report.Qualities[0]
+	if q.Awards != 4 || q.OtherAwards != 1 || q.EligibleMembers != 3 {
+		t.Errorf("awards = %d, other = %d, eligible = %d, want 4, 1, 3", q.Awards, q.OtherAwards, q.EligibleMembers)
+	}
+	if q.Gini != 0.5 || q.ExpectedGini != 0.13 {
+		t.Errorf("Gini = %v, expected Gini = %v, want 0.5 and 0.13", q.Gini, q.ExpectedGini)
+	}
+
+	// Most under-served first
+	tests := []struct {
+		username   string
+		days       int
+		expected   float64
+		actual     int
+		difference float64
+		wait       int
+	}{
+		{"carol", 15, 0.8, 0, -0.8, 15},
+		{"bob", 30, 1.6, 1, -0.6, 29},
+		{"alice", 30, 1.6, 3, 1.4, 11},
+	}
+	for i, tt := range tests {
+		t.Run(tt.username, func(t *testing.T) {
+			m := q.Members[i]
+			if m.MemberUsername != tt.username {
+				t.Fatalf("member %d = %s, want %s", i, m.MemberUsername, tt.username)
+			}
+			if m.EligibleDays != tt.days || m.ExpectedWins != tt.expected || m.ActualWins != tt.actual || m.Difference != tt.difference || m.WaitDays != tt.wait {
+				t.Errorf("got %d days, expected %v, actual %d, difference %v, wait %d; want %d, %v, %d, %v, %d",
+					m.EligibleDays, m.ExpectedWins, m.ActualWins, m.Difference, m.WaitDays,
+					tt.days, tt.expected, tt.actual, tt.difference, tt.wait)
+			}
+		})
+	}
+
+	if waits := q.LongestWaits; len(waits) != 3 || waits[0].MemberUsername != "bob" || waits[2].MemberUsername != "alice" {
+		t.Errorf("LongestWaits = %+v, want bob, carol, alice", waits)
+	}
+
+	if all := Fairness(distributions, members, period, "", period.To); len(all.Qualities) != len(FairnessQualities) {
+		t.Errorf("got %d qualities without a filter, want %d", len(all.Qualities), len(FairnessQualities))
+	}
+}
@@ -0,0 +1,194 @@
+package stats
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"flavaflav/internal/models"
+)
+
+// Period is a half-open time range [From, To); a zero From means "since the beginning"
+type Period struct {
+	Name string    `json:"name"`
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+}
+
+// Contains returns true if t falls within the period
+func (p Period) Contains(t time.Time) bool {
+	return !t.Before(p.From) && t.Before(p.To)
+}
+
+// ParsePeriod understands "week", "month", "quarter", "year", "all" and day
+// counts such as "30d"; an empty name means "all"
+func ParsePeriod(name string, now time.Time) (Period, error) {
+	p := Period{Name: name, To: now.Add(time.Second)}
+	switch name {
+	case "", "all":
+		p.Name = "all"
+	case "week":
+		p.From = now.AddDate(0, 0, -7)
+	case "month":
+		p.From = now.AddDate(0, -1, 0)
+	case "quarter":
+		p.From = now.AddDate(0, -3, 0)
+	case "year":
+		p.From = now.AddDate(-1, 0, 0)
+	default:
+		days, err := strconv.Atoi(strings.TrimSuffix(name, "d"))
+		if !strings.HasSuffix(name, "d") || err != nil || days <= 0 {
+			return Period{}, fmt.Errorf("unknown period %q (use week, month, quarter, year, all or e.g. 30d)", name)
+		}
+		p.From = now.AddDate(0, 0, -days)
+	}
+	return p, nil
+}
+
+// MemberAwards summarises the links one member received
+type MemberAwards struct {
+	MemberID           string         `json:"member_id"`
+	MemberUsername     string         `json:"member_username"`
+	Total              int            `json:"total"`
+	ByQuality          map[string]int `json:"by_quality"`
+	AvgDaysBetween     float64        `json:"avg_days_between_awards"` // 0 with fewer than two awards
+	LastAwardAt        time.Time      `json:"last_award_at"`
+	DaysSinceLastAward int            `json:"days_since_last_award"`
+}
+
+// OfficerInventory summarises the links one officer entered into inventory
+type OfficerInventory struct {
+	AddedBy   string         `json:"added_by"`
+	Total     int            `json:"total"`
+	ByQuality map[string]int `json:"by_quality"`
+}
+
+// GuildStats is the full statistics report for a period
+type GuildStats struct {
+	Period                 Period             `json:"period"`
+	TotalDistributions     int                `json:"total_distributions"`
+	DistributionsByQuality map[string]int     `json:"distributions_by_quality"`
+	DistributionsPerWeek   float64            `json:"distributions_per_week"`
+	InventoryAdded         int                `json:"inventory_added"`
+	Leaderboard            []MemberAwards     `json:"leaderboard"`
+	InventoryByOfficer     []OfficerInventory `json:"inventory_by_officer"`
+}
+
+// Leaderboard ranks members by links received within the period, optionally
+// limited to one quality. Reversed distributions are not counted.
+func Leaderboard(distributions []*models.Distribution, period Period, quality string, now time.Time) []MemberAwards {
+	byMember := make(map[string][]*models.Distribution)
+	for _, d := range distributions {
+		if d.IsReversed() || !period.Contains(d.DistributedAt) || (quality != "" && d.Quality != quality) {
+			continue
+		}
+		byMember[d.MemberID] = append(byMember[d.MemberID], d)
+	}
+
+	board := make([]MemberAwards, 0, len(byMember))
+	for memberID, awards := range byMember {
+		sort.Slice(awards, func(i, j int) bool {
+			return awards[i].DistributedAt.Before(awards[j].DistributedAt)
+		})
+
+		entry := MemberAwards{
+			MemberID:  memberID,
+			Total:     len(awards),
+			ByQuality: make(map[string]int),
+		}
+		for _, d := range awards {
+			entry.ByQuality[d.Quality]++
+			entry.MemberUsername = d.MemberUsername // keep the most recent name
+		}
+
+		last := awards[len(awards)-1].DistributedAt
+		entry.LastAwardAt = last
+		entry.DaysSinceLastAward = int(now.Sub(last).Hours() / 24)
+		if len(awards) > 1 {
+			span := last.Sub(awards[0].DistributedAt).Hours() / 24
+			entry.AvgDaysBetween = span / float64(len(awards)-1)
+		}
+
+		board = append(board, entry)
+	}
+
+	sort.Slice(board, func(i, j int) bool {
+		if board[i].Total != board[j].Total {
+			return board[i].Total > board[j].Total
+		}
+		return board[i].MemberUsername < board[j].MemberUsername
+	})
+
+	return board
+}
+
+// InventoryByOfficer totals links added within the period per officer,
+// leaving out links made by fusion
+func InventoryByOfficer(links []*models.InventoryLink, period Period) []OfficerInventory {
+	byOfficer := make(map[string]*OfficerInventory)
+	for _, link := range links {
+		// Fusion results were made from links already counted
+		if !period.Contains(link.AddedDate) || link.IsFusionResult() {
+			continue
+		}
+		entry, ok := byOfficer[link.AddedBy]
+		if !ok {
+			entry = &OfficerInventory{AddedBy: link.AddedBy, ByQuality: make(map[string]int)}
+			byOfficer[link.AddedBy] = entry
+		}
+		entry.Total++
+		entry.ByQuality[link.Quality]++
+	}
+
+	result := make([]OfficerInventory, 0, len(byOfficer))
+	for _, entry := range byOfficer {
+		result = append(result, *entry)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Total != result[j].Total {
+			return result[i].Total > result[j].Total
+		}
+		return result[i].AddedBy < result[j].AddedBy
+	})
+
+	return result
+}
+
+// Compute builds the full statistics report for a period
+func Compute(distributions []*models.Distribution, links []*models.InventoryLink, period Period, now time.Time) *GuildStats {
+	result := &GuildStats{
+		Period:                 period,
+		DistributionsByQuality: make(map[string]int),
+		Leaderboard:            Leaderboard(distributions, period, "", now),
+		InventoryByOfficer:     InventoryByOfficer(links, period),
+	}
+
+	earliest := period.From
+	for _, d := range distributions {
+		if d.IsReversed() || !period.Contains(d.DistributedAt) {
+			continue
+		}
+		result.TotalDistributions++
+		result.DistributionsByQuality[d.Quality]++
+		if earliest.IsZero() || d.DistributedAt.Before(earliest) {
+			earliest = d.DistributedAt
+		}
+	}
+
+	for _, entry := range result.InventoryByOfficer {
+		result.InventoryAdded += entry.Total
+	}
+
+	// For "all", measure the rate from the first distribution
+	if !earliest.IsZero() {
+		weeks := now.Sub(earliest).Hours() / (24 * 7)
+		if weeks < 1 {
+			weeks = 1
+		}
+		result.DistributionsPerWeek = float64(result.TotalDistributions) / weeks
+	}
+
+	return result
+}
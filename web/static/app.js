@@ -256,7 +256,7 @@ async function spinWheel() {
         }
 
         // Pick random winner
-        const winnerResponse = await fetch(`${API_BASE}/distribution/pick-winner?list_id=temp`, {
+        const winnerResponse = await adminFetch(`${API_BASE}/distribution/pick-winner?list_id=temp`, {
             method: 'POST'
         });
         const winnerData = await winnerResponse.json();
@@ -396,12 +396,25 @@ function showWinner(winner) {
             <h4>${winner.username}</h4>
             <p><strong>Rank:</strong> ${winner.rank}</p>
             <p><strong>Days in Guild:</strong> ${winner.days_in_guild}</p>
+            ${formatCharacters(winner.characters)}
         </div>
     `;
 
     resultDiv.style.display = 'block';
 }
 
+// Lists a member's in-game characters, primary first
+function formatCharacters(characters) {
+    if (!characters || characters.length === 0) {
+        return '';
+    }
+    const names = characters
+        .slice()
+        .sort((a, b) => b.primary - a.primary)
+        .map(c => c.primary ? `${c.name} (primary)` : c.name);
+    return `<p><strong>Trade to:</strong> ${names.join(', ')}</p>`;
+}
+
 // History functions
 async function loadHistory() {
     try {
@@ -438,6 +451,7 @@ function displayHistory(history) {
             </div>
             <div class="history-details">
                 <p><strong>Link:</strong> ${item.link_type} (${item.bonus})</p>
+                ${item.character ? `<p><strong>Character:</strong> ${item.character}</p>` : ''}
                 <p><strong>Date:</strong> ${new Date(item.distributed_at).toLocaleString()}</p>
                 <p><strong>Method:</strong> ${item.method}</p>
                 <p><strong>Distributed by:</strong> ${item.distributed_by}</p>
@@ -587,7 +601,7 @@ async function handleAddMember(event) {
     };
 
     try {
-        const response = await fetch(`${API_BASE}/member/create`, {
+        const response = await adminFetch(`${API_BASE}/member/create`, {
             method: 'POST',
             headers: {
                 'Content-Type': 'application/json'
@@ -622,7 +636,7 @@ async function handleAddInventory(event) {
     };
 
     try {
-        const response = await fetch(`${API_BASE}/inventory/add`, {
+        const response = await adminFetch(`${API_BASE}/inventory/add`, {
             method: 'POST',
             headers: {
                 'Content-Type': 'application/json'
@@ -654,7 +668,7 @@ async function promoteMember(discordId) {
     }
 
     try {
-        const response = await fetch(`${API_BASE}/member/promote?discord_id=${discordId}`, {
+        const response = await adminFetch(`${API_BASE}/member/promote?discord_id=${discordId}`, {
             method: 'POST'
         });
 
@@ -672,6 +686,31 @@ async function promoteMember(discordId) {
     }
 }
 
+// Officer actions need the API key when the API was deployed with one; ask for
+// it once and keep it in local storage, asking again if it is rejected
+const API_KEY_STORAGE = 'flavaflavApiKey';
+
+async function adminFetch(url, options = {}) {
+    const send = () => {
+        const headers = { ...(options.headers || {}) };
+        const key = localStorage.getItem(API_KEY_STORAGE);
+        if (key) {
+            headers['X-Api-Key'] = key;
+        }
+        return fetch(url, { ...options, headers });
+    };
+
+    let response = await send();
+    if (response.status === 401) {
+        const key = prompt('Enter the FlavaFlav admin API key');
+        if (key) {
+            localStorage.setItem(API_KEY_STORAGE, key);
+            response = await send();
+        }
+    }
+    return response;
+}
+
 // Utility functions
 function getQualityEmoji(quality) {
     switch (quality) {